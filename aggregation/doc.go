@@ -0,0 +1,6 @@
+// Package aggregation describes the result of the aggregation of the news.
+//
+// The result has the filtered news with the report of the parsers, the number of the news and the time
+// of the fetching and the parsing of every source, the numbers of the news before and after every filter
+// and the time of the whole aggregation. The clients render it in the output with the news.
+package aggregation
@@ -0,0 +1,68 @@
+package aggregation
+
+import (
+	"fmt"
+	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
+	"news-aggregator/filter"
+	"news-aggregator/parser"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Result is the result of the aggregation: the filtered news with the report of the
+// parsers and the statistics of the sources and the filters. Collected is the number of the news
+// of the sources before the filters and Duration is the time of the whole aggregation.
+type Result struct {
+	News      []news.News
+	Report    parser.Report
+	Sources   []SourceStats
+	Filters   []FilterStats
+	Collected int
+	Duration  time.Duration
+}
+
+// SourceStats is the statistics of the single source: the state of its collection, the number
+// of its news and the time of its fetching and parsing.
+type SourceStats struct {
+	Name     source.Name
+	State    parser.State
+	Articles int
+	Duration time.Duration
+	Error    string
+}
+
+// FilterStats is the number of the news before and after the filter and the time of the filtering.
+type FilterStats struct {
+	Filter   string
+	Before   int
+	After    int
+	Duration time.Duration
+}
+
+// Warnings returns the descriptions of the sources which are not collected.
+func (result Result) Warnings() []string {
+	return result.Report.Warnings()
+}
+
+// NewSourceStats returns the statistics of the sources of the report sorted by the source name.
+func NewSourceStats(report parser.Report) []SourceStats {
+	stats := make([]SourceStats, 0, len(report.Sources))
+	for name, status := range report.Sources {
+		stats = append(stats, SourceStats{
+			Name:     name,
+			State:    status.State,
+			Articles: status.Articles,
+			Duration: status.Duration,
+			Error:    status.Error,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// FilterName returns the name of the type of the filter like filter.ByKeyword.
+func FilterName(newsFilter filter.NewsFilter) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", newsFilter), "*")
+}
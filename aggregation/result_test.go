@@ -0,0 +1,45 @@
+package aggregation
+
+import (
+	"news-aggregator/entity/source"
+	"news-aggregator/filter"
+	"news-aggregator/parser"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSourceStats(t *testing.T) {
+	report := parser.Report{Sources: map[source.Name]parser.SourceStatus{
+		"nbc": {State: parser.TimedOut, Duration: time.Second, Error: "context deadline exceeded"},
+		"bbc": {State: parser.Collected, Articles: 3, Duration: time.Millisecond},
+	}}
+
+	expected := []SourceStats{
+		{Name: "bbc", State: parser.Collected, Articles: 3, Duration: time.Millisecond},
+		{Name: "nbc", State: parser.TimedOut, Duration: time.Second, Error: "context deadline exceeded"},
+	}
+	assert.Equal(t, expected, NewSourceStats(report))
+	assert.Empty(t, NewSourceStats(parser.Report{}))
+
+	result := Result{Report: report, Sources: expected}
+	assert.Equal(t, []string{"source nbc timeout: context deadline exceeded"}, result.Warnings())
+}
+
+func TestFilterName(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   filter.NewsFilter
+		expected string
+	}{
+		{name: "Filter value", filter: filter.ByKeyword{}, expected: "filter.ByKeyword"},
+		{name: "Filter pointer", filter: &filter.ByDate{}, expected: "filter.ByDate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FilterName(tt.filter))
+		})
+	}
+}
@@ -1,13 +1,17 @@
 package aggregator
 
 import (
+	"context"
 	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
+	"news-aggregator/parser"
 )
 
 // Collector is using for fetching news from source by source name.
 //
 //go:generate mockgen -source=collector.go -destination=mock_aggregator/mock_collector.go -package=aggregator news-aggregator/aggregator Collector
 type Collector interface {
-	FindNewsByResourcesName(sourcesNames []source.Name) ([]news.News, error)
+	// FindNewsByResourcesName returns the news of the passed sources with the report
+	// of the items which the parsers skipped or repaired.
+	FindNewsByResourcesName(ctx context.Context, sourcesNames []source.Name) ([]news.News, parser.Report, error)
 }
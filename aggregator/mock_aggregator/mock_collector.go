@@ -5,8 +5,10 @@
 package aggregator
 
 import (
+	context "context"
 	news "news-aggregator/entity/news"
 	source "news-aggregator/entity/source"
+	parser "news-aggregator/parser"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -36,16 +38,17 @@ func (m *MockCollector) EXPECT() *MockCollectorMockRecorder {
 }
 
 // FindNewsByResourcesName mocks base method.
-func (m *MockCollector) FindNewsByResourcesName(sourcesNames []source.Name) ([]news.News, error) {
+func (m *MockCollector) FindNewsByResourcesName(ctx context.Context, sourcesNames []source.Name) ([]news.News, parser.Report, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindNewsByResourcesName", sourcesNames)
+	ret := m.ctrl.Call(m, "FindNewsByResourcesName", ctx, sourcesNames)
 	ret0, _ := ret[0].([]news.News)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(parser.Report)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // FindNewsByResourcesName indicates an expected call of FindNewsByResourcesName.
-func (mr *MockCollectorMockRecorder) FindNewsByResourcesName(sourcesNames interface{}) *gomock.Call {
+func (mr *MockCollectorMockRecorder) FindNewsByResourcesName(ctx, sourcesNames interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindNewsByResourcesName", reflect.TypeOf((*MockCollector)(nil).FindNewsByResourcesName), sourcesNames)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindNewsByResourcesName", reflect.TypeOf((*MockCollector)(nil).FindNewsByResourcesName), ctx, sourcesNames)
 }
@@ -1,11 +1,14 @@
 package aggregator
 
 import (
+	"context"
+	"news-aggregator/aggregation"
 	"news-aggregator/client"
-	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
 	"news-aggregator/filter"
+	"news-aggregator/pagination"
 	"news-aggregator/validator"
+	"time"
 )
 
 // newsAggregator provides methods for aggregating news from various sources.
@@ -21,33 +24,74 @@ func New(newsCollector Collector) client.Aggregator {
 // Aggregate fetches news from the provided sources, applies the given
 // filters, and returns the filtered news.
 // Parameters:
-// - sources: a slice of strings representing the names of the sources to fetch news from.
+// - ctx: the context of the aggregation, the collecting and the filtering stop with its error when it is done.
+// - sources: a slice of strings representing the names of the sources to fetch news from,
+// "all", "tag:<tag>" or the glob patterns of the names.
 // - filters: a variadic parameter of filter.Service to apply filters to the fetched news.
 //
 // Returns:
-// - A slice of news that have been fetched and filtered.
-// - An error message string if any errors occurred during the process.
+// - The result with the news that have been fetched and filtered, the report of the skipped and
+// repaired items, the number of the news and the time of the parsing of every source and the numbers
+// of the news before and after every filter. The news of the healthy sources are returned when the
+// other sources fail, the report contains the status of every source and the warnings of the failed ones.
+// - An error message string if any errors occurred during the process or none of the sources is collected.
 
-func (aggregator *newsAggregator) Aggregate(sources []string, filters ...filter.NewsFilter) ([]news.News, error) {
-	var sourceNames []source.Name
-
-	for _, name := range sources {
-		sourceNames = append(sourceNames, source.Name(name))
+func (aggregator *newsAggregator) Aggregate(ctx context.Context, sources []string, filters ...filter.NewsFilter) (aggregation.Result, error) {
+	start := time.Now()
+	if err := ctx.Err(); err != nil {
+		return aggregation.Result{}, err
+	}
+	resolvedSources, err := validator.ResolveSources(sources)
+	if err != nil {
+		return aggregation.Result{}, err
 	}
 
-	validateSource, err := validator.ValidateSource(sources)
-	if !validateSource {
-		return nil, err
+	var sourceNames []source.Name
+	for _, name := range resolvedSources {
+		sourceNames = append(sourceNames, source.Name(name))
 	}
 
-	news, err := aggregator.newsCollector.FindNewsByResourcesName(sourceNames)
+	news, report, err := aggregator.newsCollector.FindNewsByResourcesName(ctx, sourceNames)
+	result := aggregation.Result{Report: report, Sources: aggregation.NewSourceStats(report), Collected: len(news)}
 	if err != nil {
-		return nil, err
+		result.Duration = time.Since(start)
+		return result, err
 	}
 
 	for _, f := range filters {
+		if err := ctx.Err(); err != nil {
+			result.Duration = time.Since(start)
+			return result, err
+		}
+		filterStart := time.Now()
+		before := len(news)
 		news = f.Filter(news)
+		result.Filters = append(result.Filters, aggregation.FilterStats{
+			Filter:   aggregation.FilterName(f),
+			Before:   before,
+			After:    len(news),
+			Duration: time.Since(filterStart),
+		})
 	}
 
-	return news, nil
+	result.News = news
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// AggregatePage fetches and filters the news like Aggregate, sorts them by the Order of the request
+// and returns the page of them requested by the limit and the offset or the cursor of the request.
+// The page has the total number of the filtered news and the cursors of the next and the previous
+// pages, which are kept stable when the new news are collected. The result has all filtered news.
+func (aggregator *newsAggregator) AggregatePage(ctx context.Context, sources []string, request pagination.Request, filters ...filter.NewsFilter) (pagination.Page, aggregation.Result, error) {
+	result, err := aggregator.Aggregate(ctx, sources, filters...)
+	if err != nil {
+		return pagination.Page{}, result, err
+	}
+	page, err := pagination.Paginate(result.News, request)
+	if err != nil {
+		return pagination.Page{}, result, err
+	}
+	result.News = page.News
+	return page, result, nil
 }
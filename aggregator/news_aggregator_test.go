@@ -1,12 +1,17 @@
 package aggregator
 
 import (
+	"context"
+	"errors"
 	"github.com/golang/mock/gomock"
+	"news-aggregator/aggregation"
 	aggregator "news-aggregator/aggregator/mock_aggregator"
 	"news-aggregator/constant"
 	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
 	"news-aggregator/filter"
+	"news-aggregator/pagination"
+	"news-aggregator/parser"
 	"reflect"
 	"testing"
 	"time"
@@ -27,6 +32,7 @@ func TestNews_Aggregate(t *testing.T) {
 		args         args
 		setup        func()
 		wantQuantity int
+		wantWarnings int
 		wantErr      bool
 	}{
 		{
@@ -39,10 +45,10 @@ func TestNews_Aggregate(t *testing.T) {
 				}},
 			},
 			setup: func() {
-				mockCollector.EXPECT().FindNewsByResourcesName([]source.Name{"bbc", "nbc"}).
+				mockCollector.EXPECT().FindNewsByResourcesName(gomock.Any(), []source.Name{"bbc", "nbc"}).
 					Return([]news.News{
 						{Title: "Test Title", Description: "Test Description", Link: "http://test.com", Date: time.Date(2024, time.May, 18, 0, 0, 0, 0, time.UTC)},
-					}, nil)
+					}, parser.Report{}, nil)
 			},
 			wantQuantity: 1,
 			wantErr:      false,
@@ -56,11 +62,11 @@ func TestNews_Aggregate(t *testing.T) {
 				}},
 			},
 			setup: func() {
-				mockCollector.EXPECT().FindNewsByResourcesName([]source.Name{"bbc"}).
+				mockCollector.EXPECT().FindNewsByResourcesName(gomock.Any(), []source.Name{"bbc"}).
 					Return([]news.News{
 						{Title: "Trump News 1", Description: "Description 1", Link: "http://test1.com", Date: time.Now()},
 						{Title: "Trump News 2", Description: "Description 2", Link: "http://test2.com", Date: time.Now()},
-					}, nil)
+					}, parser.Report{}, nil)
 			},
 			wantQuantity: 2,
 			wantErr:      false,
@@ -74,13 +80,13 @@ func TestNews_Aggregate(t *testing.T) {
 				}},
 			},
 			setup: func() {
-				mockCollector.EXPECT().FindNewsByResourcesName([]source.Name{"usatoday"}).
+				mockCollector.EXPECT().FindNewsByResourcesName(gomock.Any(), []source.Name{"usatoday"}).
 					Return([]news.News{
 						{Title: "Ukraine News 1", Description: "Description 1", Link: "http://test1.com", Date: time.Now()},
 						{Title: "Ukraine News 2", Description: "Description 2", Link: "http://test2.com", Date: time.Now()},
 						{Title: "Ukraine News 3", Description: "Description 3", Link: "http://test3.com", Date: time.Now()},
 						{Title: "Ukraine News 4", Description: "Description 4", Link: "http://test4.com", Date: time.Now()},
-					}, nil)
+					}, parser.Report{}, nil)
 			},
 			wantQuantity: 4,
 			wantErr:      false,
@@ -94,14 +100,33 @@ func TestNews_Aggregate(t *testing.T) {
 				}},
 			},
 			setup: func() {
-				mockCollector.EXPECT().FindNewsByResourcesName([]source.Name{"nbc"}).
+				mockCollector.EXPECT().FindNewsByResourcesName(gomock.Any(), []source.Name{"nbc"}).
 					Return([]news.News{
 						{Title: "Ukraine News from NBC", Description: "Description", Link: "http://test.com", Date: time.Now()},
-					}, nil)
+					}, parser.Report{}, nil)
 			},
 			wantQuantity: 1,
 			wantErr:      false,
 		},
+		{
+			name: "Test with partial news when one of the sources failed",
+			args: args{
+				sources: []string{"bbc", "nbc"},
+				filters: nil,
+			},
+			setup: func() {
+				mockCollector.EXPECT().FindNewsByResourcesName(gomock.Any(), []source.Name{"bbc", "nbc"}).
+					Return([]news.News{
+						{Title: "BBC News", Description: "Description", Link: "http://test.com", Date: time.Now()},
+					}, parser.Report{Sources: map[source.Name]parser.SourceStatus{
+						"bbc": {State: parser.Collected, Articles: 1},
+						"nbc": {State: parser.TimedOut, Error: "context deadline exceeded"},
+					}}, nil)
+			},
+			wantQuantity: 1,
+			wantWarnings: 1,
+			wantErr:      false,
+		},
 		{
 			name: "Test with non-existent sources",
 			args: args{
@@ -129,18 +154,74 @@ func TestNews_Aggregate(t *testing.T) {
 				tt.setup()
 			}
 			na := New(mockCollector)
-			got, err := na.Aggregate(tt.args.sources, tt.args.filters...)
+			got, err := na.Aggregate(context.Background(), tt.args.sources, tt.args.filters...)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Aggregate() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(len(got), tt.wantQuantity) {
-				t.Errorf("Aggregate() got = %v, wantQuantity %v", len(got), tt.wantQuantity)
+			if !reflect.DeepEqual(len(got.News), tt.wantQuantity) {
+				t.Errorf("Aggregate() got = %v, wantQuantity %v", len(got.News), tt.wantQuantity)
+			}
+			if len(got.Warnings()) != tt.wantWarnings {
+				t.Errorf("Aggregate() warnings = %v, wantWarnings %v", got.Warnings(), tt.wantWarnings)
+			}
+			if len(got.Filters) != len(tt.args.filters) {
+				t.Errorf("Aggregate() filter stats = %v, want the stats of %d filters", got.Filters, len(tt.args.filters))
 			}
 		})
 	}
 }
 
+func TestNews_AggregatePage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCollector := aggregator.NewMockCollector(ctrl)
+	collected := []news.News{
+		{Title: "First", Link: "https://example.com/1", Date: parseDate("2024-05-17")},
+		{Title: "Second", Link: "https://example.com/2", Date: parseDate("2024-05-18")},
+		{Title: "Third", Link: "https://example.com/3", Date: parseDate("2024-05-19")},
+	}
+	mockCollector.EXPECT().FindNewsByResourcesName(gomock.Any(), []source.Name{"bbc"}).
+		Return(collected, parser.Report{}, nil).Times(2)
+
+	newest := func(articles []news.News) ([]news.News, error) {
+		sorted := make([]news.News, 0, len(articles))
+		for i := len(articles) - 1; i >= 0; i-- {
+			sorted = append(sorted, articles[i])
+		}
+		return sorted, nil
+	}
+	na := New(mockCollector)
+	page, result, err := na.AggregatePage(context.Background(), []string{"bbc"}, pagination.Request{Limit: 2, Order: newest},
+		filter.ByDate{StartDate: parseDate("2024-05-18")})
+	if err != nil {
+		t.Fatalf("AggregatePage() error = %v", err)
+	}
+	if page.Total != 2 || len(page.News) != 2 || page.News[0].Title != "Third" || page.Next != "" {
+		t.Errorf("AggregatePage() got = %+v, want the filtered news from the newest on one page", page)
+	}
+	wantFilters := []aggregation.FilterStats{{Filter: "filter.ByDate", Before: 3, After: 2}}
+	for i := range result.Filters {
+		result.Filters[i].Duration = 0
+	}
+	if result.Collected != 3 || !reflect.DeepEqual(result.Filters, wantFilters) || len(result.News) != 2 {
+		t.Errorf("AggregatePage() result = %+v, want 3 collected news and the stats of the date filter", result)
+	}
+
+	_, _, err = na.AggregatePage(context.Background(), []string{"bbc"}, pagination.Request{Cursor: "%%%"})
+	if err == nil {
+		t.Errorf("AggregatePage() with the invalid cursor, expected error")
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = na.AggregatePage(cancelled, []string{"bbc"}, pagination.Request{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("AggregatePage() with the cancelled context error = %v, want %v", err, context.Canceled)
+	}
+}
+
 func parseDate(dateStr string) time.Time {
 	date, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
@@ -0,0 +1,130 @@
+package analysis
+
+import (
+	"github.com/reiver/go-porterstemmer"
+	"slices"
+	"strings"
+)
+
+// Words returns the lowercase words of the text. The apostrophes inside the words are kept.
+func Words(text string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), isNotWordCharacter) {
+		if word = strings.Trim(word, "'’ʼ"); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// Stem returns the stem of the lowercase word by the stemmer of the language. The word in
+// the other script than the script of the language, or the word of the unknown language,
+// is stemmed by the stemmer of the language detected from the word.
+func Stem(language Language, word string) string {
+	cyrillic := isCyrillic(word)
+	if language == "" || cyrillic != (language == Ukrainian || language == Russian) {
+		language = DetectLanguage(word)
+	}
+	switch language {
+	case Ukrainian:
+		return stemUkrainian(word)
+	case Russian:
+		return stemRussian(word)
+	case German:
+		return stemGerman(word)
+	default:
+		return porterstemmer.StemString(word)
+	}
+}
+
+// Terms returns the stems of the words of the text without the stopwords. The language
+// is detected from the text if it is empty.
+func Terms(text string, language Language) []string {
+	if language == "" {
+		language = DetectLanguage(text)
+	}
+	var terms []string
+	for _, word := range Words(text) {
+		if !IsStopword(language, word) {
+			terms = append(terms, Stem(language, word))
+		}
+	}
+	return terms
+}
+
+// Stems returns the unique stems of the lowercase word in every language of its script.
+func Stems(word string) []string {
+	var stems []string
+	for _, language := range candidateLanguages(word) {
+		if stem := Stem(language, word); !slices.Contains(stems, stem) {
+			stems = append(stems, stem)
+		}
+	}
+	return stems
+}
+
+// KeywordVariants returns the stems of the keyword and its synonyms in every language of their
+// script, like English and German for the Latin words. The stems of the words of the phrase
+// are joined by the space. The keyword is expanded by the dictionary of synonyms, which may be nil.
+func KeywordVariants(keyword string, synonyms *Synonyms) []string {
+	var variants []string
+	seen := make(map[string]struct{})
+	for _, expanded := range synonyms.Expand(keyword) {
+		words := Words(expanded)
+		if len(words) == 0 {
+			continue
+		}
+		for _, language := range candidateLanguages(expanded) {
+			stems := make([]string, len(words))
+			for i, word := range words {
+				stems[i] = Stem(language, word)
+			}
+			variant := strings.Join(stems, " ")
+			if _, exists := seen[variant]; !exists {
+				seen[variant] = struct{}{}
+				variants = append(variants, variant)
+			}
+		}
+	}
+	return variants
+}
+
+// QueryTerms returns the unique stems of the words of the keywords and their synonyms in every
+// language of their script. The words which are the stopwords in any of these languages are
+// skipped unless the keyword has only the stopwords. The keywords are expanded by the dictionary
+// of synonyms, which may be nil.
+func QueryTerms(synonyms *Synonyms, keywords ...string) []string {
+	var terms []string
+	seen := make(map[string]struct{})
+	for _, keyword := range keywords {
+		for _, expanded := range synonyms.Expand(keyword) {
+			languages := candidateLanguages(expanded)
+			words := meaningfulWords(Words(expanded), languages)
+			for _, language := range languages {
+				for _, word := range words {
+					term := Stem(language, word)
+					if _, exists := seen[term]; !exists {
+						seen[term] = struct{}{}
+						terms = append(terms, term)
+					}
+				}
+			}
+		}
+	}
+	return terms
+}
+
+// meaningfulWords returns the words which are not the stopwords in any of the languages,
+// or all words if every word is the stopword.
+func meaningfulWords(words []string, languages []Language) []string {
+	var meaningful []string
+	for _, word := range words {
+		if !slices.ContainsFunc(languages, func(language Language) bool { return IsStopword(language, word) }) {
+			meaningful = append(meaningful, word)
+		}
+	}
+	if len(meaningful) == 0 {
+		return words
+	}
+	return meaningful
+}
@@ -0,0 +1,108 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		name     string
+		language Language
+		word     string
+		want     string
+	}{
+		{name: "English word", language: English, word: "running", want: "run"},
+		{name: "German plural with umlaut", language: German, word: "häuser", want: "haus"},
+		{name: "German derivational suffix", language: German, word: "freundlichkeit", want: "freundlich"},
+		{name: "German sharp s", language: German, word: "straße", want: "strass"},
+		{name: "Ukrainian noun", language: Ukrainian, word: "новини", want: "новин"},
+		{name: "Ukrainian adjective", language: Ukrainian, word: "українських", want: "українськ"},
+		{name: "Ukrainian apostrophe is removed", language: Ukrainian, word: "об'єкти", want: "обєкт"},
+		{name: "Russian adjective", language: Russian, word: "важная", want: "важн"},
+		{name: "Russian participle", language: Russian, word: "бегавшая", want: "бега"},
+		{name: "Russian noun", language: Russian, word: "книгами", want: "книг"},
+		{name: "Cyrillic word of the Latin language", language: English, word: "міста", want: "міст"},
+		{name: "Latin word of the Cyrillic language", language: Ukrainian, word: "running", want: "run"},
+		{name: "Unknown language", language: "", word: "україні", want: "україн"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Stem(tt.language, tt.word); got != tt.want {
+				t.Errorf("Stem() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		language Language
+		want     []string
+	}{
+		{name: "English stopwords are removed", text: "The president met with the ministers", language: English,
+			want: []string{"presid", "met", "minist"}},
+		{name: "German text", text: "Die Regierung hat die neuen Gesetze beschlossen", language: German,
+			want: []string{"regier", "neu", "gesetz", "beschloss"}},
+		{name: "Detected Ukrainian text", text: "Президент України зустрівся з міністрами у Києві",
+			want: []string{"президент", "україн", "зустр", "міністр", "києв"}},
+		{name: "Detected Russian text", text: "Президент России встретился с министрами в Москве",
+			want: []string{"президент", "росс", "встрет", "министр", "москв"}},
+		{name: "Empty text", text: "", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Terms(tt.text, tt.language); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Terms() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeywordVariants(t *testing.T) {
+	synonyms := NewSynonyms([][]string{{"Kyiv", "Kiev", "Київ"}})
+
+	tests := []struct {
+		name     string
+		keyword  string
+		synonyms *Synonyms
+		want     []string
+	}{
+		{name: "Keyword with synonyms", keyword: "Kyiv", synonyms: synonyms, want: []string{"kyiv", "kiev", "ки", "київ"}},
+		{name: "Keyword without dictionary", keyword: "Kyiv", want: []string{"kyiv"}},
+		{name: "Latin keyword is stemmed in English and German", keyword: "kinder", synonyms: synonyms, want: []string{"kinder", "kind"}},
+		{name: "Phrase", keyword: "air alerts", synonyms: synonyms, want: []string{"air alert"}},
+		{name: "Empty keyword", keyword: " ", synonyms: synonyms, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KeywordVariants(tt.keyword, tt.synonyms); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("KeywordVariants() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryTerms(t *testing.T) {
+	synonyms := NewSynonyms([][]string{{"kyiv", "київ", "києві"}})
+
+	tests := []struct {
+		name     string
+		keywords []string
+		want     []string
+	}{
+		{name: "Stopwords are skipped", keywords: []string{"the attack"}, want: []string{"attack"}},
+		{name: "Keyword of the stopwords", keywords: []string{"the"}, want: []string{"the"}},
+		{name: "Synonyms in other languages", keywords: []string{"Kyiv"}, want: []string{"kyiv", "ки", "київ", "києв", "києві"}},
+		{name: "Duplicate terms", keywords: []string{"attack", "attacks"}, want: []string{"attack"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := QueryTerms(synonyms, tt.keywords...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("QueryTerms() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
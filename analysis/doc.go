@@ -0,0 +1,11 @@
+// Package analysis provides the language-aware analysis of the text of the news for the filters
+// and the search.
+//
+// The language of the news is taken from its source or detected from its text by DetectLanguage.
+// Terms splits the text into the words, drops the stopwords of the language and reduces the words
+// to their stems: the Porter stemmer is used for English and the Snowball-style stemmers are used
+// for German, Ukrainian and Russian. The keywords are expanded by the dictionary of Synonyms which
+// is passed by the filters and the search index, like "Kyiv", "Kiev" and "Київ", and stemmed in
+// every language of their script, so KeywordVariants and QueryTerms match the news in any of the
+// supported languages.
+package analysis
@@ -0,0 +1,85 @@
+package analysis
+
+import "strings"
+
+const germanVowels = "aeiouyäöü"
+
+var (
+	germanStep1 = []suffixGroup{
+		{endings: []string{"em", "ern", "er", "e", "en", "es"}},
+		{endings: []string{"s"}, preceding: "bdfghklmnrt"},
+	}
+	germanStep2 = []suffixGroup{
+		{endings: []string{"en", "er", "est"}},
+		{endings: []string{"st"}, preceding: "bdfghklmnt"},
+	}
+)
+
+// stemGerman is the Snowball stemmer of the German words.
+func stemGerman(word string) string {
+	runes := []rune(strings.ReplaceAll(word, "ß", "ss"))
+	isVowel := func(r rune) bool { return strings.ContainsRune(germanVowels, r) }
+	// The u and y between the vowels are marked as the consonants U and Y.
+	for i := 1; i < len(runes)-1; i++ {
+		if (runes[i] == 'u' || runes[i] == 'y') && isVowel(runes[i-1]) && isVowel(runes[i+1]) {
+			runes[i] = runes[i] - 'a' + 'A'
+		}
+	}
+	_, r1, r2 := regions(runes, germanVowels)
+	r1 = max(r1, 3)
+
+	if length, ok := longestEnding(runes, r1, germanStep1...); ok {
+		suffix := string(runes[len(runes)-length:])
+		runes = runes[:len(runes)-length]
+		if (suffix == "e" || suffix == "en" || suffix == "es") && hasSuffixFrom(runes, 0, []rune("niss")) {
+			runes = runes[:len(runes)-1]
+		}
+	}
+
+	// The ending "st" is removed only if at least three letters precede its preceding letter.
+	if length, ok := longestEnding(runes, r1, germanStep2...); ok {
+		if string(runes[len(runes)-length:]) != "st" || len(runes)-length-1 >= 3 {
+			runes = runes[:len(runes)-length]
+		}
+	}
+
+	runes = removeGermanDerivational(runes, r1, r2)
+
+	replacer := strings.NewReplacer("U", "u", "Y", "y", "ä", "a", "ö", "o", "ü", "u")
+	return replacer.Replace(string(runes))
+}
+
+// removeGermanDerivational removes the derivational suffixes of the step 3 in R2.
+func removeGermanDerivational(runes []rune, r1, r2 int) []rune {
+	ending := func(suffix string) bool { return hasSuffixFrom(runes, r2, []rune(suffix)) }
+	precededBy := func(length int, letter rune) bool {
+		position := len(runes) - length - 1
+		return position >= 0 && runes[position] == letter
+	}
+	switch {
+	case ending("end") || ending("ung"):
+		runes = runes[:len(runes)-3]
+		if ending("ig") && !precededBy(2, 'e') {
+			runes = runes[:len(runes)-2]
+		}
+	case (ending("isch") && !precededBy(4, 'e')) || ((ending("ig") || ending("ik")) && !precededBy(2, 'e')):
+		if ending("isch") {
+			runes = runes[:len(runes)-4]
+		} else {
+			runes = runes[:len(runes)-2]
+		}
+	case ending("lich") || ending("heit"):
+		runes = runes[:len(runes)-4]
+		if hasSuffixFrom(runes, r1, []rune("er")) || hasSuffixFrom(runes, r1, []rune("en")) {
+			runes = runes[:len(runes)-2]
+		}
+	case ending("keit"):
+		runes = runes[:len(runes)-4]
+		if ending("lich") {
+			runes = runes[:len(runes)-4]
+		} else if ending("ig") {
+			runes = runes[:len(runes)-2]
+		}
+	}
+	return runes
+}
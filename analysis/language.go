@@ -0,0 +1,118 @@
+package analysis
+
+import (
+	"news-aggregator/entity/news"
+	"strings"
+	"unicode"
+)
+
+// Language is the code of the language of the text in ISO 639-1.
+type Language string
+
+// The languages with the stemmers and the stopwords.
+const (
+	English   Language = "en"
+	German    Language = "de"
+	Ukrainian Language = "uk"
+	Russian   Language = "ru"
+)
+
+// ParseLanguage returns the supported language of the code like "uk", "uk-UA" or "en_US".
+// The empty language is returned for the unsupported codes.
+func ParseLanguage(code string) Language {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if separator := strings.IndexAny(code, "-_"); separator != -1 {
+		code = code[:separator]
+	}
+	switch code {
+	case "en", "eng":
+		return English
+	case "de", "deu", "ger":
+		return German
+	case "uk", "ua", "ukr":
+		return Ukrainian
+	case "ru", "rus":
+		return Russian
+	}
+	return ""
+}
+
+// LanguageOf returns the language of the news set by its source, or the language detected
+// from its title and description.
+func LanguageOf(article news.News) Language {
+	if language := ParseLanguage(article.Language); language != "" {
+		return language
+	}
+	return DetectLanguage(string(article.Title) + " " + string(article.Description))
+}
+
+// DetectLanguage guesses the language of the text by its script, the letters which are
+// used only in Ukrainian or Russian, the German letters and the stopwords of the languages.
+// The Cyrillic text without the distinctive letters and stopwords is considered Ukrainian.
+func DetectLanguage(text string) Language {
+	var cyrillic, latin, ukrainian, russian, german int
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+			if strings.ContainsRune("іїєґ", r) {
+				ukrainian++
+			} else if strings.ContainsRune("ыэёъ", r) {
+				russian++
+			}
+		case unicode.Is(unicode.Latin, r):
+			latin++
+			if strings.ContainsRune("äöüß", r) {
+				german++
+			}
+		}
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), isNotWordCharacter)
+	if cyrillic > latin {
+		ukrainian += countStopwords(Ukrainian, words)
+		russian += countStopwords(Russian, words)
+		if russian > ukrainian {
+			return Russian
+		}
+		return Ukrainian
+	}
+	german += countStopwords(German, words)
+	if german > countStopwords(English, words) {
+		return German
+	}
+	return English
+}
+
+// candidateLanguages returns the languages which may have the word: English and German for
+// the Latin words and Ukrainian and Russian for the Cyrillic words.
+func candidateLanguages(word string) []Language {
+	if isCyrillic(word) {
+		return []Language{Ukrainian, Russian}
+	}
+	return []Language{English, German}
+}
+
+// isCyrillic reports whether the first letter of the word is Cyrillic.
+func isCyrillic(word string) bool {
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			return unicode.Is(unicode.Cyrillic, r)
+		}
+	}
+	return false
+}
+
+func countStopwords(language Language, words []string) int {
+	count := 0
+	for _, word := range words {
+		if IsStopword(language, word) {
+			count++
+		}
+	}
+	return count
+}
+
+func isNotWordCharacter(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '’' && r != 'ʼ'
+}
@@ -0,0 +1,83 @@
+package analysis
+
+import (
+	"news-aggregator/entity/news"
+	"testing"
+)
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		code string
+		want Language
+	}{
+		{code: "en", want: English},
+		{code: "en-GB", want: English},
+		{code: "deu", want: German},
+		{code: " UK ", want: Ukrainian},
+		{code: "ru_RU", want: Russian},
+		{code: "fr", want: ""},
+		{code: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := ParseLanguage(tt.code); got != tt.want {
+				t.Errorf("ParseLanguage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Language
+	}{
+		{name: "English", text: "The president met with the ministers", want: English},
+		{name: "German by the stopwords", text: "Die Regierung hat die neuen Gesetze beschlossen", want: German},
+		{name: "German by the letters", text: "Häuser", want: German},
+		{name: "Ukrainian by the letters", text: "Київ", want: Ukrainian},
+		{name: "Russian by the letters", text: "Москва объявила", want: Russian},
+		{name: "Russian by the stopwords", text: "Он сказал, что это не так", want: Russian},
+		{name: "Cyrillic without the distinctive letters", text: "Президент", want: Ukrainian},
+		{name: "Text without letters", text: "2024", want: English},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectLanguage(tt.text); got != tt.want {
+				t.Errorf("DetectLanguage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLanguageOf(t *testing.T) {
+	if got := LanguageOf(news.News{Title: "Київ", Language: "de"}); got != German {
+		t.Errorf("LanguageOf() = %v, expected the language of the source", got)
+	}
+	if got := LanguageOf(news.News{Title: "Новини з Києва", Language: "fr"}); got != Ukrainian {
+		t.Errorf("LanguageOf() = %v, expected the detected language", got)
+	}
+}
+
+func TestIsStopword(t *testing.T) {
+	tests := []struct {
+		language Language
+		word     string
+		want     bool
+	}{
+		{language: English, word: "the", want: true},
+		{language: German, word: "und", want: true},
+		{language: Ukrainian, word: "і", want: true},
+		{language: Russian, word: "и", want: true},
+		{language: Ukrainian, word: "київ", want: false},
+		{language: "", word: "the", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.language)+" "+tt.word, func(t *testing.T) {
+			if got := IsStopword(tt.language, tt.word); got != tt.want {
+				t.Errorf("IsStopword() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package analysis
+
+import "strings"
+
+const russianVowels = "аеиоуыэюя"
+
+var (
+	russianPerfectiveGerund = []suffixGroup{
+		{endings: []string{"в", "вши", "вшись"}, preceding: "ая"},
+		{endings: []string{"ив", "ивши", "ившись", "ыв", "ывши", "ывшись"}},
+	}
+	russianAdjective = []suffixGroup{
+		{endings: []string{"ее", "ие", "ые", "ое", "ими", "ыми", "ей", "ий", "ый", "ой", "ем", "им", "ым", "ом",
+			"его", "ого", "ему", "ому", "их", "ых", "ую", "юю", "ая", "яя", "ою", "ею"}},
+	}
+	russianParticiple = []suffixGroup{
+		{endings: []string{"ем", "нн", "вш", "ющ", "щ"}, preceding: "ая"},
+		{endings: []string{"ивш", "ывш", "ующ"}},
+	}
+	russianReflexive = []suffixGroup{
+		{endings: []string{"ся", "сь"}},
+	}
+	russianVerb = []suffixGroup{
+		{endings: []string{"ла", "на", "ете", "йте", "ли", "й", "л", "ем", "н", "ло", "но", "ет", "ют", "ны",
+			"ть", "ешь", "нно"}, preceding: "ая"},
+		{endings: []string{"ила", "ыла", "ена", "ейте", "уйте", "ите", "или", "ыли", "ей", "уй", "ил", "ыл", "им",
+			"ым", "ен", "ило", "ыло", "ено", "ят", "ует", "уют", "ит", "ыт", "ены", "ить", "ыть", "ишь", "ую", "ю"}},
+	}
+	russianNoun = []suffixGroup{
+		{endings: []string{"а", "ев", "ов", "ие", "ье", "е", "иями", "ями", "ами", "еи", "ии", "и", "ией", "ей",
+			"ой", "ий", "й", "иям", "ям", "ием", "ем", "ам", "ом", "о", "у", "ах", "иях", "ях", "ы", "ь", "ию",
+			"ью", "ю", "ия", "ья", "я"}},
+	}
+	russianDerivational = []suffixGroup{{endings: []string{"ост", "ость"}}}
+	russianSuperlative  = []suffixGroup{{endings: []string{"ейш", "ейше"}}}
+)
+
+// stemRussian is the Snowball stemmer of the Russian words.
+func stemRussian(word string) string {
+	runes := []rune(strings.ReplaceAll(word, "ё", "е"))
+	rv, _, r2 := regions(runes, russianVowels)
+
+	if !removeEnding(&runes, rv, russianPerfectiveGerund...) {
+		removeEnding(&runes, rv, russianReflexive...)
+		if removeEnding(&runes, rv, russianAdjective...) {
+			removeEnding(&runes, rv, russianParticiple...)
+		} else if !removeEnding(&runes, rv, russianVerb...) {
+			removeEnding(&runes, rv, russianNoun...)
+		}
+	}
+
+	removeEnding(&runes, rv, suffixGroup{endings: []string{"и"}})
+	removeEnding(&runes, max(rv, r2), russianDerivational...)
+
+	switch {
+	case hasSuffixFrom(runes, rv, []rune("нн")):
+		runes = runes[:len(runes)-1]
+	case removeEnding(&runes, rv, russianSuperlative...):
+		if hasSuffixFrom(runes, rv, []rune("нн")) {
+			runes = runes[:len(runes)-1]
+		}
+	default:
+		removeEnding(&runes, rv, suffixGroup{endings: []string{"ь"}})
+	}
+	return string(runes)
+}
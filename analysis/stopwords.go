@@ -0,0 +1,34 @@
+package analysis
+
+import "strings"
+
+// stopwords are the most frequent words of the languages which do not help to find the news.
+var stopwords = map[Language]map[string]struct{}{
+	English: wordSet(`a about after all also an and any are as at be because been before but by can could
+		did do does for from had has have he her his how i if in into is it its just more most my no not of on
+		one only or other our out over she so some than that the their them then there these they this to
+		up was we were what when where which who will with would you your`),
+	German: wordSet(`aber alle als also am an auch auf aus bei bin bis da damit dann das dass dem den der des
+		die dies doch dort du durch ein eine einem einen einer eines er es für hat hatte ich ihr im in ist ja
+		kann mit nach nicht noch nur oder sich sie sind so über um und uns von vor war wie wir wird zu zum zur`),
+	Ukrainian: wordSet(`а або але без би був була були було бути в вже ви від він вона вони воно де для до
+		є же з за і із їх й коли ми між на над не ні ніж о об однак під по при про та так також там те
+		ти то тому тут у уже хоча це цей ці через що щоб як який яка які якщо я`),
+	Russian: wordSet(`а без бы был была были было быть в вам вас вот все всё вы где да для до его ее её же за
+		и из или им их к как когда ли мы на над не него нет ни но о об однако он она они оно от по под при
+		про с со так также там то тоже только у уже что чтобы это этот эти я`),
+}
+
+func wordSet(words string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, word := range strings.Fields(words) {
+		set[word] = struct{}{}
+	}
+	return set
+}
+
+// IsStopword reports whether the lowercase word is the stopword of the language.
+func IsStopword(language Language, word string) bool {
+	_, exists := stopwords[language][word]
+	return exists
+}
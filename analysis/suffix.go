@@ -0,0 +1,86 @@
+package analysis
+
+import "strings"
+
+// minimumStemLength is the number of the letters which are always kept by the stemmers.
+const minimumStemLength = 2
+
+// suffixGroup is the group of the endings of the stemmer. The endings of the group which
+// requires the preceding letter are removed only after one of the preceding letters.
+type suffixGroup struct {
+	endings   []string
+	preceding string
+}
+
+// longestEnding returns the length of the longest ending of the groups which is in the region
+// of the word starting from the start, and whether its condition of the preceding letter is met.
+// The region of the word must also contain the preceding letter.
+func longestEnding(word []rune, start int, groups ...suffixGroup) (int, bool) {
+	length, matchedGroup := 0, -1
+	for groupIndex, group := range groups {
+		for _, ending := range group.endings {
+			endingRunes := []rune(ending)
+			if len(endingRunes) > length && hasSuffixFrom(word, start, endingRunes) {
+				length, matchedGroup = len(endingRunes), groupIndex
+			}
+		}
+	}
+	if matchedGroup == -1 {
+		return 0, false
+	}
+	if preceding := groups[matchedGroup].preceding; preceding != "" {
+		position := len(word) - length - 1
+		if position < start || !strings.ContainsRune(preceding, word[position]) {
+			return length, false
+		}
+	}
+	return length, true
+}
+
+// removeEnding removes the longest ending of the groups in the region and reports whether it is removed.
+func removeEnding(word *[]rune, start int, groups ...suffixGroup) bool {
+	length, ok := longestEnding(*word, start, groups...)
+	if !ok || len(*word)-length < minimumStemLength {
+		return false
+	}
+	*word = (*word)[:len(*word)-length]
+	return true
+}
+
+// hasSuffixFrom reports whether the word ends with the suffix which starts in the region from the start.
+func hasSuffixFrom(word []rune, start int, suffix []rune) bool {
+	if len(suffix) > len(word) || len(word)-len(suffix) < start {
+		return false
+	}
+	for i, r := range suffix {
+		if word[len(word)-len(suffix)+i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// regions returns the start of the region after the first vowel, and the regions R1 and R2 of
+// the Snowball stemmers: R1 starts after the first non-vowel following the vowel, and R2 is R1 of R1.
+func regions(word []rune, vowels string) (rv, r1, r2 int) {
+	isVowel := func(r rune) bool { return strings.ContainsRune(vowels, r) }
+	rv, r1, r2 = len(word), len(word), len(word)
+	for i, r := range word {
+		if isVowel(r) {
+			rv = i + 1
+			break
+		}
+	}
+	r1 = regionAfter(word, 0, isVowel)
+	r2 = regionAfter(word, r1, isVowel)
+	return rv, r1, r2
+}
+
+func regionAfter(word []rune, start int, isVowel func(rune) bool) int {
+	for i := start + 1; i < len(word); i++ {
+		if !isVowel(word[i]) && isVowel(word[i-1]) {
+			return i + 1
+		}
+	}
+	return len(word)
+}
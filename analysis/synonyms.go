@@ -0,0 +1,75 @@
+package analysis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Synonyms is the dictionary of the groups of the words and the phrases with the same meaning,
+// like the spellings of the names in the different languages. The synonyms expand the keywords
+// of the search, so the news with any synonym are found.
+type Synonyms struct {
+	groups map[string][]string
+}
+
+// NewSynonyms returns the dictionary of the groups. The words are compared case-insensitively.
+func NewSynonyms(groups [][]string) *Synonyms {
+	synonyms := &Synonyms{groups: make(map[string][]string)}
+	for _, group := range groups {
+		var normalized []string
+		for _, word := range group {
+			if word = normalizeSynonym(word); word != "" {
+				normalized = append(normalized, word)
+			}
+		}
+		for _, word := range normalized {
+			synonyms.groups[word] = append(synonyms.groups[word], normalized...)
+		}
+	}
+	return synonyms
+}
+
+// LoadSynonyms reads the dictionary from the JSON file with the array of the groups, like
+// [["kyiv", "kiev", "київ"]]. The empty dictionary is returned if the file does not exist.
+func LoadSynonyms(path string) (*Synonyms, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewSynonyms(nil), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read synonyms: %w", err)
+	}
+	var groups [][]string
+	if err := json.Unmarshal(content, &groups); err != nil {
+		return nil, fmt.Errorf("failed to decode synonyms from %s: %w", path, err)
+	}
+	return NewSynonyms(groups), nil
+}
+
+// Expand returns the keyword and its synonyms without the duplicates, the keyword is the first.
+// The nil dictionary returns only the keyword.
+func (synonyms *Synonyms) Expand(keyword string) []string {
+	keyword = normalizeSynonym(keyword)
+	if keyword == "" {
+		return nil
+	}
+	expanded := []string{keyword}
+	if synonyms == nil {
+		return expanded
+	}
+	seen := map[string]struct{}{keyword: {}}
+	for _, synonym := range synonyms.groups[keyword] {
+		if _, exists := seen[synonym]; !exists {
+			seen[synonym] = struct{}{}
+			expanded = append(expanded, synonym)
+		}
+	}
+	return expanded
+}
+
+func normalizeSynonym(word string) string {
+	return strings.Join(strings.Fields(strings.ToLower(word)), " ")
+}
@@ -0,0 +1,63 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSynonyms_Expand(t *testing.T) {
+	synonyms := NewSynonyms([][]string{{"Kyiv", "Kiev", "Київ"}, {"kyiv", "capital of ukraine"}})
+	tests := []struct {
+		name     string
+		synonyms *Synonyms
+		keyword  string
+		want     []string
+	}{
+		{name: "Keyword in the group", synonyms: synonyms, keyword: "KIEV", want: []string{"kiev", "kyiv", "київ"}},
+		{name: "Keyword in several groups", synonyms: synonyms, keyword: "kyiv",
+			want: []string{"kyiv", "kiev", "київ", "capital of ukraine"}},
+		{name: "Phrase with extra spaces", synonyms: synonyms, keyword: " Capital  of Ukraine ",
+			want: []string{"capital of ukraine", "kyiv"}},
+		{name: "Keyword without synonyms", synonyms: synonyms, keyword: "Lviv", want: []string{"lviv"}},
+		{name: "Nil dictionary", synonyms: nil, keyword: "Kyiv", want: []string{"kyiv"}},
+		{name: "Empty keyword", synonyms: synonyms, keyword: "", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.synonyms.Expand(tt.keyword); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadSynonyms(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "synonyms.json")
+	if err := os.WriteFile(valid, []byte(`[["kyiv", "київ"]]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"kyiv": "київ"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	synonyms, err := LoadSynonyms(valid)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := synonyms.Expand("київ"); !reflect.DeepEqual(got, []string{"київ", "kyiv"}) {
+		t.Errorf("Expand() = %v, expected the synonyms from the file", got)
+	}
+
+	synonyms, err = LoadSynonyms(filepath.Join(dir, "missing.json"))
+	if err != nil || !reflect.DeepEqual(synonyms.Expand("kyiv"), []string{"kyiv"}) {
+		t.Errorf("LoadSynonyms() = %v, %v, expected the empty dictionary", synonyms, err)
+	}
+
+	if _, err := LoadSynonyms(invalid); err == nil {
+		t.Errorf("Expected error for the invalid file")
+	}
+}
@@ -0,0 +1,65 @@
+package analysis
+
+import "strings"
+
+const ukrainianVowels = "аеиоуюяіїє"
+
+// The endings of the Ukrainian stemmer follow the structure of the Snowball Russian stemmer
+// with the endings of the Ukrainian nouns, adjectives, participles and verbs.
+var (
+	ukrainianPerfectiveGerund = []suffixGroup{
+		{endings: []string{"в", "вши", "вшись"}, preceding: "ая"},
+		{endings: []string{"ив", "ивши", "ившись"}},
+	}
+	ukrainianAdjective = []suffixGroup{
+		{endings: []string{"ими", "ій", "ий", "а", "е", "ова", "ове", "є", "їй", "єє", "еє", "я", "ім", "ем",
+			"им", "их", "іх", "ою", "йми", "іми", "у", "ю", "ого", "ому", "ої", "ього", "ьому"}},
+	}
+	ukrainianParticiple = []suffixGroup{
+		{endings: []string{"ий", "ого", "ому", "им", "ім", "а", "ій", "у", "ою", "і", "их", "йми"}},
+	}
+	ukrainianReflexive = []suffixGroup{
+		{endings: []string{"ся", "сь", "си"}},
+	}
+	ukrainianVerb = []suffixGroup{
+		{endings: []string{"ив", "ать", "ять", "ав", "али", "учи", "ячи", "вши", "ши", "ме", "ати", "яти", "ити",
+			"ють", "уть", "ить", "ємо", "имо", "ете", "ите", "ала", "ило", "ила", "или", "ли", "ла", "ло"}},
+	}
+	ukrainianNoun = []suffixGroup{
+		{endings: []string{"а", "ев", "ов", "е", "ями", "ами", "еи", "и", "ей", "ой", "ий", "й", "иям", "ям",
+			"ием", "ем", "ам", "ом", "о", "у", "ах", "иях", "ях", "ь", "ию", "ью", "ю", "ия", "ья", "я", "і", "ові",
+			"ї", "ею", "єю", "ою", "є", "еві", "єм", "ів", "їв", "іям"}},
+	}
+	ukrainianDerivational = []suffixGroup{{endings: []string{"ост", "ость", "ість"}}}
+	ukrainianSuperlative  = []suffixGroup{{endings: []string{"ейш", "ейше", "іш", "іше"}}}
+)
+
+// apostrophes removes the apostrophes of the Ukrainian words like "м'ясо".
+var apostrophes = strings.NewReplacer("'", "", "’", "", "ʼ", "")
+
+// stemUkrainian is the Snowball-style stemmer of the Ukrainian words.
+func stemUkrainian(word string) string {
+	runes := []rune(apostrophes.Replace(word))
+	rv, _, r2 := regions(runes, ukrainianVowels)
+
+	if !removeEnding(&runes, rv, ukrainianPerfectiveGerund...) {
+		removeEnding(&runes, rv, ukrainianReflexive...)
+		if removeEnding(&runes, rv, ukrainianAdjective...) {
+			removeEnding(&runes, rv, ukrainianParticiple...)
+		} else if !removeEnding(&runes, rv, ukrainianVerb...) {
+			removeEnding(&runes, rv, ukrainianNoun...)
+		}
+	}
+
+	removeEnding(&runes, rv, suffixGroup{endings: []string{"и", "і"}})
+	removeEnding(&runes, max(rv, r2), ukrainianDerivational...)
+
+	switch {
+	case hasSuffixFrom(runes, rv, []rune("нн")):
+		runes = runes[:len(runes)-1]
+	case removeEnding(&runes, rv, ukrainianSuperlative...):
+	default:
+		removeEnding(&runes, rv, suffixGroup{endings: []string{"ь"}})
+	}
+	return string(runes)
+}
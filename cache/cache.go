@@ -0,0 +1,346 @@
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
+	"news-aggregator/parser"
+	"os"
+	"slices"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// Config describes the limits of the Cache.
+type Config struct {
+	// MaxBytes is the maximum estimated size of the cached news in bytes.
+	MaxBytes int64
+}
+
+// DefaultConfig returns the configuration used by the aggregator.
+func DefaultConfig() Config {
+	return Config{MaxBytes: 64 << 20}
+}
+
+// Stats are the statistics of the Cache.
+type Stats struct {
+	// Hits is the number of the requests answered from the cache.
+	Hits uint64 `json:"hits"`
+	// Misses is the number of the requests which parsed the file.
+	Misses uint64 `json:"misses"`
+	// Revalidations is the number of the hits of the files which were touched, or could be
+	// rewritten within the granularity of their modification time, but have the same content.
+	Revalidations uint64 `json:"revalidations"`
+	// Invalidations is the number of the entries dropped because the source is changed.
+	Invalidations uint64 `json:"invalidations"`
+	// Evictions is the number of the entries dropped because of the size limit.
+	Evictions uint64 `json:"evictions"`
+	// Entries is the number of the cached sources.
+	Entries int `json:"entries"`
+	// Bytes is the estimated size of the cached news.
+	Bytes int64 `json:"bytes"`
+	// MaxBytes is the limit of the size of the cached news.
+	MaxBytes int64 `json:"maxBytes"`
+}
+
+// modTimeGranularity is the coarsest granularity of the modification times of the file systems.
+// The file whose version is read within it after the modification may be rewritten by the updaters
+// without the change of the modification time, so its content is compared by the hash.
+const modTimeGranularity = 2 * time.Second
+
+// Loader parses the file of the source when it is not cached.
+type Loader func() ([]news.News, parser.Report, error)
+
+// key is the part of the source record which affects its parsed news.
+type key struct {
+	path         source.PathToFile
+	sourceType   source.Type
+	selectors    source.Selectors
+	etag         string
+	lastModified string
+}
+
+// version identifies the content of the file or the directory of the source.
+type version struct {
+	modTime   time.Time
+	size      int64
+	directory bool
+	// hash is the hash of the content of the file, it is not computed for the directories.
+	hash string
+	// checked is the time when the version was read.
+	checked time.Time
+}
+
+// racy reports whether the file may be changed after the version was read without the change
+// of the modification time.
+func (fileVersion version) racy() bool {
+	return fileVersion.checked.Sub(fileVersion.modTime) < modTimeGranularity
+}
+
+type entry struct {
+	key     key
+	version version
+	news    []news.News
+	report  parser.Report
+	bytes   int64
+}
+
+// Cache is the LRU cache of the parsed news of the sources. It is safe for the concurrent use.
+type Cache struct {
+	mutex   sync.Mutex
+	config  Config
+	entries map[source.PathToFile]*list.Element
+	order   *list.List
+	stats   Stats
+}
+
+// New returns the empty cache with the limits of the config.
+func New(config Config) *Cache {
+	return &Cache{
+		config:  config,
+		entries: make(map[source.PathToFile]*list.Element),
+		order:   list.New(),
+		stats:   Stats{MaxBytes: config.MaxBytes},
+	}
+}
+
+// Get returns the news of the source from the cache if its file is not changed, otherwise the
+// news are parsed by the loader and cached. The results of the failed loads are not cached.
+// The entries are shared by the sources with the same file, so the cached news and diagnostics
+// are returned with the name of the passed source. The returned slices are the copies, so the
+// callers may sort and filter them.
+func (cache *Cache) Get(currentSource source.Source, load Loader) ([]news.News, parser.Report, error) {
+	sourceKey := newKey(currentSource)
+	current, err := stat(currentSource.PathToFile)
+	if err != nil {
+		cache.Invalidate(currentSource.PathToFile)
+		return load()
+	}
+
+	if cached, ok := cache.lookup(sourceKey, &current); ok {
+		articles, report := cached.copyFor(currentSource.Name)
+		return articles, report, nil
+	}
+
+	// The hash is computed before the parsing, so the file changed during the parsing
+	// does not get the news of its previous content.
+	if !current.directory && current.hash == "" {
+		current.hash = hashFile(currentSource.PathToFile)
+	}
+	articles, report, err := load()
+	if err != nil {
+		cache.Invalidate(currentSource.PathToFile)
+		return articles, report, err
+	}
+	cache.store(&entry{
+		key:     sourceKey,
+		version: current,
+		news:    slices.Clone(articles),
+		report:  cloneReport(report),
+		bytes:   estimate(articles, report),
+	})
+	return articles, report, nil
+}
+
+// Invalidate drops the cached news of the source file.
+func (cache *Cache) Invalidate(path source.PathToFile) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if element, exists := cache.entries[path]; exists {
+		cache.remove(element)
+		cache.stats.Invalidations++
+	}
+}
+
+// Stats returns the current statistics of the cache.
+func (cache *Cache) Stats() Stats {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	stats := cache.stats
+	stats.Entries = len(cache.entries)
+	return stats
+}
+
+// lookup returns the cached entry of the source if it is not changed. The hash of the file
+// is compared when the modification time or the size is changed or the cached version is racy,
+// and the entry of the file with the same content gets the new version. The hash is set to
+// the version when it is computed. The racy entries of the directories are not used, since
+// their hashes are not computed.
+func (cache *Cache) lookup(sourceKey key, current *version) (*entry, bool) {
+	cache.mutex.Lock()
+	element, exists := cache.entries[sourceKey.path]
+	if !exists {
+		cache.stats.Misses++
+		cache.mutex.Unlock()
+		return nil, false
+	}
+	cached := element.Value.(*entry)
+	if cached.key == sourceKey && cached.version.modTime.Equal(current.modTime) && cached.version.size == current.size &&
+		!cached.version.racy() {
+		cache.order.MoveToFront(element)
+		cache.stats.Hits++
+		cache.mutex.Unlock()
+		return cached, true
+	}
+	cachedKey, cachedHash := cached.key, cached.version.hash
+	cache.mutex.Unlock()
+
+	if cachedKey == sourceKey && cachedHash != "" && !current.directory {
+		current.hash = hashFile(sourceKey.path)
+		if current.hash == cachedHash {
+			cache.mutex.Lock()
+			defer cache.mutex.Unlock()
+			if element, exists := cache.entries[sourceKey.path]; exists && element.Value.(*entry) == cached {
+				cached.version = *current
+				cache.order.MoveToFront(element)
+			}
+			cache.stats.Hits++
+			cache.stats.Revalidations++
+			return cached, true
+		}
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if element, exists := cache.entries[sourceKey.path]; exists && element.Value.(*entry) == cached {
+		cache.remove(element)
+		cache.stats.Invalidations++
+	}
+	cache.stats.Misses++
+	return nil, false
+}
+
+// store adds the entry and evicts the least recently used entries which exceed the limit.
+// The entry which is larger than the limit is not cached.
+func (cache *Cache) store(newEntry *entry) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if element, exists := cache.entries[newEntry.key.path]; exists {
+		cache.remove(element)
+	}
+	if cache.config.MaxBytes > 0 && newEntry.bytes > cache.config.MaxBytes {
+		return
+	}
+	cache.entries[newEntry.key.path] = cache.order.PushFront(newEntry)
+	cache.stats.Bytes += newEntry.bytes
+	for cache.config.MaxBytes > 0 && cache.stats.Bytes > cache.config.MaxBytes {
+		cache.remove(cache.order.Back())
+		cache.stats.Evictions++
+	}
+}
+
+// copyFor returns the copies of the cached news and diagnostics with the name of the source.
+func (cached *entry) copyFor(name source.Name) ([]news.News, parser.Report) {
+	articles := slices.Clone(cached.news)
+	for i := range articles {
+		articles[i].SourceName = name
+	}
+	report := cloneReport(cached.report)
+	for i := range report.Diagnostics {
+		report.Diagnostics[i].Source = name
+	}
+	return articles, report
+}
+
+// remove drops the element, the mutex must be held.
+func (cache *Cache) remove(element *list.Element) {
+	removed := cache.order.Remove(element).(*entry)
+	delete(cache.entries, removed.key.path)
+	cache.stats.Bytes -= removed.bytes
+}
+
+func newKey(currentSource source.Source) key {
+	sourceKey := key{
+		path:         currentSource.PathToFile,
+		sourceType:   currentSource.SourceType,
+		etag:         currentSource.ETag,
+		lastModified: currentSource.LastModified,
+	}
+	if currentSource.Selectors != nil {
+		sourceKey.selectors = *currentSource.Selectors
+	}
+	return sourceKey
+}
+
+// stat returns the version of the file without the hash. The version of the directory
+// is made of the latest modification time and the total size of its files. The time of the
+// check is taken before the file is read, so the version is racy if the file is changed then.
+func stat(path source.PathToFile) (version, error) {
+	checked := time.Now()
+	info, err := os.Stat(string(path))
+	if err != nil {
+		return version{}, err
+	}
+	if !info.IsDir() {
+		return version{modTime: info.ModTime(), size: info.Size(), checked: checked}, nil
+	}
+
+	entries, err := os.ReadDir(string(path))
+	if err != nil {
+		return version{}, err
+	}
+	directoryVersion := version{modTime: info.ModTime(), size: int64(len(entries)), directory: true, checked: checked}
+	for _, dirEntry := range entries {
+		entryInfo, err := dirEntry.Info()
+		if err != nil {
+			return version{}, err
+		}
+		if entryInfo.ModTime().After(directoryVersion.modTime) {
+			directoryVersion.modTime = entryInfo.ModTime()
+		}
+		directoryVersion.size += entryInfo.Size()
+	}
+	return directoryVersion, nil
+}
+
+// hashFile returns the hash of the content of the file or an empty string if it cannot be read.
+func hashFile(path source.PathToFile) string {
+	file, err := os.Open(string(path))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func cloneReport(report parser.Report) parser.Report {
+	cloned := parser.Report{Diagnostics: slices.Clone(report.Diagnostics)}
+	for name, status := range report.Sources {
+		cloned.SetStatus(name, status)
+	}
+	return cloned
+}
+
+// newsOverhead is the estimated size of the fixed part of the news and its slice element.
+const newsOverhead = int64(unsafe.Sizeof(news.News{})) + int64(unsafe.Sizeof(time.Time{}))
+
+// estimate returns the estimated size of the parsed news and their report in bytes.
+func estimate(articles []news.News, report parser.Report) int64 {
+	var size int64
+	for _, article := range articles {
+		size += newsOverhead + int64(len(article.ID)+len(article.Title)+len(article.Description)+
+			len(article.Link)+len(article.SourceName)+len(article.Content)+len(article.Language))
+		for _, value := range article.Authors {
+			size += int64(unsafe.Sizeof(value)) + int64(len(value))
+		}
+		for _, value := range article.Categories {
+			size += int64(unsafe.Sizeof(value)) + int64(len(value))
+		}
+		for _, value := range article.Images {
+			size += int64(unsafe.Sizeof(value)) + int64(len(value))
+		}
+	}
+	for _, diagnostic := range report.Diagnostics {
+		size += int64(unsafe.Sizeof(diagnostic)) + int64(len(diagnostic.Source)+len(diagnostic.Item)+
+			len(diagnostic.Action)+len(diagnostic.Reason)+len(diagnostic.Fallback))
+	}
+	return size
+}
@@ -0,0 +1,239 @@
+package cache
+
+import (
+	"errors"
+	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
+	"news-aggregator/parser"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingLoader returns the loader of the news with the passed titles which counts its calls.
+func countingLoader(calls *int, titles ...news.Title) Loader {
+	return func() ([]news.News, parser.Report, error) {
+		*calls++
+		var articles []news.News
+		for _, title := range titles {
+			articles = append(articles, news.News{Title: title})
+		}
+		return articles, parser.Report{}, nil
+	}
+}
+
+func writeFile(t *testing.T, path, content string, modTime time.Time) {
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestCache_Get(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	modTime := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	writeFile(t, path, "first", modTime)
+	currentSource := source.Source{Name: "feed", PathToFile: source.PathToFile(path), SourceType: source.RSS}
+	cache := New(DefaultConfig())
+	calls := 0
+
+	got, _, err := cache.Get(currentSource, countingLoader(&calls, "First"))
+	require.NoError(t, err)
+	assert.Equal(t, news.Title("First"), got[0].Title)
+
+	got[0].Title = "Changed by the caller"
+	got, _, err = cache.Get(currentSource, countingLoader(&calls, "Second"))
+	require.NoError(t, err)
+	assert.Equal(t, news.Title("First"), got[0].Title, "the unchanged file must be answered from the cache")
+	assert.Equal(t, 1, calls)
+
+	writeFile(t, path, "first", modTime.Add(time.Hour))
+	got, _, err = cache.Get(currentSource, countingLoader(&calls, "Second"))
+	require.NoError(t, err)
+	assert.Equal(t, news.Title("First"), got[0].Title, "the touched file with the same content must be revalidated")
+	assert.Equal(t, 1, calls)
+
+	writeFile(t, path, "second", modTime.Add(time.Hour))
+	got, _, err = cache.Get(currentSource, countingLoader(&calls, "Second"))
+	require.NoError(t, err)
+	assert.Equal(t, news.Title("Second"), got[0].Title, "the changed file must be parsed again")
+	assert.Equal(t, 2, calls)
+
+	currentSource.ETag = `"refreshed"`
+	_, _, err = cache.Get(currentSource, countingLoader(&calls, "Third"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls, "the source refreshed by the updater must be parsed again")
+
+	currentSource.Selectors = &source.Selectors{Item: "article"}
+	_, _, err = cache.Get(currentSource, countingLoader(&calls, "Fourth"))
+	require.NoError(t, err)
+	assert.Equal(t, 4, calls, "the source with the new selectors must be parsed again")
+
+	assert.Equal(t, Stats{
+		Hits:          2,
+		Misses:        4,
+		Revalidations: 1,
+		Invalidations: 3,
+		Entries:       1,
+		Bytes:         estimate([]news.News{{Title: "Fourth"}}, parser.Report{}),
+		MaxBytes:      DefaultConfig().MaxBytes,
+	}, cache.Stats())
+}
+
+func TestCache_GetNameCasing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bbc.xml")
+	writeFile(t, path, "content", time.Now())
+	cache := New(DefaultConfig())
+	calls := 0
+
+	for _, name := range []source.Name{"BBC", "bbc"} {
+		currentSource := source.Source{Name: name, PathToFile: source.PathToFile(path), SourceType: source.RSS}
+		_, _, err := cache.Get(currentSource, countingLoader(&calls, "News"))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, calls, "the source requested with the other casing must be answered from the cache")
+	assert.Equal(t, 1, cache.Stats().Entries)
+}
+
+func TestCache_GetSharedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	writeFile(t, path, "content", time.Now())
+	cache := New(DefaultConfig())
+	calls := 0
+	load := func(name source.Name) Loader {
+		return func() ([]news.News, parser.Report, error) {
+			calls++
+			var report parser.Report
+			report.Skip(name, "item", "reason")
+			return []news.News{{Title: "News", SourceName: name}}, report, nil
+		}
+	}
+
+	first := source.Source{Name: "first", PathToFile: source.PathToFile(path)}
+	second := source.Source{Name: "second", PathToFile: source.PathToFile(path)}
+	_, _, err := cache.Get(first, load(first.Name))
+	require.NoError(t, err)
+	got, report, err := cache.Get(second, load(second.Name))
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, source.Name("second"), got[0].SourceName, "the cached news must have the name of the requested source")
+	assert.Equal(t, source.Name("second"), report.Diagnostics[0].Source)
+
+	got, _, err = cache.Get(first, load(first.Name))
+	require.NoError(t, err)
+	assert.Equal(t, source.Name("first"), got[0].SourceName)
+}
+
+func TestCache_GetRewrittenWithinModTimeGranularity(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	modTime := time.Now().Truncate(time.Second)
+	writeFile(t, path, "first", modTime)
+	currentSource := source.Source{Name: "feed", PathToFile: source.PathToFile(path), SourceType: source.RSS}
+	cache := New(DefaultConfig())
+	calls := 0
+
+	_, _, err := cache.Get(currentSource, countingLoader(&calls, "First"))
+	require.NoError(t, err)
+	got, _, err := cache.Get(currentSource, countingLoader(&calls, "Second"))
+	require.NoError(t, err)
+	assert.Equal(t, news.Title("First"), got[0].Title, "the same content must be answered from the cache")
+	assert.Equal(t, 1, calls)
+
+	// The updater rewrites the file with the content of the same size within the same second.
+	writeFile(t, path, "other", modTime)
+	got, _, err = cache.Get(currentSource, countingLoader(&calls, "Second"))
+	require.NoError(t, err)
+	assert.Equal(t, news.Title("Second"), got[0].Title, "the rewritten file must be parsed again")
+	assert.Equal(t, 2, calls)
+}
+
+func TestCache_GetErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	writeFile(t, path, "content", time.Now())
+	currentSource := source.Source{Name: "feed", PathToFile: source.PathToFile(path)}
+	cache := New(DefaultConfig())
+	calls := 0
+	failingLoader := func() ([]news.News, parser.Report, error) {
+		calls++
+		return nil, parser.Report{}, errors.New("parse error")
+	}
+
+	_, _, err := cache.Get(currentSource, failingLoader)
+	assert.Error(t, err)
+	_, _, err = cache.Get(currentSource, failingLoader)
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls, "the failed results must not be cached")
+
+	_, _, err = cache.Get(source.Source{Name: "missing", PathToFile: "missing.xml"}, failingLoader)
+	assert.Error(t, err)
+	assert.Equal(t, 0, cache.Stats().Entries)
+}
+
+func TestCache_Eviction(t *testing.T) {
+	directory := t.TempDir()
+	entrySize := estimate([]news.News{{Title: "News"}}, parser.Report{})
+	cache := New(Config{MaxBytes: 2 * entrySize})
+	calls := 0
+
+	sources := make([]source.Source, 3)
+	for i, name := range []source.Name{"first", "second", "third"} {
+		path := filepath.Join(directory, string(name)+".xml")
+		writeFile(t, path, string(name), time.Now())
+		sources[i] = source.Source{Name: name, PathToFile: source.PathToFile(path)}
+	}
+
+	for _, currentSource := range sources[:2] {
+		_, _, err := cache.Get(currentSource, countingLoader(&calls, "News"))
+		require.NoError(t, err)
+	}
+	_, _, err := cache.Get(sources[0], countingLoader(&calls, "News"))
+	require.NoError(t, err)
+	_, _, err = cache.Get(sources[2], countingLoader(&calls, "News"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	_, _, err = cache.Get(sources[0], countingLoader(&calls, "News"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls, "the recently used source must be kept")
+	_, _, err = cache.Get(sources[1], countingLoader(&calls, "News"))
+	require.NoError(t, err)
+	assert.Equal(t, 4, calls, "the least recently used source must be evicted")
+
+	stats := cache.Stats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.LessOrEqual(t, stats.Bytes, stats.MaxBytes)
+	assert.Equal(t, uint64(2), stats.Evictions)
+
+	largePath := filepath.Join(directory, "large.xml")
+	writeFile(t, largePath, "large", time.Now())
+	large := source.Source{Name: "large", PathToFile: source.PathToFile(largePath)}
+	_, _, err = cache.Get(large, countingLoader(&calls, "News", "Too", "Many"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, cache.Stats().Entries, "the entry larger than the limit must not be cached")
+	_, _, err = cache.Get(large, countingLoader(&calls, "News", "Too", "Many"))
+	require.NoError(t, err)
+	assert.Equal(t, 6, calls)
+}
+
+func TestCache_GetDirectory(t *testing.T) {
+	directory := t.TempDir()
+	modTime := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	writeFile(t, filepath.Join(directory, "1.eml"), "first", modTime)
+	require.NoError(t, os.Chtimes(directory, modTime, modTime))
+	currentSource := source.Source{Name: "newsletters", PathToFile: source.PathToFile(directory), SourceType: source.EMAIL}
+	cache := New(DefaultConfig())
+	calls := 0
+
+	for i := 0; i < 2; i++ {
+		_, _, err := cache.Get(currentSource, countingLoader(&calls, "First"))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, calls)
+
+	writeFile(t, filepath.Join(directory, "2.eml"), "second", modTime.Add(time.Hour))
+	_, _, err := cache.Get(currentSource, countingLoader(&calls, "First", "Second"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls, "the directory with the new file must be parsed again")
+}
@@ -0,0 +1,10 @@
+// Package cache keeps the parsed news of the source files between the requests.
+// Cache returns the news of the source without parsing its file again while the file
+// has the same modification time and size, or the same hash if they are changed or the
+// file was read within the granularity of its modification time. The entries are keyed
+// by the files, so the sources with the same file share them, and are also invalidated
+// when the type, the selectors or the validators of the source are changed by the refresh
+// of the updaters. The least recently used entries are
+// evicted when the estimated size of the news exceeds the limit set by Config, and the
+// statistics of the hits and the misses are returned by Stats.
+package cache
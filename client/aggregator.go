@@ -1,8 +1,10 @@
 package client
 
 import (
-	"news-aggregator/entity/news"
+	"context"
+	"news-aggregator/aggregation"
 	"news-aggregator/filter"
+	"news-aggregator/pagination"
 )
 
 // Aggregator defines an interface for aggregating collector news.
@@ -10,6 +12,12 @@ import (
 //go:generate mockgen -source=aggregator.go -destination=mock_aggregator/mock_aggregator.go -package=client  news-aggregator/aggregator Aggregator
 type Aggregator interface {
 	// Aggregate fetches news from the provided sources,
-	//applies the given filters, and returns the filtered news.
-	Aggregate(sources []string, filters ...filter.NewsFilter) ([]news.News, error)
+	//applies the given filters, and returns the filtered news with the parsing report and the statistics
+	// of the sources and the filters. The report lists the sources which failed, their news are missing
+	// from the partial result. The aggregation stops with the error of the context when the context is done.
+	Aggregate(ctx context.Context, sources []string, filters ...filter.NewsFilter) (aggregation.Result, error)
+	// AggregatePage fetches and filters the news like Aggregate, sorts them by the order of the
+	// request and returns the requested page with the total number of the news and the cursors
+	// of the neighbouring pages.
+	AggregatePage(ctx context.Context, sources []string, request pagination.Request, filters ...filter.NewsFilter) (pagination.Page, aggregation.Result, error)
 }
@@ -1,12 +1,18 @@
 package client
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"github.com/sirupsen/logrus"
-	"news-aggregator/constant"
+	"news-aggregator/analysis"
+	"news-aggregator/cluster"
 	"news-aggregator/entity/news"
 	"news-aggregator/filter"
+	"news-aggregator/search"
+	"news-aggregator/sorter"
 	"news-aggregator/validator"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -14,44 +20,129 @@ import (
 //go:generate mockgen -source=client.go -destination=mock_aggregator/mock_client.go -package=client news-aggregator/client Client
 type Client interface {
 	//FetchNews collect the news by some rules defined in the implementations.
-	FetchNews() ([]news.News, error)
+	//The fetching stops with the error of the context when the context is done.
+	FetchNews(ctx context.Context) ([]news.News, error)
 	//Print outputs the transferred news.
 	Print(news []news.News)
 }
 
 // buildKeywordFilter extracts keywords from command line arguments and adds them to the filters.
-func buildKeywordFilter(keywords string, filters []filter.NewsFilter) []filter.NewsFilter {
+// The keywords are expanded by the dictionary of synonyms, which may be nil.
+func buildKeywordFilter(keywords string, synonyms *analysis.Synonyms, filters []filter.NewsFilter) []filter.NewsFilter {
 	logrus.Info("building keywords filter for: " + keywords)
 	if keywords != "" {
 		keywordList := strings.Split(keywords, ",")
 		uniqueKeywords := checkUnique(keywordList)
-		filters = append(filters, filter.ByKeyword{Keywords: uniqueKeywords})
+		filters = append(filters, filter.ByKeyword{Keywords: uniqueKeywords, Synonyms: synonyms})
 	}
 	return filters
 }
 
+// buildRelevanceFilter extracts keywords from command line arguments and adds the filter
+// which scores the news by relevance to them. The news are ranked by the index of the stored
+// news, without the index they are ranked by the statistics of the collected news.
+func buildRelevanceFilter(keywords string, index *search.Index, filters []filter.NewsFilter) []filter.NewsFilter {
+	logrus.Info("building relevance filter for: " + keywords)
+	if keywords != "" {
+		filters = append(filters, search.ByRelevance{Index: index, Keywords: checkUnique(strings.Split(keywords, ","))})
+	}
+	return filters
+}
+
+// buildTextFilter adds the relevance filter of the keywords if the news are sorted by
+// relevance alone or with the other keys, otherwise the keyword filter.
+func buildTextFilter(keywords, sortBy string, index *search.Index, synonyms *analysis.Synonyms, filters []filter.NewsFilter) []filter.NewsFilter {
+	if sorter.SortsBy(sortBy, sorter.Relevance) {
+		return buildRelevanceFilter(keywords, index, filters)
+	}
+	return buildKeywordFilter(keywords, synonyms, filters)
+}
+
+// buildQueryFilter compiles the search query and adds it to the filters.
+// The words of the query are expanded by the dictionary of synonyms, which may be nil.
+// The returned error has the column of the syntax error of the query.
+func buildQueryFilter(query string, synonyms *analysis.Synonyms, filters []filter.NewsFilter) ([]filter.NewsFilter, error) {
+	logrus.Info("building query filter for: " + query)
+	if strings.TrimSpace(query) == "" {
+		return filters, nil
+	}
+	queryFilter, err := filter.ParseQuery(query, synonyms)
+	if err != nil {
+		return filters, err
+	}
+	return append(filters, queryFilter), nil
+}
+
 // buildDateFilters extracts date filters from command line arguments and adds them to the filters.
-func buildDateFilters(startDateStr, endDateStr string, filters []filter.NewsFilter) ([]filter.NewsFilter, error) {
-	logrus.Info("building date filters for start date: " + startDateStr + "and the end date: " + endDateStr)
-	validationErr, isValid := validator.ValidateDate(startDateStr, endDateStr)
+// The dates without the time zone are taken in the location, or in UTC if the location is nil.
+// The filters are returned unchanged with the error if the dates are invalid.
+func buildDateFilters(since, until, last string, location *time.Location, filters []filter.NewsFilter) ([]filter.NewsFilter, error) {
+	logrus.Info("building date filters for since: " + since + ", until: " + until + " and last: " + last)
+	validationErr, isValid := validator.ValidateDate(since, until, last)
 
 	if validationErr != nil {
-		return nil, validationErr
+		return filters, validationErr
 	}
 	if isValid {
-		startDate, err := time.Parse(constant.DateOutputLayout, startDateStr)
+		if location == nil {
+			location = time.UTC
+		}
+		dateFilter, err := filter.ParseDateRange(since, until, last, time.Now().In(location))
 		if err != nil {
-			return nil, errors.New("Invalid start date: " + startDateStr)
+			return filters, err
 		}
+		return append(filters, dateFilter), nil
+	}
+	return filters, nil
+}
 
-		endDate, err := time.Parse(constant.DateOutputLayout, endDateStr)
-		if err != nil {
-			return nil, errors.New("Invalid end date: " + endDateStr)
+// buildGroupFilter adds the filter which groups the near-duplicate news into the stories if the
+// news are grouped by story. The similarity is the threshold of the grouping from 0 to 1, the
+// default threshold is used if it is empty.
+func buildGroupFilter(group, similarity string, filters []filter.NewsFilter) ([]filter.NewsFilter, error) {
+	logrus.Info("building group filter for: " + group + " with similarity: " + similarity)
+	if group == "" {
+		return filters, nil
+	}
+	if !strings.EqualFold(group, cluster.GroupByStory) {
+		return filters, errors.New("wrong group parameter: " + group)
+	}
+	config := cluster.DefaultConfig()
+	if similarity != "" {
+		threshold, err := strconv.ParseFloat(similarity, 64)
+		if err != nil || threshold <= 0 || threshold > 1 {
+			return filters, errors.New("wrong similarity parameter, must be from 0 to 1: " + similarity)
 		}
+		config.Similarity = threshold
+	}
+	return append(filters, cluster.ByStory{Config: config}), nil
+}
 
-		return append(filters, filter.ByDate{StartDate: startDate, EndDate: endDate}), nil
+// loadTimezone returns the location of the IANA time zone like Europe/Kyiv,
+// or nil if the name is empty.
+func loadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
 	}
-	return filters, nil
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", name, err)
+	}
+	return location, nil
+}
+
+// inTimezone returns the copy of the news with the dates in the location.
+// The news are returned unchanged if the location is nil.
+func inTimezone(articles []news.News, location *time.Location) []news.News {
+	if location == nil {
+		return articles
+	}
+	converted := make([]news.News, len(articles))
+	for i, article := range articles {
+		article.Date = article.Date.In(location)
+		converted[i] = article
+	}
+	return converted
 }
 
 // checkUnique returns a slice containing only unique strings from the input slice.
@@ -1,18 +1,25 @@
 package client
 
 import (
+	"bufio"
+	"context"
 	"flag"
 	"fmt"
 	"github.com/Masterminds/sprig/v3"
-	"github.com/reiver/go-porterstemmer"
 	"github.com/sirupsen/logrus"
+	"io"
+	"news-aggregator/aggregation"
+	"news-aggregator/analysis"
 	"news-aggregator/entity/news"
 	"news-aggregator/filter"
+	"news-aggregator/pagination"
+	"news-aggregator/search"
 	"news-aggregator/sorter"
 	"os"
-	"regexp"
 	"strings"
 	"text/template"
+	"time"
+	"unicode"
 )
 
 // commandLineClient represents a command line client for the news-aggregator application.
@@ -20,79 +27,206 @@ type commandLineClient struct {
 	aggregator       Aggregator
 	sources          []string
 	keywords         string
+	query            string
+	queryError       error
 	startDateStr     string
 	endDateStr       string
+	last             string
+	timezone         string
+	location         *time.Location
+	dateError        error
+	group            string
+	similarity       string
+	groupError       error
 	sortBy           string
 	sortingBySources bool
 	help             bool
-	DateSorter       sorter.DateSorter
+	pages            bool
+	pageRequest      pagination.Request
+	pageError        error
+	page             pagination.Page
+	result           aggregation.Result
+	ctx              context.Context
+	Sorter           Sorter
 	filters          []filter.NewsFilter
+	synonyms         *analysis.Synonyms
 }
 
-// NewCommandLine creates and initializes a new commandLineClient with the provided aggregator.
-func NewCommandLine(aggregator Aggregator) Client {
-	cli := &commandLineClient{aggregator: aggregator}
-	cli.DateSorter = sorter.DateSorter{}
+// defaultPageLimit is the number of the news of the page when the news are paged through without the limit.
+const defaultPageLimit = 10
+
+// NewCommandLine creates and initializes a new commandLineClient with the provided aggregator,
+// the index of the stored news which ranks the news by relevance and the dictionary of synonyms
+// which expands the keywords, the index and the dictionary may be nil.
+func NewCommandLine(aggregator Aggregator, index *search.Index, synonyms *analysis.Synonyms) Client {
+	cli := &commandLineClient{aggregator: aggregator, synonyms: synonyms}
+	cli.Sorter = sorter.MultiKeySorter{}
 	var sourcesStr string
-	flag.StringVar(&sourcesStr, "sources", "", "Specify news sources separated by comma")
+	flag.StringVar(&sourcesStr, "sources", "", "Specify news sources separated by comma, \"all\", tags like \"tag:world\" or patterns like \"n*\"")
 	flag.StringVar(&cli.keywords, "keywords", "", "Specify keywords to filter collector articles")
+	flag.StringVar(&cli.query, "query", "", "Specify search query with AND, OR, NOT, parentheses, \"phrases\", title:, description:, source: and wildcards like ukr*")
 	flag.StringVar(&cli.startDateStr, "startDate", "", "Specify start date (YYYY-MM-DD)")
 	flag.StringVar(&cli.endDateStr, "endDate", "", "Specify end date (YYYY-MM-DD)")
-	flag.StringVar(&cli.sortBy, "sortBy", "", "Specify sort by DESC/ASC.")
+	flag.StringVar(&cli.startDateStr, "since", "", "Specify start of the date range: YYYY-MM-DD, RFC3339 timestamp or duration before now like 7d")
+	flag.StringVar(&cli.endDateStr, "until", "", "Specify end of the date range: YYYY-MM-DD, RFC3339 timestamp or duration before now like 1h")
+	flag.StringVar(&cli.last, "last", "", "Specify duration before now to show the news of, like 24h or 7d")
+	flag.StringVar(&cli.timezone, "timezone", "", "Specify time zone of the dates, like Europe/Kyiv")
+	flag.StringVar(&cli.sortBy, "sortBy", "", "Specify sort by DESC/ASC, relevance to the keywords or keys like \"source:asc,date:desc\" of date, title, source, relevance and length")
 	flag.BoolVar(&cli.sortingBySources, "sortingBySources", false, "Enable sorting articles by sources")
+	flag.StringVar(&cli.group, "group", "", "Specify \"story\" to group the near-duplicate articles of the sources into the stories")
+	flag.StringVar(&cli.similarity, "similarity", "", "Specify similarity threshold of the articles of one story from 0 to 1")
+	var limit, offset, cursor string
+	flag.StringVar(&limit, "limit", "", "Specify maximum number of the articles to show")
+	flag.StringVar(&offset, "offset", "", "Specify number of the articles to skip")
+	flag.StringVar(&cursor, "cursor", "", "Specify cursor of the page printed after the previous page")
+	flag.BoolVar(&cli.pages, "pages", false, "Page through the articles: Enter shows the next page, p the previous one and q quits")
 	flag.BoolVar(&cli.help, "help", false, "Show help information")
 	flag.Parse()
 
 	cli.sources = checkUnique(strings.Split(sourcesStr, ","))
-	cli.filters = buildKeywordFilter(cli.keywords, cli.filters)
-	var err error
-	cli.filters, err = buildDateFilters(cli.startDateStr, cli.endDateStr, cli.filters)
-	if err != nil {
-		logrus.Error("Command line client: Date filter error: ", err)
+	cli.filters = buildTextFilter(cli.keywords, cli.sortBy, index, synonyms, cli.filters)
+	cli.filters, cli.queryError = buildQueryFilter(cli.query, synonyms, cli.filters)
+	if cli.queryError != nil {
+		logrus.Error("Command line client: Query filter error: ", cli.queryError)
+	}
+	cli.location, cli.dateError = loadTimezone(cli.timezone)
+	if cli.dateError == nil {
+		cli.filters, cli.dateError = buildDateFilters(cli.startDateStr, cli.endDateStr, cli.last, cli.location, cli.filters)
+	}
+	if cli.dateError != nil {
+		logrus.Error("Command line client: Date filter error: ", cli.dateError)
+	}
+	cli.filters, cli.groupError = buildGroupFilter(cli.group, cli.similarity, cli.filters)
+	if cli.groupError != nil {
+		logrus.Error("Command line client: Group filter error: ", cli.groupError)
+	}
+	cli.pageRequest, cli.pageError = pagination.ParseRequest(limit, offset, cursor)
+	if cli.pageError != nil {
+		logrus.Error("Command line client: Pagination error: ", cli.pageError)
+	}
+	if cli.pages && cli.pageRequest.Limit == 0 {
+		cli.pageRequest.Limit = defaultPageLimit
 	}
 
 	logrus.Info("Command line client: Initialized with sources: ", cli.sources, " and filters: ", cli.filters)
 	return cli
 }
 
-// FetchNews fetches news based on the command line arguments.
-func (cli *commandLineClient) FetchNews() ([]news.News, error) {
+// FetchNews fetches news based on the command line arguments. The context is kept
+// for the pages which are fetched while the news are paged through in Print.
+func (cli *commandLineClient) FetchNews(ctx context.Context) ([]news.News, error) {
 	if cli.help {
 		cli.printUsage()
 		return nil, nil
 	}
+	if cli.queryError != nil {
+		return nil, cli.queryError
+	}
+	if cli.dateError != nil {
+		return nil, cli.dateError
+	}
+	if cli.groupError != nil {
+		return nil, cli.groupError
+	}
+	if cli.pageError != nil {
+		return nil, cli.pageError
+	}
+
+	cli.ctx = ctx
+	page, err := cli.fetchPage(ctx, cli.pageRequest)
+	if err != nil {
+		return nil, err
+	}
+	return page.News, nil
+}
 
+// fetchPage fetches the page of the news sorted by the sortBy parameter and keeps it for the output.
+func (cli *commandLineClient) fetchPage(ctx context.Context, request pagination.Request) (pagination.Page, error) {
 	logrus.Info("Command line client: Fetching news with sources: ", cli.sources, " and filters: ", cli.filters)
-	news, err := cli.aggregator.Aggregate(cli.sources, cli.filters...)
+	request.Order = func(articles []news.News) ([]news.News, error) {
+		return cli.Sorter.SortNews(articles, cli.sortBy)
+	}
+	page, result, err := cli.aggregator.AggregatePage(ctx, cli.sources, request, cli.filters...)
 	if err != nil {
 		logrus.Error("Command line client: Aggregation error: ", err)
-		return nil, err
+		return pagination.Page{}, err
+	}
+	result.Report.Log()
+	for _, warning := range result.Warnings() {
+		fmt.Fprintln(os.Stderr, "Warning: the news are incomplete,", warning)
 	}
+	cli.page = page
+	cli.result = result
+	logrus.Info("Command line client: Articles fetched and sorted with sortBy: ", cli.sortBy)
+	return page, nil
+}
+
+// browsePages prints the next page after the Enter or n is read from the input, the previous page
+// after p and stops after q, at the end of the input or when the context is done.
+func (cli *commandLineClient) browsePages(ctx context.Context, input io.Reader, output io.Writer, printPage func([]news.News)) {
+	scanner := bufio.NewScanner(input)
+	for (cli.page.Next != "" || cli.page.Previous != "") && ctx.Err() == nil {
+		var options []string
+		if cli.page.Next != "" {
+			options = append(options, "Enter - next page")
+		}
+		if cli.page.Previous != "" {
+			options = append(options, "p - previous page")
+		}
+		fmt.Fprintf(output, "Page %s. %s, q - quit: ", describePage(cli.page), strings.Join(options, ", "))
+		if !scanner.Scan() {
+			return
+		}
+
+		var cursor string
+		switch strings.ToLower(strings.TrimSpace(scanner.Text())) {
+		case "", "n":
+			cursor = cli.page.Next
+		case "p":
+			cursor = cli.page.Previous
+		case "q":
+			return
+		}
+		if cursor == "" {
+			continue
+		}
+		page, err := cli.fetchPage(ctx, pagination.Request{Limit: cli.pageRequest.Limit, Cursor: cursor})
+		if err != nil {
+			fmt.Fprintln(output, err)
+			return
+		}
+		printPage(page.News)
+	}
+}
 
-	news, fetchParametersError := cli.DateSorter.SortNews(news, cli.sortBy)
-	if fetchParametersError != nil {
-		logrus.Error("Command line client: Date sorting error: ", fetchParametersError)
-		return nil, fetchParametersError
+// describePage returns the positions of the first and the last news of the page like "11-20 of 95".
+func describePage(page pagination.Page) string {
+	if len(page.News) == 0 {
+		return fmt.Sprintf("0 of %d", page.Total)
 	}
-	logrus.Info("Command line client: Articles fetched and sorted by date with sortBy: ", cli.sortBy)
-	return news, nil
+	return fmt.Sprintf("%d-%d of %d", page.Offset+1, page.Offset+len(page.News), page.Total)
 }
 
-// Print outputs the transferred news.
+// Print outputs the transferred news. In the paging mode the other pages are printed
+// one by one as they are requested by the standard input.
 func (cli *commandLineClient) Print(newsForOutput []news.News) {
+	cli.printPage(newsForOutput)
+	if cli.pages {
+		ctx := cli.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		cli.browsePages(ctx, os.Stdin, os.Stdout, cli.printPage)
+	}
+}
+
+// printPage outputs the news of the page with the filters and the position of the page.
+func (cli *commandLineClient) printPage(newsForOutput []news.News) {
 	funcMap := sprig.FuncMap()
 	funcMap["emphasise"] = func(keywords, text string) string {
-		if keywords == "" {
-			return text
-		} else {
-			for _, keyword := range strings.Split(keywords, ",") {
-				stemString := porterstemmer.StemString(strings.ToLower(keyword))
-				re := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(stemString))
-				text = re.ReplaceAllString(text, "//"+stemString+"//")
-			}
-			return text
-		}
+		return emphasise(keywords, text, cli.synonyms)
 	}
+	funcMap["milliseconds"] = milliseconds
 
 	tmpl, err := template.New("news").Funcs(funcMap).ParseFiles("client/OutputTemplate.tmpl")
 	if err != nil {
@@ -105,6 +239,7 @@ func (cli *commandLineClient) Print(newsForOutput []news.News) {
 		SortingBySources bool
 	}
 
+	newsForOutput = inTimezone(newsForOutput, cli.location)
 	var data []newsData
 	for _, n := range newsForOutput {
 		data = append(data, newsData{
@@ -116,14 +251,28 @@ func (cli *commandLineClient) Print(newsForOutput []news.News) {
 	outputData := struct {
 		Filters          []string
 		Count            int
+		Total            int
+		Page             string
+		Next             string
+		Previous         string
 		News             []newsData
 		NewsBySource     map[string][]newsData
 		SortingBySources bool
+		Result           aggregation.Result
 	}{
-		Filters:          []string{cli.keywords, cli.startDateStr, cli.endDateStr},
+		Filters:          []string{cli.keywords, cli.startDateStr, cli.endDateStr, cli.query, cli.last, cli.timezone, cli.group},
 		Count:            len(newsForOutput),
+		Total:            len(newsForOutput),
 		News:             data,
 		SortingBySources: cli.sortingBySources,
+		Result:           cli.result,
+	}
+
+	if cli.pageRequest.Paged() {
+		outputData.Total = cli.page.Total
+		outputData.Page = describePage(cli.page)
+		outputData.Next = cli.page.Next
+		outputData.Previous = cli.page.Previous
 	}
 
 	if cli.sortingBySources {
@@ -146,13 +295,87 @@ func (cli *commandLineClient) Print(newsForOutput []news.News) {
 	}
 }
 
+// milliseconds returns the duration rounded to the milliseconds like "35ms".
+func milliseconds(duration time.Duration) string {
+	return duration.Round(time.Millisecond).String()
+}
+
 // printUsage prints the usage instructions
 func (cli *commandLineClient) printUsage() {
 	fmt.Println("Usage of news-aggregator:" +
 		"\nType --sources, and then list the news you want to retrieve information from. " +
 		"The program supports such news news:\nABC, BBC, NBC, USA Today and Washington Times. \n" +
 		"\nType --keywords, and then list the keywords by which you want to filter articles. \n" +
-		"\nType --startDate and --endDate to filter by date. News published between the specified dates will be shown." +
-		"Date format - yyyy-mm-dd" + "" +
-		"Type --sortBy to sort by DESC/ASC." + "Type --sortingBySources to sort by sources.")
+		"\nType --query to filter articles by the search query with AND, OR, NOT, parentheses, \"phrases\", " +
+		"the fields title:, description: and source: and the prefix wildcards like ukr*. \n" +
+		"\nType --since and --until (or --startDate and --endDate) to filter by date. News published between the specified dates " +
+		"inclusive will be shown, either date may be omitted. Date format - yyyy-mm-dd, RFC3339 timestamp like " +
+		"2024-05-19T10:00:00+03:00 or duration before now like 24h or 7d. " +
+		"\nType --last to show the news of the duration before now like 24h. " +
+		"\nType --timezone to show the dates in the time zone like Europe/Kyiv. " +
+		"\nType --group=story to group the near-duplicate articles of the sources into the stories " +
+		"and --similarity to set the similarity of the articles of one story from 0 to 1. " +
+		"Type --sortBy to sort by DESC/ASC, by relevance to the keywords, or by the comma-separated keys like source:asc,date:desc,title:asc, the keys are date, title, source, relevance and length." + "Type --sortingBySources to sort by sources." +
+		"\nType --limit to show at most the number of the articles and --offset to skip the number of the articles, " +
+		"--cursor shows the page after the previous one by its cursor. " +
+		"\nType --pages to page through the articles, --limit sets the size of the page, 10 by default.")
+}
+
+// emphasise wraps the words of the text which match the comma-separated keywords or their
+// synonyms from the dictionary, which may be nil, in slashes. The words are compared by their stems
+// in the languages of their script, so the inflected forms of the keywords are emphasised in every
+// supported language.
+func emphasise(keywords, text string, synonyms *analysis.Synonyms) string {
+	if keywords == "" {
+		return text
+	}
+	var variants []string
+	for _, keyword := range strings.Split(keywords, ",") {
+		for _, variant := range analysis.KeywordVariants(keyword, synonyms) {
+			variants = append(variants, strings.Fields(variant)...)
+		}
+	}
+	if len(variants) == 0 {
+		return text
+	}
+
+	var result strings.Builder
+	runes := []rune(text)
+	for start := 0; start < len(runes); {
+		if !isWordRune(runes[start]) {
+			result.WriteRune(runes[start])
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+		word := string(runes[start:end])
+		if matchesVariant(word, variants) {
+			result.WriteString("//" + word + "//")
+		} else {
+			result.WriteString(word)
+		}
+		start = end
+	}
+	return result.String()
+}
+
+// matchesVariant reports whether any stem of the word contains the stem of the keyword.
+func matchesVariant(word string, variants []string) bool {
+	for _, normalized := range analysis.Words(word) {
+		for _, stem := range analysis.Stems(normalized) {
+			for _, variant := range variants {
+				if strings.Contains(stem, variant) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'' || r == '’' || r == 'ʼ'
 }
@@ -2,11 +2,18 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"github.com/golang/mock/gomock"
 	"io"
+	"news-aggregator/aggregation"
+	"news-aggregator/analysis"
 	"news-aggregator/client/mock_aggregator"
+	"news-aggregator/cluster"
 	"news-aggregator/entity/news"
 	"news-aggregator/filter"
+	"news-aggregator/pagination"
+	"news-aggregator/search"
+	"news-aggregator/sorter"
 	"os"
 	"reflect"
 	"strings"
@@ -46,10 +53,10 @@ func TestCommandLineClient_FetchArticles(t *testing.T) {
 			},
 			setup: func() {
 				mockAggregator.EXPECT().
-					Aggregate([]string{"source1", "source2"}, gomock.Any()).
-					Return([]news.News{
+					AggregatePage(gomock.Any(), []string{"source1", "source2"}, gomock.Any(), gomock.Any()).
+					Return(pagination.Page{News: []news.News{
 						{Title: "Test Title", Description: "Test Description", Link: "http://test.com", Date: time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)},
-					}, nil)
+					}}, aggregation.Result{}, nil)
 			},
 			want: []news.News{
 				{Title: "Test Title", Description: "Test Description", Link: "http://test.com", Date: time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)},
@@ -67,7 +74,7 @@ func TestCommandLineClient_FetchArticles(t *testing.T) {
 			},
 			setup: func() {
 				mockAggregator.EXPECT().
-					Aggregate([]string{""}, gomock.Any())
+					AggregatePage(gomock.Any(), []string{""}, gomock.Any(), gomock.Any())
 			},
 			want: nil,
 		},
@@ -84,17 +91,54 @@ func TestCommandLineClient_FetchArticles(t *testing.T) {
 				endDateStr:   tt.fields.endDateStr,
 				help:         tt.fields.help,
 			}
-			if got, _ := cli.FetchNews(); !reflect.DeepEqual(got, tt.want) {
+			if got, _ := cli.FetchNews(context.Background()); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Actual result = %v,expexted %v", got, tt.want)
 			}
 		})
 	}
 }
 
+func TestCommandLineClient_browsePages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAggregator := client.NewMockAggregator(ctrl)
+	articles := []news.News{
+		{Title: "First", Link: "http://test.com/1"},
+		{Title: "Second", Link: "http://test.com/2"},
+		{Title: "Third", Link: "http://test.com/3"},
+	}
+	mockAggregator.EXPECT().AggregatePage(gomock.Any(), []string{"bbc"}, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ []string, request pagination.Request, _ ...filter.NewsFilter) (pagination.Page, aggregation.Result, error) {
+			page, err := pagination.Paginate(articles, request)
+			return page, aggregation.Result{}, err
+		}).Times(4)
+
+	cli := &commandLineClient{aggregator: mockAggregator, sources: []string{"bbc"}, pages: true, pageRequest: pagination.Request{Limit: 1}, Sorter: sorter.MultiKeySorter{}}
+	got, err := cli.FetchNews(context.Background())
+	if err != nil || len(got) != 1 || got[0].Title != "First" {
+		t.Fatalf("FetchNews() got = %v, %v, want the first page", got, err)
+	}
+
+	var printed []string
+	var output bytes.Buffer
+	cli.browsePages(context.Background(), strings.NewReader("\nn\np\nq\n"), &output, func(page []news.News) {
+		for _, article := range page {
+			printed = append(printed, string(article.Title))
+		}
+	})
+	if want := []string{"Second", "Third", "Second"}; !reflect.DeepEqual(printed, want) {
+		t.Errorf("browsePages() printed = %v, want %v", printed, want)
+	}
+	if !strings.Contains(output.String(), "Page 3-3 of 3. p - previous page, q - quit") {
+		t.Errorf("browsePages() prompts = %q, want the prompt of the last page", output.String())
+	}
+}
+
 func TestFetchKeywords(t *testing.T) {
 	cli := &commandLineClient{keywords: "keyword1,keyword2"}
 	var filters []filter.NewsFilter
-	filters = buildKeywordFilter(cli.keywords, filters)
+	filters = buildKeywordFilter(cli.keywords, nil, filters)
 
 	expectedFilters := []filter.NewsFilter{
 		filter.ByKeyword{Keywords: []string{"keyword1", "keyword2"}},
@@ -108,10 +152,10 @@ func TestFetchKeywords(t *testing.T) {
 func TestFetchDateFilters(t *testing.T) {
 	cli := &commandLineClient{startDateStr: "2023-01-01", endDateStr: "2023-12-31"}
 	var filters []filter.NewsFilter
-	filters, _ = buildDateFilters(cli.startDateStr, cli.endDateStr, filters)
+	filters, _ = buildDateFilters(cli.startDateStr, cli.endDateStr, "", nil, filters)
 
 	startDate := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
-	endDate := time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2023, time.December, 31, 23, 59, 59, 999999999, time.UTC)
 	expectedFilters := []filter.NewsFilter{
 		filter.ByDate{StartDate: startDate, EndDate: endDate},
 	}
@@ -119,6 +163,106 @@ func TestFetchDateFilters(t *testing.T) {
 	if !reflect.DeepEqual(filters, expectedFilters) {
 		t.Errorf("buildDateFilters() failed, got: %v, want: %v", filters, expectedFilters)
 	}
+
+	kyiv := time.FixedZone("EEST", 3*60*60)
+	filters, _ = buildDateFilters("2023-01-01", "", "", kyiv, nil)
+	if len(filters) != 1 || !filters[0].(filter.ByDate).StartDate.Equal(time.Date(2023, time.January, 1, 0, 0, 0, 0, kyiv)) {
+		t.Errorf("buildDateFilters() failed, got: %v, want the start of the day in the time zone", filters)
+	}
+
+	keywordFilters := []filter.NewsFilter{filter.ByKeyword{Keywords: []string{"ukraine"}}}
+	filters, err := buildDateFilters("2023-12-31", "2023-01-01", "", nil, keywordFilters)
+	if err == nil || !reflect.DeepEqual(filters, keywordFilters) {
+		t.Errorf("buildDateFilters() = %v, %v, want the error and the unchanged filters", filters, err)
+	}
+}
+
+func TestFetchGroupFilter(t *testing.T) {
+	filters, err := buildGroupFilter("Story", "0.5", nil)
+	expectedFilters := []filter.NewsFilter{cluster.ByStory{Config: cluster.Config{Similarity: 0.5}}}
+	if err != nil || !reflect.DeepEqual(filters, expectedFilters) {
+		t.Errorf("buildGroupFilter() = %v, %v, want: %v", filters, err, expectedFilters)
+	}
+
+	filters, err = buildGroupFilter("story", "", nil)
+	expectedFilters = []filter.NewsFilter{cluster.ByStory{Config: cluster.DefaultConfig()}}
+	if err != nil || !reflect.DeepEqual(filters, expectedFilters) {
+		t.Errorf("buildGroupFilter() = %v, %v, want: %v", filters, err, expectedFilters)
+	}
+
+	if filters, err = buildGroupFilter("", "", nil); err != nil || filters != nil {
+		t.Errorf("buildGroupFilter() = %v, %v, want no filters", filters, err)
+	}
+	for _, args := range [][2]string{{"source", ""}, {"story", "1.5"}, {"story", "high"}} {
+		if _, err := buildGroupFilter(args[0], args[1], nil); err == nil {
+			t.Errorf("buildGroupFilter(%q, %q), expected error", args[0], args[1])
+		}
+	}
+}
+
+func TestInTimezone(t *testing.T) {
+	kyiv, err := loadTimezone("Europe/Kyiv")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	articles := []news.News{{Title: "News", Date: time.Date(2024, time.May, 19, 21, 30, 0, 0, time.UTC)}}
+
+	converted := inTimezone(articles, kyiv)
+	if got := converted[0].Date.Format(time.RFC3339); got != "2024-05-20T00:30:00+03:00" {
+		t.Errorf("inTimezone() date = %v, want the date in the time zone", got)
+	}
+	if articles[0].Date.Location() != time.UTC {
+		t.Errorf("inTimezone() changed the passed news")
+	}
+	if got := inTimezone(articles, nil); !reflect.DeepEqual(got, articles) {
+		t.Errorf("inTimezone() = %v, want the unchanged news", got)
+	}
+	if _, err := loadTimezone("Mars/Olympus"); err == nil {
+		t.Errorf("Expected error for the unknown time zone")
+	}
+}
+
+func TestFetchTextFilter(t *testing.T) {
+	filters := buildTextFilter("ukraine,kyiv,ukraine", "Relevance", nil, nil, nil)
+	expectedFilters := []filter.NewsFilter{
+		search.ByRelevance{Keywords: []string{"ukraine", "kyiv"}},
+	}
+	if !reflect.DeepEqual(filters, expectedFilters) {
+		t.Errorf("buildTextFilter() failed, got: %v, want: %v", filters, expectedFilters)
+	}
+
+	filters = buildTextFilter("ukraine", "source:asc,relevance", nil, nil, nil)
+	expectedFilters = []filter.NewsFilter{search.ByRelevance{Keywords: []string{"ukraine"}}}
+	if !reflect.DeepEqual(filters, expectedFilters) {
+		t.Errorf("buildTextFilter() failed, got: %v, want: %v", filters, expectedFilters)
+	}
+
+	filters = buildTextFilter("ukraine", "desc", nil, nil, nil)
+	expectedFilters = []filter.NewsFilter{filter.ByKeyword{Keywords: []string{"ukraine"}}}
+	if !reflect.DeepEqual(filters, expectedFilters) {
+		t.Errorf("buildTextFilter() failed, got: %v, want: %v", filters, expectedFilters)
+	}
+}
+
+func TestFetchQueryFilter(t *testing.T) {
+	filters, err := buildQueryFilter(`title:ukraine AND NOT "air alert"`, nil, nil)
+	if err != nil {
+		t.Fatalf("buildQueryFilter() error = %v", err)
+	}
+	if len(filters) != 1 || filters[0].(filter.ByQuery).Query != `title:ukraine AND NOT "air alert"` {
+		t.Errorf("buildQueryFilter() failed, got: %v", filters)
+	}
+
+	filters, err = buildQueryFilter(" ", nil, nil)
+	if err != nil || filters != nil {
+		t.Errorf("buildQueryFilter() for the empty query got: %v, %v", filters, err)
+	}
+
+	cli := &commandLineClient{query: "ukraine AND", Sorter: sorter.MultiKeySorter{}}
+	cli.filters, cli.queryError = buildQueryFilter(cli.query, nil, cli.filters)
+	if _, err := cli.FetchNews(context.Background()); err == nil || !strings.Contains(err.Error(), "column 12") {
+		t.Errorf("FetchNews() error = %v, want the error at column 12", err)
+	}
 }
 
 func TestCommandLineClient_printUsage(t *testing.T) {
@@ -127,9 +271,19 @@ func TestCommandLineClient_printUsage(t *testing.T) {
 		"\nType --sources, and then list the news you want to retrieve information from. " +
 		"The program supports such news news:\nABC, BBC, NBC, USA Today and Washington Times. \n" +
 		"\nType --keywords, and then list the keywords by which you want to filter articles. \n" +
-		"\nType --startDate and --endDate to filter by date. News published between the specified dates will be shown." +
-		"Date format - yyyy-mm-dd" + "" +
-		"Type --sortBy to sort by DESC/ASC." + "Type --sortingBySources to sort by sources."
+		"\nType --query to filter articles by the search query with AND, OR, NOT, parentheses, \"phrases\", " +
+		"the fields title:, description: and source: and the prefix wildcards like ukr*. \n" +
+		"\nType --since and --until (or --startDate and --endDate) to filter by date. News published between the specified dates " +
+		"inclusive will be shown, either date may be omitted. Date format - yyyy-mm-dd, RFC3339 timestamp like " +
+		"2024-05-19T10:00:00+03:00 or duration before now like 24h or 7d. " +
+		"\nType --last to show the news of the duration before now like 24h. " +
+		"\nType --timezone to show the dates in the time zone like Europe/Kyiv. " +
+		"\nType --group=story to group the near-duplicate articles of the sources into the stories " +
+		"and --similarity to set the similarity of the articles of one story from 0 to 1. " +
+		"Type --sortBy to sort by DESC/ASC, by relevance to the keywords, or by the comma-separated keys like source:asc,date:desc,title:asc, the keys are date, title, source, relevance and length." + "Type --sortingBySources to sort by sources." +
+		"\nType --limit to show at most the number of the articles and --offset to skip the number of the articles, " +
+		"--cursor shows the page after the previous one by its cursor. " +
+		"\nType --pages to page through the articles, --limit sets the size of the page, 10 by default."
 
 	var output bytes.Buffer
 	old := os.Stdout
@@ -180,3 +334,29 @@ func TestCheckUnique(t *testing.T) {
 		})
 	}
 }
+
+func TestEmphasise(t *testing.T) {
+	synonyms := analysis.NewSynonyms([][]string{{"kyiv", "київ", "києві"}})
+
+	tests := []struct {
+		name     string
+		keywords string
+		text     string
+		want     string
+	}{
+		{name: "Inflected English keyword", keywords: "attack", text: "Attacks on the city.",
+			want: "//Attacks// on the city."},
+		{name: "Synonym in Ukrainian", keywords: "Kyiv", text: "Тривога у Києві та області",
+			want: "Тривога у //Києві// та області"},
+		{name: "Several keywords", keywords: "war,ukraine", text: "The war in Ukraine",
+			want: "The //war// in //Ukraine//"},
+		{name: "Without keywords", keywords: "", text: "The war", want: "The war"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := emphasise(tt.keywords, tt.text, synonyms); got != tt.want {
+				t.Errorf("Actual result %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
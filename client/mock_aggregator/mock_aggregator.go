@@ -5,8 +5,10 @@
 package client
 
 import (
-	news "news-aggregator/entity/news"
+	context "context"
+	aggregation "news-aggregator/aggregation"
 	filter "news-aggregator/filter"
+	pagination "news-aggregator/pagination"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -36,21 +38,42 @@ func (m *MockAggregator) EXPECT() *MockAggregatorMockRecorder {
 }
 
 // Aggregate mocks base method.
-func (m *MockAggregator) Aggregate(sources []string, filters ...filter.NewsFilter) ([]news.News, error) {
+func (m *MockAggregator) Aggregate(ctx context.Context, sources []string, filters ...filter.NewsFilter) (aggregation.Result, error) {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{sources}
+	varargs := []interface{}{ctx, sources}
 	for _, a := range filters {
 		varargs = append(varargs, a)
 	}
 	ret := m.ctrl.Call(m, "Aggregate", varargs...)
-	ret0, _ := ret[0].([]news.News)
+	ret0, _ := ret[0].(aggregation.Result)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Aggregate indicates an expected call of Aggregate.
-func (mr *MockAggregatorMockRecorder) Aggregate(sources interface{}, filters ...interface{}) *gomock.Call {
+func (mr *MockAggregatorMockRecorder) Aggregate(ctx, sources interface{}, filters ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{sources}, filters...)
+	varargs := append([]interface{}{ctx, sources}, filters...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aggregate", reflect.TypeOf((*MockAggregator)(nil).Aggregate), varargs...)
 }
+
+// AggregatePage mocks base method.
+func (m *MockAggregator) AggregatePage(ctx context.Context, sources []string, request pagination.Request, filters ...filter.NewsFilter) (pagination.Page, aggregation.Result, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, sources, request}
+	for _, a := range filters {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AggregatePage", varargs...)
+	ret0, _ := ret[0].(pagination.Page)
+	ret1, _ := ret[1].(aggregation.Result)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AggregatePage indicates an expected call of AggregatePage.
+func (mr *MockAggregatorMockRecorder) AggregatePage(ctx, sources, request interface{}, filters ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, sources, request}, filters...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregatePage", reflect.TypeOf((*MockAggregator)(nil).AggregatePage), varargs...)
+}
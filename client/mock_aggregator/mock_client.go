@@ -5,6 +5,7 @@
 package client
 
 import (
+	context "context"
 	news "news-aggregator/entity/news"
 	reflect "reflect"
 
@@ -35,18 +36,18 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 }
 
 // FetchNews mocks base method.
-func (m *MockClient) FetchNews() ([]news.News, error) {
+func (m *MockClient) FetchNews(ctx context.Context) ([]news.News, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FetchNews")
+	ret := m.ctrl.Call(m, "FetchNews", ctx)
 	ret0, _ := ret[0].([]news.News)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FetchNews indicates an expected call of FetchNews.
-func (mr *MockClientMockRecorder) FetchNews() *gomock.Call {
+func (mr *MockClientMockRecorder) FetchNews(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchNews", reflect.TypeOf((*MockClient)(nil).FetchNews))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchNews", reflect.TypeOf((*MockClient)(nil).FetchNews), ctx)
 }
 
 // Print mocks base method.
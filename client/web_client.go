@@ -1,14 +1,24 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/sirupsen/logrus"
 	"net/http"
+	"net/url"
+	"news-aggregator/aggregation"
+	"news-aggregator/analysis"
 	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
 	"news-aggregator/filter"
+	"news-aggregator/pagination"
+	"news-aggregator/parser"
+	"news-aggregator/search"
 	"news-aggregator/sorter"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type WebClient struct {
@@ -17,60 +27,269 @@ type WebClient struct {
 	sortBy           string
 	sortingBySources bool
 	help             bool
-	DateSorter       sorter.DateSorter
+	debug            bool
+	meta             bool
+	queryError       error
+	dateError        error
+	groupError       error
+	pageError        error
+	pageRequest      pagination.Request
+	page             pagination.Page
+	requestURL       url.URL
+	location         *time.Location
+	result           aggregation.Result
+	Sorter           Sorter
 	filters          []filter.NewsFilter
 	output           http.ResponseWriter
 }
 
-// NewWebClient creates and initializes a new web client with the provided aggregator.
-func NewWebClient(r http.Request, w http.ResponseWriter, aggregator Aggregator) Client {
+// debugResponse is the body of the response in the debug mode.
+type debugResponse struct {
+	News        []news.News                         `json:"news"`
+	Diagnostics []parser.Diagnostic                 `json:"diagnostics"`
+	Sources     map[source.Name]parser.SourceStatus `json:"sources"`
+	Warnings    []string                            `json:"warnings"`
+	Page        *pageResponse                       `json:"page,omitempty"`
+	Meta        *metaResponse                       `json:"meta,omitempty"`
+}
+
+// newsResponse is the body of the response with the page of the news or the statistics of the aggregation.
+type newsResponse struct {
+	News []news.News   `json:"news"`
+	Page *pageResponse `json:"page,omitempty"`
+	Meta *metaResponse `json:"meta,omitempty"`
+}
+
+// metaResponse is the statistics of the aggregation: the number of the news and the time of the
+// parsing of every source, the numbers of the news before and after every filter, the number of
+// the collected news, the time of the whole aggregation in milliseconds and the warnings.
+type metaResponse struct {
+	Sources    []sourceMeta `json:"sources"`
+	Filters    []filterMeta `json:"filters"`
+	Collected  int          `json:"collected"`
+	DurationMs int64        `json:"durationMs"`
+	Warnings   []string     `json:"warnings"`
+}
+
+// sourceMeta is the statistics of the source in the meta block.
+type sourceMeta struct {
+	Name       source.Name  `json:"name"`
+	State      parser.State `json:"state"`
+	Articles   int          `json:"articles"`
+	DurationMs int64        `json:"durationMs"`
+	Error      string       `json:"error,omitempty"`
+}
+
+// filterMeta is the statistics of the filter in the meta block.
+type filterMeta struct {
+	Filter     string `json:"filter"`
+	Before     int    `json:"before"`
+	After      int    `json:"after"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// pageResponse is the position of the page of the news: the total number of the news, the number
+// of the news before the page and on the page, and the links of the next and the previous pages.
+type pageResponse struct {
+	Total    int    `json:"total"`
+	Offset   int    `json:"offset"`
+	Limit    int    `json:"limit,omitempty"`
+	Count    int    `json:"count"`
+	Next     string `json:"next,omitempty"`
+	Previous string `json:"prev,omitempty"`
+}
+
+// NewWebClient creates and initializes a new web client with the provided aggregator, the index
+// of the stored news which ranks the news by relevance and the dictionary of synonyms which
+// expands the keywords, the index and the dictionary may be nil.
+func NewWebClient(r http.Request, w http.ResponseWriter, aggregator Aggregator, index *search.Index, synonyms *analysis.Synonyms) Client {
 	queryParams := r.URL.Query()
 	webClient := &WebClient{aggregator: aggregator}
 	webClient.Sources = checkUnique(strings.Split(queryParams.Get("sources"), ","))
 	webClient.sortBy = queryParams.Get("sortBy")
 	webClient.sortingBySources = queryParams.Get("sortingBySources") == "true"
 	webClient.help = queryParams.Get("help") == "true"
-	webClient.DateSorter = sorter.DateSorter{}
-	webClient.filters = buildKeywordFilter(queryParams.Get("keywords"), webClient.filters)
-	filters, err := buildDateFilters(queryParams.Get("startDate"), queryParams.Get("endDate"), webClient.filters)
-	if err != nil {
-		logrus.Error("New web client initialization error: ", err)
+	webClient.debug = queryParams.Get("debug") == "true"
+	webClient.meta = queryParams.Get("meta") == "true"
+	webClient.Sorter = sorter.MultiKeySorter{}
+	webClient.filters = buildTextFilter(queryParams.Get("keywords"), webClient.sortBy, index, synonyms, webClient.filters)
+	webClient.filters, webClient.queryError = buildQueryFilter(queryParams.Get("q"), synonyms, webClient.filters)
+	webClient.location, webClient.dateError = loadTimezone(queryParams.Get("tz"))
+	if webClient.dateError == nil {
+		since := firstNonEmpty(queryParams.Get("since"), queryParams.Get("startDate"))
+		until := firstNonEmpty(queryParams.Get("until"), queryParams.Get("endDate"))
+		webClient.filters, webClient.dateError = buildDateFilters(since, until, queryParams.Get("last"), webClient.location, webClient.filters)
+	}
+	if webClient.dateError != nil {
+		logrus.Error("New web client initialization error: ", webClient.dateError)
+	}
+	webClient.filters, webClient.groupError = buildGroupFilter(queryParams.Get("group"), queryParams.Get("similarity"), webClient.filters)
+	if webClient.groupError != nil {
+		logrus.Error("New web client initialization error: ", webClient.groupError)
+	}
+	webClient.pageRequest, webClient.pageError = pagination.ParseRequest(queryParams.Get("limit"), queryParams.Get("offset"), queryParams.Get("cursor"))
+	if webClient.pageError != nil {
+		logrus.Error("New web client initialization error: ", webClient.pageError)
+	}
+	if r.URL != nil {
+		webClient.requestURL = *r.URL
 	}
-	webClient.filters = filters
 	webClient.output = w
 	logrus.Info("New web client initialized")
 	return webClient
 }
 
-// FetchNews retrieves articles based on arguments provided as params.
-func (webClient *WebClient) FetchNews() ([]news.News, error) {
+// FetchNews retrieves articles based on arguments provided as params. The aggregation
+// stops when the context of the request is done, like after the client disconnects.
+func (webClient *WebClient) FetchNews(ctx context.Context) ([]news.News, error) {
 	if webClient.help {
 		webClient.printUsage()
 		return nil, nil
 	}
+	if webClient.queryError != nil {
+		return nil, webClient.queryError
+	}
+	if webClient.dateError != nil {
+		return nil, webClient.dateError
+	}
+	if webClient.groupError != nil {
+		return nil, webClient.groupError
+	}
+	if webClient.pageError != nil {
+		return nil, webClient.pageError
+	}
 
-	articles, err := webClient.aggregator.Aggregate(webClient.Sources, webClient.filters...)
+	request := webClient.pageRequest
+	request.Order = func(articles []news.News) ([]news.News, error) {
+		return webClient.Sorter.SortNews(articles, webClient.sortBy)
+	}
+	page, result, err := webClient.aggregator.AggregatePage(ctx, webClient.Sources, request, webClient.filters...)
 	if err != nil {
 		return nil, err
 	}
-	logrus.Info("Web client: articles aggregate successfully. Length: ", len(articles))
-
-	articles, fetchParametersError := webClient.DateSorter.SortNews(articles, webClient.sortBy)
-	if fetchParametersError != nil {
-		return nil, fetchParametersError
-	}
-	return articles, nil
+	logrus.Info("Web client: articles aggregate successfully. Length: ", len(page.News), " of ", page.Total,
+		", duration: ", result.Duration)
+	webClient.result = result
+	webClient.page = page
+	result.Report.Log()
+	return page.News, nil
 }
 
+// Print writes the news to the response as the JSON array. The sources which failed are
+// listed in the Warning headers. If the page of the news is requested, the news are wrapped into
+// the object with the page, which has the total number of the news and the links of the next and
+// the previous pages, the total number and the links are also set in the X-Total-Count and the Link
+// headers. If the meta block is requested, the news are wrapped into the object with the meta, which
+// has the statistics of the sources and the filters. If the debug mode is enabled, the news are
+// wrapped into the object with the diagnostics of the parsers, the statuses of the sources,
+// the warnings, the page and the meta.
 func (webClient *WebClient) Print(news []news.News) {
 	webClient.output.Header().Set("Content-Type", "application/json")
-	err := json.NewEncoder(webClient.output).Encode(news)
+	warnings := webClient.result.Warnings()
+	for _, warning := range warnings {
+		webClient.output.Header().Add("Warning", fmt.Sprintf("199 news-aggregator %q", warning))
+	}
+	news = inTimezone(news, webClient.location)
+	var body any = news
+	var page *pageResponse
+	if webClient.pageRequest.Paged() {
+		page = webClient.pageResponse(len(news))
+		webClient.output.Header().Set("X-Total-Count", strconv.Itoa(page.Total))
+		var links []string
+		if page.Next != "" {
+			links = append(links, fmt.Sprintf("<%s>; rel=\"next\"", page.Next))
+		}
+		if page.Previous != "" {
+			links = append(links, fmt.Sprintf("<%s>; rel=\"prev\"", page.Previous))
+		}
+		if len(links) > 0 {
+			webClient.output.Header().Set("Link", strings.Join(links, ", "))
+		}
+	}
+	var meta *metaResponse
+	if webClient.meta {
+		meta = webClient.metaResponse(warnings)
+	}
+	if page != nil || meta != nil {
+		body = newsResponse{News: news, Page: page, Meta: meta}
+	}
+	if webClient.debug {
+		diagnostics := webClient.result.Report.Diagnostics
+		if diagnostics == nil {
+			diagnostics = []parser.Diagnostic{}
+		}
+		sources := webClient.result.Report.Sources
+		if sources == nil {
+			sources = map[source.Name]parser.SourceStatus{}
+		}
+		if warnings == nil {
+			warnings = []string{}
+		}
+		body = debugResponse{News: news, Diagnostics: diagnostics, Sources: sources, Warnings: warnings, Page: page, Meta: meta}
+	}
+	err := json.NewEncoder(webClient.output).Encode(body)
 	if err != nil {
 		logrus.Error("Failed to encode json: ", err)
 		http.Error(webClient.output, "Failed to encode json: "+err.Error(), http.StatusInternalServerError)
 	}
 }
 
+// metaResponse returns the statistics of the aggregation of the fetched news.
+func (webClient *WebClient) metaResponse(warnings []string) *metaResponse {
+	meta := &metaResponse{
+		Sources:    []sourceMeta{},
+		Filters:    []filterMeta{},
+		Collected:  webClient.result.Collected,
+		DurationMs: webClient.result.Duration.Milliseconds(),
+		Warnings:   []string{},
+	}
+	for _, stats := range webClient.result.Sources {
+		meta.Sources = append(meta.Sources, sourceMeta{
+			Name:       stats.Name,
+			State:      stats.State,
+			Articles:   stats.Articles,
+			DurationMs: stats.Duration.Milliseconds(),
+			Error:      stats.Error,
+		})
+	}
+	for _, stats := range webClient.result.Filters {
+		meta.Filters = append(meta.Filters, filterMeta{
+			Filter:     stats.Filter,
+			Before:     stats.Before,
+			After:      stats.After,
+			DurationMs: stats.Duration.Milliseconds(),
+		})
+	}
+	meta.Warnings = append(meta.Warnings, warnings...)
+	return meta
+}
+
+// pageResponse returns the position of the fetched page with the count of its news.
+func (webClient *WebClient) pageResponse(count int) *pageResponse {
+	return &pageResponse{
+		Total:    webClient.page.Total,
+		Offset:   webClient.page.Offset,
+		Limit:    webClient.page.Limit,
+		Count:    count,
+		Next:     webClient.pageLink(webClient.page.Next),
+		Previous: webClient.pageLink(webClient.page.Previous),
+	}
+}
+
+// pageLink returns the link of the request with the cursor instead of the offset,
+// or the empty string if the cursor is empty.
+func (webClient *WebClient) pageLink(cursor string) string {
+	if cursor == "" {
+		return ""
+	}
+	link := url.URL{Path: webClient.requestURL.Path}
+	query := webClient.requestURL.Query()
+	query.Del("offset")
+	query.Set("cursor", cursor)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
+
 // printUsage prints the usage instructions
 func (webClient *WebClient) printUsage() {
 	webClient.output.Header().Set("Content-Type", "text/plain")
@@ -78,11 +297,32 @@ func (webClient *WebClient) printUsage() {
 		"\nType --sources, and then list the news you want to retrieve information from. "+
 		"The program supports such news news:\nABC, BBC, NBC, USA Today and Washington Times. \n"+
 		"\nType --keywords, and then list the keywords by which you want to filter articles. \n"+
-		"\nType --startDate and --endDate to filter by date. News published between the specified dates will be shown."+
-		"Date format - yyyy-mm-dd"+
-		"\nType --sortBy to sort by DESC/ASC."+
-		"\nType --sortingBySources to sort by sources.")
+		"\nType --q to filter articles by the search query with AND, OR, NOT, parentheses, \"phrases\", "+
+		"the fields title:, description: and source: and the prefix wildcards like ukr*. \n"+
+		"\nType --since and --until (or --startDate and --endDate) to filter by date. News published between the specified dates "+
+		"inclusive will be shown, either date may be omitted. Date format - yyyy-mm-dd, RFC3339 timestamp like "+
+		"2024-05-19T10:00:00+03:00 or duration before now like 24h or 7d."+
+		"\nType --last to show the news of the duration before now like 24h."+
+		"\nType --tz to show the dates in the time zone like Europe/Kyiv."+
+		"\nType --group=story to group the near-duplicate articles of the sources into the stories "+
+		"and --similarity to set the similarity of the articles of one story from 0 to 1."+
+		"\nType --sortBy to sort by DESC/ASC, by relevance to the keywords, or by the comma-separated keys like source:asc,date:desc,title:asc, the keys are date, title, source, relevance and length."+
+		"\nType --sortingBySources to sort by sources."+
+		"\nType --debug to get the diagnostics of the skipped and repaired items and the statuses of the sources with the news."+
+		"\nType --meta to get the numbers of the news and the durations of the sources, the numbers of the news before and after the filters and the warnings with the news."+
+		"\nType --limit to get at most the number of the articles and --offset to skip the number of the articles, "+
+		"the response has the total number of the articles and the links of the next and the previous pages with the --cursor.")
 	if err != nil {
 		return
 	}
 }
+
+// firstNonEmpty returns the first of the values which is not empty.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
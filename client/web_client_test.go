@@ -1,13 +1,20 @@
 package client
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/golang/mock/gomock"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"news-aggregator/aggregation"
 	"news-aggregator/client/mock_aggregator"
 	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
 	"news-aggregator/filter"
+	"news-aggregator/pagination"
+	"news-aggregator/parser"
 	"news-aggregator/sorter"
 	"reflect"
 	"strings"
@@ -27,7 +34,7 @@ func TestWebClient_FetchNews(t *testing.T) {
 		sortBy           string
 		sortingBySources bool
 		help             bool
-		DateSorter       sorter.DateSorter
+		Sorter           Sorter
 		filters          []filter.NewsFilter
 		output           http.ResponseWriter
 	}
@@ -45,6 +52,7 @@ func TestWebClient_FetchNews(t *testing.T) {
 				Sources:          []string{"source1", "source2"},
 				sortBy:           "desc",
 				sortingBySources: true,
+				Sorter:           sorter.MultiKeySorter{},
 				filters: []filter.NewsFilter{
 					filter.ByKeyword{Keywords: []string{"test"}},
 					filter.ByDate{StartDate: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC)},
@@ -53,10 +61,10 @@ func TestWebClient_FetchNews(t *testing.T) {
 			},
 			setup: func() {
 				mockAggregator.EXPECT().
-					Aggregate([]string{"source1", "source2"}, gomock.Any()).
-					Return([]news.News{
+					AggregatePage(gomock.Any(), []string{"source1", "source2"}, gomock.Any(), gomock.Any()).
+					Return(pagination.Page{News: []news.News{
 						{Title: "Test Title", Description: "Test Description", Link: "http://test.com", Date: time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)},
-					}, nil)
+					}}, aggregation.Result{}, nil)
 			},
 			want: []news.News{
 				{Title: "Test Title", Description: "Test Description", Link: "http://test.com", Date: time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)},
@@ -70,6 +78,7 @@ func TestWebClient_FetchNews(t *testing.T) {
 				Sources:          []string{""},
 				sortBy:           "desc",
 				sortingBySources: true,
+				Sorter:           sorter.MultiKeySorter{},
 				filters: []filter.NewsFilter{
 					filter.ByKeyword{Keywords: []string{"test"}},
 					filter.ByDate{StartDate: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC)},
@@ -78,8 +87,8 @@ func TestWebClient_FetchNews(t *testing.T) {
 			},
 			setup: func() {
 				mockAggregator.EXPECT().
-					Aggregate([]string{""}, gomock.Any()).
-					Return(nil, fmt.Errorf("aggregation error"))
+					AggregatePage(gomock.Any(), []string{""}, gomock.Any(), gomock.Any()).
+					Return(pagination.Page{}, aggregation.Result{}, fmt.Errorf("aggregation error"))
 			},
 			want:    nil,
 			wantErr: true,
@@ -95,11 +104,11 @@ func TestWebClient_FetchNews(t *testing.T) {
 				sortBy:           tt.fields.sortBy,
 				sortingBySources: tt.fields.sortingBySources,
 				help:             tt.fields.help,
-				DateSorter:       tt.fields.DateSorter,
+				Sorter:           tt.fields.Sorter,
 				filters:          tt.fields.filters,
 				output:           tt.fields.output,
 			}
-			got, err := webClient.FetchNews()
+			got, err := webClient.FetchNews(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("FetchNews() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -111,18 +120,191 @@ func TestWebClient_FetchNews(t *testing.T) {
 	}
 }
 
+func TestNewWebClient_Query(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAggregator := client.NewMockAggregator(ctrl)
+
+	request := httptest.NewRequest(http.MethodGet, "/news?sources=bbc&q="+url.QueryEscape("title:(ukraine OR kyiv"), nil)
+	webClient := NewWebClient(*request, httptest.NewRecorder(), mockAggregator, nil, nil)
+	_, err := webClient.FetchNews(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "column 7") {
+		t.Errorf("FetchNews() error = %v, want the error at column 7", err)
+	}
+
+	articles := []news.News{{Title: "Ukraine"}, {Title: "Kyiv"}, {Title: "Football"}}
+	mockAggregator.EXPECT().AggregatePage(gomock.Any(), []string{"bbc"}, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ []string, request pagination.Request, filters ...filter.NewsFilter) (pagination.Page, aggregation.Result, error) {
+			filtered := articles
+			for _, newsFilter := range filters {
+				filtered = newsFilter.Filter(filtered)
+			}
+			page, err := pagination.Paginate(filtered, request)
+			return page, aggregation.Result{}, err
+		})
+	request = httptest.NewRequest(http.MethodGet, "/news?sources=bbc&q="+url.QueryEscape("title:(ukraine OR kyiv)"), nil)
+	webClient = NewWebClient(*request, httptest.NewRecorder(), mockAggregator, nil, nil)
+	got, err := webClient.FetchNews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchNews() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, articles[:2]) {
+		t.Errorf("FetchNews() got = %v, want %v", got, articles[:2])
+	}
+}
+
+func TestNewWebClient_Dates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAggregator := client.NewMockAggregator(ctrl)
+
+	for _, query := range []string{"since=2024-05-20&until=2024-05-19", "last=forever", "tz=Mars/Olympus"} {
+		request := httptest.NewRequest(http.MethodGet, "/news?sources=bbc&"+query, nil)
+		if _, err := NewWebClient(*request, httptest.NewRecorder(), mockAggregator, nil, nil).FetchNews(context.Background()); err == nil {
+			t.Errorf("FetchNews() with %s, expected error", query)
+		}
+	}
+
+	now := time.Now()
+	articles := []news.News{{Title: "Recent", Date: now.Add(-time.Hour)}, {Title: "Old", Date: now.AddDate(0, 0, -3)}}
+	mockAggregator.EXPECT().AggregatePage(gomock.Any(), []string{"bbc"}, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ []string, request pagination.Request, filters ...filter.NewsFilter) (pagination.Page, aggregation.Result, error) {
+			filtered := articles
+			for _, newsFilter := range filters {
+				filtered = newsFilter.Filter(filtered)
+			}
+			page, err := pagination.Paginate(filtered, request)
+			return page, aggregation.Result{}, err
+		})
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/news?sources=bbc&last=24h&tz=Europe/Kyiv", nil)
+	webClient := NewWebClient(*request, recorder, mockAggregator, nil, nil)
+	got, err := webClient.FetchNews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchNews() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, articles[:1]) {
+		t.Errorf("FetchNews() got = %v, want %v", got, articles[:1])
+	}
+
+	webClient.Print(got)
+	var printed []news.News
+	if err := json.NewDecoder(recorder.Body).Decode(&printed); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	kyiv, _ := time.LoadLocation("Europe/Kyiv")
+	_, expectedOffset := got[0].Date.In(kyiv).Zone()
+	if _, offset := printed[0].Date.Zone(); len(printed) != 1 || offset != expectedOffset {
+		t.Errorf("Print() dates = %v, want the dates in the time zone", printed)
+	}
+}
+
+func TestNewWebClient_Pages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAggregator := client.NewMockAggregator(ctrl)
+
+	for _, query := range []string{"limit=0", "offset=-1", "cursor=unknown"} {
+		request := httptest.NewRequest(http.MethodGet, "/news?sources=bbc&"+query, nil)
+		if _, err := NewWebClient(*request, httptest.NewRecorder(), mockAggregator, nil, nil).FetchNews(context.Background()); err == nil {
+			t.Errorf("FetchNews() with %s, expected error", query)
+		}
+	}
+
+	articles := []news.News{
+		{Title: "First", Link: "http://test.com/1", Date: time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)},
+		{Title: "Second", Link: "http://test.com/2", Date: time.Date(2023, time.May, 2, 0, 0, 0, 0, time.UTC)},
+		{Title: "Third", Link: "http://test.com/3", Date: time.Date(2023, time.May, 3, 0, 0, 0, 0, time.UTC)},
+	}
+	mockAggregator.EXPECT().AggregatePage(gomock.Any(), []string{"bbc"}, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ []string, request pagination.Request, _ ...filter.NewsFilter) (pagination.Page, aggregation.Result, error) {
+			page, err := pagination.Paginate(append([]news.News(nil), articles...), request)
+			return page, aggregation.Result{}, err
+		}).Times(2)
+
+	fetchPage := func(target string) (*httptest.ResponseRecorder, newsResponse) {
+		recorder := httptest.NewRecorder()
+		webClient := NewWebClient(*httptest.NewRequest(http.MethodGet, target, nil), recorder, mockAggregator, nil, nil)
+		got, err := webClient.FetchNews(context.Background())
+		if err != nil {
+			t.Fatalf("FetchNews() error = %v", err)
+		}
+		webClient.Print(got)
+		var response newsResponse
+		if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return recorder, response
+	}
+
+	recorder, first := fetchPage("/news?sources=bbc&sortBy=desc&limit=2&offset=0")
+	if len(first.News) != 2 || first.News[0].Title != "Third" || first.Page.Total != 3 || first.Page.Count != 2 {
+		t.Errorf("Print() got = %+v, want the first page of the news from the newest", first)
+	}
+	if !strings.HasPrefix(first.Page.Next, "/news?cursor=") || strings.Contains(first.Page.Next, "offset") || first.Page.Previous != "" {
+		t.Errorf("Print() page links = %+v, want only the link of the next page with the cursor", first.Page)
+	}
+	if recorder.Header().Get("X-Total-Count") != "3" || recorder.Header().Get("Link") != "<"+first.Page.Next+">; rel=\"next\"" {
+		t.Errorf("Print() headers = %v, want the total count and the link of the next page", recorder.Header())
+	}
+
+	_, second := fetchPage(first.Page.Next)
+	if len(second.News) != 1 || second.News[0].Title != "First" || second.Page.Offset != 2 || second.Page.Next != "" || second.Page.Previous == "" {
+		t.Errorf("Print() got = %+v, want the last page with the link of the previous page", second)
+	}
+}
+
+func TestNewWebClient_SortKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAggregator := client.NewMockAggregator(ctrl)
+
+	articles := []news.News{
+		{Title: "b", SourceName: "nbc", Date: time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)},
+		{Title: "a", SourceName: "bbc", Date: time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)},
+		{Title: "c", SourceName: "bbc", Date: time.Date(2023, time.May, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	mockAggregator.EXPECT().AggregatePage(gomock.Any(), []string{"bbc", "nbc"}, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ []string, request pagination.Request, _ ...filter.NewsFilter) (pagination.Page, aggregation.Result, error) {
+			page, err := pagination.Paginate(append([]news.News(nil), articles...), request)
+			return page, aggregation.Result{}, err
+		}).Times(2)
+
+	request := httptest.NewRequest(http.MethodGet, "/news?sources=bbc,nbc&sortBy="+url.QueryEscape("source:asc,date:desc"), nil)
+	got, err := NewWebClient(*request, httptest.NewRecorder(), mockAggregator, nil, nil).FetchNews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchNews() error = %v", err)
+	}
+	var titles []string
+	for _, article := range got {
+		titles = append(titles, string(article.Title))
+	}
+	if want := []string{"c", "a", "b"}; !reflect.DeepEqual(titles, want) {
+		t.Errorf("FetchNews() titles = %v, want %v", titles, want)
+	}
+
+	request = httptest.NewRequest(http.MethodGet, "/news?sources=bbc,nbc&sortBy=author", nil)
+	if _, err := NewWebClient(*request, httptest.NewRecorder(), mockAggregator, nil, nil).FetchNews(context.Background()); err == nil || !strings.Contains(err.Error(), "supported keys") {
+		t.Errorf("FetchNews() error = %v, want the error with the supported keys", err)
+	}
+}
+
 func TestWebClient_Print(t *testing.T) {
 	type fields struct {
 		output http.ResponseWriter
+		debug  bool
+		meta   bool
+		result aggregation.Result
 	}
 	type args struct {
 		news []news.News
 	}
 	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		want   string
+		name         string
+		fields       fields
+		args         args
+		want         string
+		wantWarnings []string
 	}{
 		{
 			name: "Valid Print",
@@ -131,10 +313,95 @@ func TestWebClient_Print(t *testing.T) {
 			},
 			args: args{
 				news: []news.News{
-					{Title: "Test Title", Description: "Test Description", Link: "http://test.com", Date: time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)},
+					{ID: "1f8a8ab796bf77ce", Title: "Test Title", Description: "Test Description", Link: "http://test.com", Date: time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)},
 				},
 			},
-			want: `[{"title":"Test Title","description":"Test Description","url":"http://test.com","publishedAt":"2023-05-01T00:00:00Z","SourceName":""}]`,
+			want: `[{"id":"1f8a8ab796bf77ce","title":"Test Title","description":"Test Description","url":"http://test.com","publishedAt":"2023-05-01T00:00:00Z","SourceName":""}]`,
+		},
+		{
+			name: "Print with diagnostics in the debug mode",
+			fields: fields{
+				output: httptest.NewRecorder(),
+				debug:  true,
+				result: aggregation.Result{Report: parser.Report{Diagnostics: []parser.Diagnostic{
+					{Source: "bbc", Item: "Test Title", Action: parser.Repaired, Reason: "the publication date is missing", Fallback: parser.FallbackFetchTime},
+				}}},
+			},
+			args: args{
+				news: []news.News{
+					{ID: "1f8a8ab796bf77ce", Title: "Test Title", Link: "http://test.com", Date: time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)},
+				},
+			},
+			want: `{"news":[{"id":"1f8a8ab796bf77ce","title":"Test Title","description":"","url":"http://test.com","publishedAt":"2023-05-01T00:00:00Z","SourceName":""}],` +
+				`"diagnostics":[{"source":"bbc","item":"Test Title","action":"repaired","reason":"the publication date is missing","fallback":"fetch time"}],` +
+				`"sources":{},"warnings":[]}`,
+		},
+		{
+			name: "Print without diagnostics in the debug mode",
+			fields: fields{
+				output: httptest.NewRecorder(),
+				debug:  true,
+			},
+			want: `{"news":null,"diagnostics":[],"sources":{},"warnings":[]}`,
+		},
+		{
+			name: "Print partial news with the warnings of the failed sources",
+			fields: fields{
+				output: httptest.NewRecorder(),
+				result: aggregation.Result{Report: parser.Report{Sources: map[source.Name]parser.SourceStatus{
+					"bbc": {State: parser.Collected, Articles: 1},
+					"nbc": {State: parser.TimedOut, Error: "context deadline exceeded"},
+				}}},
+			},
+			args: args{
+				news: []news.News{
+					{ID: "1f8a8ab796bf77ce", Title: "Test Title", Link: "http://test.com", Date: time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)},
+				},
+			},
+			want:         `[{"id":"1f8a8ab796bf77ce","title":"Test Title","description":"","url":"http://test.com","publishedAt":"2023-05-01T00:00:00Z","SourceName":""}]`,
+			wantWarnings: []string{`199 news-aggregator "source nbc timeout: context deadline exceeded"`},
+		},
+		{
+			name: "Print statuses of the sources in the debug mode",
+			fields: fields{
+				output: httptest.NewRecorder(),
+				debug:  true,
+				result: aggregation.Result{Report: parser.Report{Sources: map[source.Name]parser.SourceStatus{
+					"bbc": {State: parser.Collected, Articles: 0},
+					"nbc": {State: parser.Failed, Error: "file not found"},
+				}}},
+			},
+			want: `{"news":null,"diagnostics":[],"sources":{"bbc":{"state":"ok","articles":0},"nbc":{"state":"failed","articles":0,"error":"file not found"}},` +
+				`"warnings":["source nbc failed: file not found"]}`,
+			wantWarnings: []string{`199 news-aggregator "source nbc failed: file not found"`},
+		},
+		{
+			name: "Print statistics of the sources and the filters in the meta block",
+			fields: fields{
+				output: httptest.NewRecorder(),
+				meta:   true,
+				result: aggregation.Result{
+					Report: parser.Report{Sources: map[source.Name]parser.SourceStatus{
+						"nbc": {State: parser.Failed, Error: "file not found"},
+					}},
+					Sources: []aggregation.SourceStats{
+						{Name: "bbc", State: parser.Collected, Articles: 2, Duration: 35 * time.Millisecond},
+						{Name: "nbc", State: parser.Failed, Duration: time.Millisecond, Error: "file not found"},
+					},
+					Filters:   []aggregation.FilterStats{{Filter: "filter.ByKeyword", Before: 2, After: 1, Duration: 2 * time.Millisecond}},
+					Collected: 2,
+					Duration:  40 * time.Millisecond,
+				},
+			},
+			args: args{
+				news: []news.News{
+					{ID: "1f8a8ab796bf77ce", Title: "Test Title", Link: "http://test.com", Date: time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)},
+				},
+			},
+			want: `{"news":[{"id":"1f8a8ab796bf77ce","title":"Test Title","description":"","url":"http://test.com","publishedAt":"2023-05-01T00:00:00Z","SourceName":""}],` +
+				`"meta":{"sources":[{"name":"bbc","state":"ok","articles":2,"durationMs":35},{"name":"nbc","state":"failed","articles":0,"durationMs":1,"error":"file not found"}],` +
+				`"filters":[{"filter":"filter.ByKeyword","before":2,"after":1,"durationMs":2}],"collected":2,"durationMs":40,"warnings":["source nbc failed: file not found"]}}`,
+			wantWarnings: []string{`199 news-aggregator "source nbc failed: file not found"`},
 		},
 	}
 
@@ -142,12 +409,18 @@ func TestWebClient_Print(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			webClient := &WebClient{
 				output: tt.fields.output,
+				debug:  tt.fields.debug,
+				meta:   tt.fields.meta,
+				result: tt.fields.result,
 			}
 			webClient.Print(tt.args.news)
 			result := webClient.output.(*httptest.ResponseRecorder).Body.String()
 			if strings.TrimSpace(result) != tt.want {
 				t.Errorf("Print() got = %v, want %v", result, tt.want)
 			}
+			if warnings := webClient.output.Header().Values("Warning"); !reflect.DeepEqual(warnings, tt.wantWarnings) {
+				t.Errorf("Print() warnings = %v, want %v", warnings, tt.wantWarnings)
+			}
 		})
 	}
 }
@@ -170,10 +443,21 @@ func TestWebClient_printUsage(t *testing.T) {
 				"Type --sources, and then list the news you want to retrieve information from. " +
 				"The program supports such news news:\nABC, BBC, NBC, USA Today and Washington Times. \n" +
 				"\nType --keywords, and then list the keywords by which you want to filter articles. \n" +
-				"\nType --startDate and --endDate to filter by date. News published between the specified dates will be shown." +
-				"Date format - yyyy-mm-dd" +
-				"\nType --sortBy to sort by DESC/ASC." +
-				"\nType --sortingBySources to sort by sources.",
+				"\nType --q to filter articles by the search query with AND, OR, NOT, parentheses, \"phrases\", " +
+				"the fields title:, description: and source: and the prefix wildcards like ukr*. \n" +
+				"\nType --since and --until (or --startDate and --endDate) to filter by date. News published between the specified dates " +
+				"inclusive will be shown, either date may be omitted. Date format - yyyy-mm-dd, RFC3339 timestamp like " +
+				"2024-05-19T10:00:00+03:00 or duration before now like 24h or 7d." +
+				"\nType --last to show the news of the duration before now like 24h." +
+				"\nType --tz to show the dates in the time zone like Europe/Kyiv." +
+				"\nType --group=story to group the near-duplicate articles of the sources into the stories " +
+				"and --similarity to set the similarity of the articles of one story from 0 to 1." +
+				"\nType --sortBy to sort by DESC/ASC, by relevance to the keywords, or by the comma-separated keys like source:asc,date:desc,title:asc, the keys are date, title, source, relevance and length." +
+				"\nType --sortingBySources to sort by sources." +
+				"\nType --debug to get the diagnostics of the skipped and repaired items and the statuses of the sources with the news." +
+				"\nType --meta to get the numbers of the news and the durations of the sources, the numbers of the news before and after the filters and the warnings with the news." +
+				"\nType --limit to get at most the number of the articles and --offset to skip the number of the articles, " +
+				"the response has the total number of the articles and the links of the next and the previous pages with the --cursor.",
 		},
 	}
 
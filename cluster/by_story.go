@@ -0,0 +1,29 @@
+package cluster
+
+import (
+	"news-aggregator/entity/news"
+)
+
+// ByStory replaces the near-duplicate news with the representatives of their stories.
+// The representative keeps the sources of its story and the IDs of the other news of the story.
+type ByStory struct {
+	Config Config
+}
+
+// Filter returns the representatives of the stories of the news in the order of the stories.
+func (storyFilter ByStory) Filter(articles []news.News) []news.News {
+	stories := Group(articles, storyFilter.Config)
+	representatives := make([]news.News, 0, len(stories))
+	for _, story := range stories {
+		representative := story.News
+		representative.Sources = story.Sources
+		representative.Related = nil
+		for i, article := range story.Articles {
+			if i != story.representative {
+				representative.Related = append(representative.Related, article.WithID().ID)
+			}
+		}
+		representatives = append(representatives, representative)
+	}
+	return representatives
+}
@@ -0,0 +1,12 @@
+// Package cluster groups the near-duplicate news of the different sources into the stories.
+//
+// The news are normalized into the sets of the stemmed terms of their titles and descriptions
+// in the language of the news. The MinHash signatures of the sets are split into the bands, and
+// the news which share a band are compared by the Jaccard similarity of their terms, or of the
+// terms of their titles alone. The candidates are merged from the most similar, and the news belong
+// to one story only if every two news of the story have the similarity above the threshold of
+// the Config. Every story has the representative news, which is the most similar to the
+// other news of the story, and the list of the sources which cover it. ByStory is the filter
+// which replaces the news with the representatives of their stories, it is used by the clients
+// when the news are grouped by story.
+package cluster
@@ -0,0 +1,70 @@
+package cluster
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// The MinHash signature has signatureLength minimal hashes split into the bands of bandRows
+// hashes. The news which share all hashes of any band are the candidates for one story; the
+// short bands keep the news with the low similarity of the terms as the candidates.
+const (
+	signatureLength = 128
+	bandRows        = 2
+)
+
+// signature is the MinHash signature of the set of the terms.
+type signature [signatureLength]uint32
+
+// newSignature returns the MinHash signature of the terms. The hash functions are the
+// combinations of the two halves of the 64-bit FNV hash of the term.
+func newSignature(terms map[string]struct{}) signature {
+	var minimums signature
+	for i := range minimums {
+		minimums[i] = math.MaxUint32
+	}
+	for term := range terms {
+		hasher := fnv.New64a()
+		hasher.Write([]byte(term))
+		hash := hasher.Sum64()
+		first, second := uint32(hash), uint32(hash>>32)
+		for i := range minimums {
+			if value := first + uint32(i)*second; value < minimums[i] {
+				minimums[i] = value
+			}
+		}
+	}
+	return minimums
+}
+
+// band is the key of the band of the signature.
+type band struct {
+	titles bool
+	index  int
+	hashes [bandRows]uint32
+}
+
+// bands returns the keys of the bands of the signature.
+func (minimums signature) bands(titles bool) []band {
+	keys := make([]band, 0, signatureLength/bandRows)
+	for start := 0; start+bandRows <= signatureLength; start += bandRows {
+		key := band{titles: titles, index: start / bandRows}
+		copy(key.hashes[:], minimums[start:start+bandRows])
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// jaccard returns the share of the common terms of the sets in all their terms.
+func jaccard(first, second map[string]struct{}) float64 {
+	if len(first) == 0 || len(second) == 0 {
+		return 0
+	}
+	common := 0
+	for term := range first {
+		if _, exists := second[term]; exists {
+			common++
+		}
+	}
+	return float64(common) / float64(len(first)+len(second)-common)
+}
@@ -0,0 +1,222 @@
+package cluster
+
+import (
+	"cmp"
+	"news-aggregator/analysis"
+	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
+	"slices"
+)
+
+// minimumTerms is the number of the terms of the news which is enough to compare them by
+// the share of the common terms. The shorter texts, like "Watch now", are similar to too many
+// other texts, so they belong to one story only with the same terms. The titles with fewer
+// terms are not compared alone.
+const minimumTerms = 3
+
+// GroupByStory is the grouping parameter of the news by story.
+const GroupByStory = "story"
+
+// Config holds the thresholds of the grouping of the news into the stories.
+type Config struct {
+	// Similarity is the minimum Jaccard similarity of the terms of the news of one story,
+	// from 0 to 1. The higher similarity groups only the news with the closer wording.
+	Similarity float64
+}
+
+// DefaultConfig returns the configuration which groups the news of the different sources
+// about the same event, whose short texts have about a third of the terms in common.
+func DefaultConfig() Config {
+	return Config{Similarity: 0.3}
+}
+
+// Story is the group of the near-duplicate news.
+type Story struct {
+	// News is the representative news of the story.
+	News news.News
+	// Articles are all news of the story in their original order, including the representative.
+	Articles []news.News
+	// Sources are the names of the sources which cover the story without the duplicates.
+	Sources []source.Name
+	// representative is the index of the representative news in the Articles.
+	representative int
+}
+
+// document is the normalized news: the sets of the terms of its text and of its title.
+type document struct {
+	terms      map[string]struct{}
+	titleTerms map[string]struct{}
+}
+
+func newDocument(article news.News) document {
+	language := analysis.LanguageOf(article)
+	doc := document{
+		terms:      termSet(analysis.Terms(string(article.Title)+" "+string(article.Description), language)),
+		titleTerms: termSet(analysis.Terms(string(article.Title), language)),
+	}
+	if len(doc.titleTerms) < minimumTerms {
+		doc.titleTerms = nil
+	}
+	return doc
+}
+
+func termSet(terms []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(terms))
+	for _, term := range terms {
+		set[term] = struct{}{}
+	}
+	return set
+}
+
+// similarity returns the Jaccard similarity of the terms of the documents,
+// or of the terms of their titles if they are closer.
+func (doc document) similarity(other document) float64 {
+	similarity := jaccard(doc.terms, other.terms)
+	if (len(doc.terms) < minimumTerms || len(other.terms) < minimumTerms) && similarity < 1 {
+		return 0
+	}
+	return max(similarity, jaccard(doc.titleTerms, other.titleTerms))
+}
+
+// Group groups the near-duplicate news into the stories in the order of their first news.
+// The candidate pairs of the news are merged from the most similar, and two stories are merged
+// only if every news of one story is similar to every news of the other, so the news which are
+// similar only through the third news, like A~B and B~C, are not the story of A and C.
+// The news without the terms are the stories of their own.
+func Group(articles []news.News, config Config) []Story {
+	documents := make([]document, len(articles))
+	buckets := make(map[band][]int)
+	for i, article := range articles {
+		documents[i] = newDocument(article)
+		if len(documents[i].terms) > 0 {
+			for _, key := range newSignature(documents[i].terms).bands(false) {
+				buckets[key] = append(buckets[key], i)
+			}
+		}
+		if len(documents[i].titleTerms) > 0 {
+			for _, key := range newSignature(documents[i].titleTerms).bands(true) {
+				buckets[key] = append(buckets[key], i)
+			}
+		}
+	}
+
+	similarities := make(map[[2]int]float64)
+	var candidates [][2]int
+	for _, members := range buckets {
+		for i, first := range members {
+			for _, second := range members[i+1:] {
+				pair := [2]int{first, second}
+				if _, exists := similarities[pair]; exists {
+					continue
+				}
+				similarities[pair] = documents[first].similarity(documents[second])
+				if similarities[pair] >= config.Similarity {
+					candidates = append(candidates, pair)
+				}
+			}
+		}
+	}
+	slices.SortFunc(candidates, func(first, second [2]int) int {
+		if similarities[first] != similarities[second] {
+			return cmp.Compare(similarities[second], similarities[first])
+		}
+		if first[0] != second[0] {
+			return cmp.Compare(first[0], second[0])
+		}
+		return cmp.Compare(first[1], second[1])
+	})
+
+	groups := newUnionFind(len(articles))
+	groupMembers := make(map[int][]int, len(articles))
+	for i := range articles {
+		groupMembers[i] = []int{i}
+	}
+	for _, pair := range candidates {
+		firstRoot, secondRoot := groups.find(pair[0]), groups.find(pair[1])
+		if firstRoot == secondRoot || !allSimilar(documents, groupMembers[firstRoot], groupMembers[secondRoot], config.Similarity) {
+			continue
+		}
+		groups.union(firstRoot, secondRoot)
+		groupMembers[firstRoot] = append(groupMembers[firstRoot], groupMembers[secondRoot]...)
+		delete(groupMembers, secondRoot)
+	}
+
+	storyIndexes := make(map[int]int)
+	var members [][]int
+	for i := range articles {
+		root := groups.find(i)
+		index, exists := storyIndexes[root]
+		if !exists {
+			index = len(members)
+			storyIndexes[root] = index
+			members = append(members, nil)
+		}
+		members[index] = append(members[index], i)
+	}
+
+	stories := make([]Story, 0, len(members))
+	for _, indexes := range members {
+		stories = append(stories, newStory(articles, documents, indexes))
+	}
+	return stories
+}
+
+// allSimilar reports whether every news of the first group is similar to every news of the second.
+func allSimilar(documents []document, first, second []int, threshold float64) bool {
+	for _, i := range first {
+		for _, j := range second {
+			if documents[i].similarity(documents[j]) < threshold {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// newStory returns the story of the news with the indexes. The representative is the news with
+// the highest total similarity to the other news of the story, the earliest of the equal ones.
+func newStory(articles []news.News, documents []document, indexes []int) Story {
+	story := Story{Articles: make([]news.News, 0, len(indexes))}
+	bestSimilarity := -1.0
+	for _, i := range indexes {
+		story.Articles = append(story.Articles, articles[i])
+		if !slices.Contains(story.Sources, articles[i].SourceName) {
+			story.Sources = append(story.Sources, articles[i].SourceName)
+		}
+		var total float64
+		for _, j := range indexes {
+			if i != j {
+				total += documents[i].similarity(documents[j])
+			}
+		}
+		if total > bestSimilarity || total == bestSimilarity && articles[i].Date.Before(story.News.Date) {
+			story.News, story.representative, bestSimilarity = articles[i], len(story.Articles)-1, total
+		}
+	}
+	return story
+}
+
+// unionFind keeps the disjoint sets of the indexes of the news.
+type unionFind struct {
+	parents []int
+}
+
+func newUnionFind(size int) *unionFind {
+	parents := make([]int, size)
+	for i := range parents {
+		parents[i] = i
+	}
+	return &unionFind{parents: parents}
+}
+
+func (sets *unionFind) find(index int) int {
+	for sets.parents[index] != index {
+		sets.parents[index] = sets.parents[sets.parents[index]]
+		index = sets.parents[index]
+	}
+	return index
+}
+
+func (sets *unionFind) union(first, second int) {
+	sets.parents[sets.find(second)] = sets.find(first)
+}
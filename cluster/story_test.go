@@ -0,0 +1,147 @@
+package cluster
+
+import (
+	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var (
+	israelBBC = news.News{Title: "Israel war cabinet minister vows to quit if there is no post-war plan for Gaza",
+		Link: "https://bbc.co.uk/1", SourceName: "bbc", Date: time.Date(2024, 5, 18, 21, 0, 0, 0, time.UTC)}
+	israelABC = news.News{Title: "Member of Israel's War Cabinet says he'll quit the government June 8 unless there's a new war plan",
+		Description: "A key member of Israel's three-man War Cabinet has threatened to resign from the government",
+		Link:        "https://abcnews.go.com/1", SourceName: "abc", Date: time.Date(2024, 5, 18, 20, 0, 0, 0, time.UTC)}
+	israelWashington = news.News{Title: "Member of Israel's War Cabinet says he'll quit the government June 8 unless there's a new war plan",
+		Description: "Benny Gantz said he will quit the government unless a new plan for the war in Gaza is adopted",
+		Link:        "https://washingtontimes.com/1", SourceName: "washington", Date: time.Date(2024, 5, 18, 19, 0, 0, 0, time.UTC)}
+	argentinaABC = news.News{Title: "Argentine president begins unusual visit to Spain, snubbing officials and courting the far-right",
+		Link: "https://abcnews.go.com/2", SourceName: "abc", Date: time.Date(2024, 5, 18, 10, 0, 0, 0, time.UTC)}
+	argentinaWashington = news.News{Title: "Argentine President Javier Milei on visit to Spain, snubs officials, courts far-right",
+		Link: "https://washingtontimes.com/2", SourceName: "washington", Date: time.Date(2024, 5, 18, 11, 0, 0, 0, time.UTC)}
+	celtic = news.News{Title: "Police injured and 19 arrested in Celtic celebrations",
+		Link: "https://bbc.co.uk/2", SourceName: "bbc", Date: time.Date(2024, 5, 18, 9, 0, 0, 0, time.UTC)}
+	download = news.News{Title: "Download now", Link: "https://bbc.co.uk/3", SourceName: "bbc"}
+	watch    = news.News{Title: "Watch now", Link: "https://bbc.co.uk/4", SourceName: "bbc"}
+	empty    = news.News{Link: "https://bbc.co.uk/5", SourceName: "bbc"}
+)
+
+func TestGroup(t *testing.T) {
+	articles := []news.News{israelBBC, argentinaABC, celtic, israelABC, argentinaWashington, israelWashington, download, watch, empty}
+	tests := []struct {
+		name        string
+		config      Config
+		wantStories [][]news.News
+		wantSources [][]source.Name
+	}{
+		{
+			name:   "Default similarity",
+			config: DefaultConfig(),
+			wantStories: [][]news.News{
+				{israelBBC}, {argentinaABC, argentinaWashington}, {celtic},
+				{israelABC, israelWashington}, {download}, {watch}, {empty},
+			},
+			wantSources: [][]source.Name{
+				{"bbc"}, {"abc", "washington"}, {"bbc"}, {"abc", "washington"}, {"bbc"}, {"bbc"}, {"bbc"},
+			},
+		},
+		{
+			name:   "Low similarity groups different wording",
+			config: Config{Similarity: 0.2},
+			wantStories: [][]news.News{
+				{israelBBC, israelABC, israelWashington},
+				{argentinaABC, argentinaWashington},
+				{celtic}, {download}, {watch}, {empty},
+			},
+			wantSources: [][]source.Name{
+				{"bbc", "abc", "washington"}, {"abc", "washington"}, {"bbc"}, {"bbc"}, {"bbc"}, {"bbc"},
+			},
+		},
+		{
+			name:   "High similarity groups only close wording",
+			config: Config{Similarity: 0.9},
+			wantStories: [][]news.News{
+				{israelBBC}, {argentinaABC}, {celtic}, {israelABC, israelWashington},
+				{argentinaWashington}, {download}, {watch}, {empty},
+			},
+			wantSources: [][]source.Name{
+				{"bbc"}, {"abc"}, {"bbc"}, {"abc", "washington"}, {"washington"}, {"bbc"}, {"bbc"}, {"bbc"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stories := Group(articles, tt.config)
+			if len(stories) != len(tt.wantStories) {
+				t.Fatalf("Group() returned %d stories, want %d: %v", len(stories), len(tt.wantStories), stories)
+			}
+			for i, story := range stories {
+				if !reflect.DeepEqual(story.Articles, tt.wantStories[i]) {
+					t.Errorf("Story %d articles = %v, want %v", i, story.Articles, tt.wantStories[i])
+				}
+				if !reflect.DeepEqual(story.Sources, tt.wantSources[i]) {
+					t.Errorf("Story %d sources = %v, want %v", i, story.Sources, tt.wantSources[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGroup_Chain(t *testing.T) {
+	first := news.News{Title: "Kyiv drones attack energy grid overnight", Link: "https://example.com/1"}
+	second := news.News{Title: "Energy grid overnight power outage Kharkiv", Link: "https://example.com/2"}
+	third := news.News{Title: "Power outage Kharkiv schools close online", Link: "https://example.com/3"}
+
+	stories := Group([]news.News{first, second, third}, DefaultConfig())
+	want := [][]news.News{{first, second}, {third}}
+	if len(stories) != len(want) {
+		t.Fatalf("Group() returned %d stories, want %d: %v", len(stories), len(want), stories)
+	}
+	for i, story := range stories {
+		if !reflect.DeepEqual(story.Articles, want[i]) {
+			t.Errorf("Story %d articles = %v, want %v: the first and the third news are not similar", i, story.Articles, want[i])
+		}
+	}
+}
+
+func TestGroup_Representative(t *testing.T) {
+	stories := Group([]news.News{israelBBC, israelABC, israelWashington}, Config{Similarity: 0.2})
+	if len(stories) != 1 {
+		t.Fatalf("Group() returned %d stories, want 1", len(stories))
+	}
+	if !reflect.DeepEqual(stories[0].News, israelWashington) {
+		t.Errorf("Representative = %v, want the news closest to the others and the earliest", stories[0].News.Title)
+	}
+}
+
+func TestByStory_Filter(t *testing.T) {
+	got := ByStory{Config: DefaultConfig()}.Filter([]news.News{argentinaABC, celtic, argentinaWashington})
+	if len(got) != 2 {
+		t.Fatalf("Filter() returned %d news, want 2", len(got))
+	}
+	if got[0].Link != argentinaABC.Link && got[0].Link != argentinaWashington.Link {
+		t.Errorf("Filter() first news = %v, want the representative of the story", got[0].Title)
+	}
+	if !reflect.DeepEqual(got[0].Sources, []source.Name{"abc", "washington"}) || len(got[0].Related) != 1 {
+		t.Errorf("Filter() sources = %v, related = %v, want both sources and the other news", got[0].Sources, got[0].Related)
+	}
+	if !reflect.DeepEqual(got[1].Sources, []source.Name{"bbc"}) || got[1].Related != nil {
+		t.Errorf("Filter() sources = %v, related = %v, want the single source", got[1].Sources, got[1].Related)
+	}
+	if argentinaABC.Sources != nil {
+		t.Errorf("Filter() changed the passed news")
+	}
+}
+
+func TestJaccard(t *testing.T) {
+	first := termSet([]string{"israel", "war", "cabinet"})
+	second := termSet([]string{"israel", "war", "plan", "gaza"})
+	if got := jaccard(first, second); got != 0.4 {
+		t.Errorf("jaccard() = %v, want 0.4", got)
+	}
+	if got := jaccard(first, nil); got != 0 {
+		t.Errorf("jaccard() = %v, want 0 for the empty set", got)
+	}
+}
@@ -1,18 +1,34 @@
 package main
 
 import (
+	"context"
 	"github.com/sirupsen/logrus"
 	"news-aggregator/aggregator"
+	"news-aggregator/analysis"
 	"news-aggregator/client"
 	"news-aggregator/collector"
 	"news-aggregator/constant"
 	"news-aggregator/entity/source"
+	"news-aggregator/search"
 	"news-aggregator/storage"
 	newsStorage "news-aggregator/storage/news"
 	sourceStorage "news-aggregator/storage/source"
+	"news-aggregator/trending"
+	sourceService "news-aggregator/web/source"
+	"os"
+	"os/signal"
+	_ "time/tzdata"
 )
 
 func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		// the first interrupt cancels the fetching, the next one stops the program at once
+		<-ctx.Done()
+		stop()
+	}()
+
 	newsJsonStorage, err := newsStorage.NewJsonStorage(source.PathToFile(constant.PathToResources))
 	if err != nil {
 		logrus.Fatal(err)
@@ -21,14 +37,36 @@ func main() {
 	if err != nil {
 		logrus.Fatal(err)
 	}
-	newStorage := storage.NewStorage(
+	synonyms, err := analysis.LoadSynonyms(constant.PathToSynonyms)
+	if err != nil {
+		logrus.Error("Failed to load synonyms: ", err)
+	}
+	searchIndex, err := search.Open(ctx, constant.PathToSearchIndex, storage.NewStorage(newsJsonStorage, jsonSourceStorage), synonyms)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	newStorage := search.NewIndexedStorage(storage.NewStorage(
 		newsJsonStorage,
 		jsonSourceStorage,
-	)
+	), searchIndex)
+	if len(os.Args) > 1 && os.Args[1] == "opml" {
+		service := sourceService.NewService(newStorage, collector.GetDefaultParsers())
+		if err := runOPML(ctx, service, os.Args[2:], os.Stdout); err != nil {
+			logrus.Fatal(err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "trending" {
+		if err := runTrending(ctx, trending.NewService(newStorage), os.Args[2:], os.Stdout); err != nil {
+			logrus.Fatal(err)
+		}
+		return
+	}
+
 	newsCollector := collector.New(newStorage)
 	newsAggregator := aggregator.New(newsCollector)
-	cli := client.NewCommandLine(newsAggregator)
-	articles, err := cli.FetchNews()
+	cli := client.NewCommandLine(newsAggregator, searchIndex, synonyms)
+	articles, err := cli.FetchNews(ctx)
 	if err != nil {
 		println(err.Error())
 	}
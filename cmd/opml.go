@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"news-aggregator/opml"
+	sourceService "news-aggregator/web/source"
+	"os"
+	"text/tabwriter"
+)
+
+const opmlUsage = `Usage:
+  opml export [-output sources.opml]  writes the sources as the OPML document
+  opml import -file sources.opml      adds the sources of the OPML document`
+
+// runOPML runs the opml command which exports the sources to the OPML document or imports them from it.
+func runOPML(ctx context.Context, service *sourceService.Service, args []string, output io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(opmlUsage)
+	}
+
+	flags := flag.NewFlagSet("opml "+args[0], flag.ContinueOnError)
+	switch args[0] {
+	case "export":
+		outputPath := flags.String("output", "", "Specify the file for the OPML document, the standard output is used by default")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *outputPath == "" {
+			return service.ExportOPML(ctx, output)
+		}
+		file, err := os.Create(*outputPath)
+		if err != nil {
+			return err
+		}
+		if err := service.ExportOPML(ctx, file); err != nil {
+			_ = file.Close()
+			return err
+		}
+		return file.Close()
+	case "import":
+		inputPath := flags.String("file", "", "Specify the OPML document with the sources")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *inputPath == "" {
+			return errors.New(opmlUsage)
+		}
+		file, err := os.Open(*inputPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		results, err := service.ImportOPML(ctx, file)
+		if err != nil {
+			return err
+		}
+		return printImportResults(output, results)
+	default:
+		return errors.New(opmlUsage)
+	}
+}
+
+// printImportResults prints the result of the import of each outline as the table.
+func printImportResults(output io.Writer, results []opml.Result) error {
+	writer := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "NAME\tSTATUS\tURL\tERROR")
+	for _, result := range results {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", result.Name, result.Status, result.URL, result.Error)
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"news-aggregator/trending"
+	"strings"
+	"time"
+)
+
+// runTrending runs the trending command which prints the emerging topics of the stored news with their sample news.
+func runTrending(ctx context.Context, service *trending.Service, args []string, output io.Writer) error {
+	flags := flag.NewFlagSet("trending", flag.ContinueOnError)
+	sources := flags.String("sources", "", "Specify news sources separated by comma, tags like \"tag:world\" or patterns like \"n*\", all sources by default")
+	window := flags.String("window", "", "Specify recent window before now like 24h or 7d, 24h by default")
+	baseline := flags.String("baseline", "", "Specify baseline window before the recent one like 7d, 7d by default")
+	limit := flags.String("limit", "", "Specify maximum number of the topics, 10 by default")
+	samples := flags.String("samples", "", "Specify number of the sample news of every topic, 3 by default")
+	timezone := flags.String("timezone", "", "Specify time zone of the dates, like Europe/Kyiv")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	config, err := trending.ParseConfig(*window, *baseline, *limit, *samples)
+	if err != nil {
+		return err
+	}
+	location := time.UTC
+	if *timezone != "" {
+		if location, err = time.LoadLocation(*timezone); err != nil {
+			return fmt.Errorf("invalid time zone %q: %w", *timezone, err)
+		}
+	}
+	var selectors []string
+	if *sources != "" {
+		selectors = strings.Split(*sources, ",")
+	}
+
+	topics, err := service.Trending(ctx, selectors, time.Now(), config)
+	if err != nil {
+		return err
+	}
+	return printTopics(output, topics, config, location)
+}
+
+// printTopics prints the topics from the highest score with their sample news.
+func printTopics(output io.Writer, topics []trending.Topic, config trending.Config, location *time.Location) error {
+	if len(topics) == 0 {
+		_, err := fmt.Fprintf(output, "No trending topics in the last %s compared with %s before.\n", config.Window, config.Baseline)
+		return err
+	}
+	fmt.Fprintf(output, "Trending topics of the last %s compared with %s before:\n", config.Window, config.Baseline)
+	for i, topic := range topics {
+		sourceNames := make([]string, len(topic.Sources))
+		for j, name := range topic.Sources {
+			sourceNames[j] = string(name)
+		}
+		fmt.Fprintf(output, "\n%d. %s (score %.2f, %d recent news, %d before, sources: %s)\n",
+			i+1, topic.Phrase, topic.Score, topic.RecentCount, topic.BaselineCount, strings.Join(sourceNames, ", "))
+		for _, article := range topic.Articles {
+			fmt.Fprintf(output, "   %s %s: %s\n      %s\n",
+				article.Date.In(location).Format("2006-01-02 15:04"), article.SourceName, article.Title, article.Link)
+		}
+	}
+	return nil
+}
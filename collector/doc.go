@@ -1,12 +1,20 @@
 // Package collector provides functionality for gathering newsCollector from specific sources.
-// FindNewsByResourcesName(sourcesNames []source.Name) ([]newsCollector.News, string)
+// FindNewsByResourcesName(ctx context.Context, sourcesNames []source.Name) ([]newsCollector.News, string)
 // is used to receive all newsCollector from sources passed to it, if these sources are
-// correct and present in the system
+// correct and present in the system. The sources are parsed concurrently by the pool of
+// the workers within the per-source timeout of the Config, and the news of the healthy
+// sources are returned with the status of every source when the other sources fail.
 // findNewsForCurrentSource(currentSource source.Source,
 //
 //	name source.Name, allArticles []newsCollector.News) []newsCollector.News returns
 //	the list of collector from the passed source.
 //
+// ParseFile(ctx context.Context, parser Parser, path source.PathToFile, name source.Name)
+// opens the file of the source and passes its content to the reader-based parser.
+//
+// MirrorSource downloads the file of the remote source with the conditional request
+// and saves it to the PathToFile only if it is parsed without errors.
+//
 // InitializeSource(sources []source.Source) initializes the news that
 // will be available for parsing.
 package collector
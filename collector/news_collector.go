@@ -1,56 +1,223 @@
 package collector
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"news-aggregator/aggregator"
+	"news-aggregator/cache"
 	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
+	"news-aggregator/fetcher"
+	"news-aggregator/parser"
 	"news-aggregator/storage"
+	"os"
 	"strings"
+	"sync"
+	"time"
 )
 
+// Config describes the limits of the collection of the sources.
+type Config struct {
+	// Workers is the number of the sources parsed at the same time.
+	Workers int
+	// SourceTimeout limits the fetching and the parsing of the single source.
+	SourceTimeout time.Duration
+	// Cache keeps the parsed news of the source files between the searches, the files
+	// are parsed on every search if it is nil.
+	Cache *cache.Cache
+}
+
+// DefaultConfig returns the configuration used by the aggregator.
+func DefaultConfig() Config {
+	return Config{
+		Workers:       4,
+		SourceTimeout: 30 * time.Second,
+	}
+}
+
 type newsCollector struct {
 	sourceStorage storage.Storage
 	parsers       *Parsers
+	fetcher       *fetcher.Fetcher
+	config        Config
+}
+
+// sourceResult is the result of the collection of the single source.
+type sourceResult struct {
+	news     []news.News
+	report   parser.Report
+	err      error
+	duration time.Duration
 }
 
 // New create new instance of collector
 func New(sourceStorage storage.Storage) aggregator.Collector {
-	return &newsCollector{sourceStorage: sourceStorage, parsers: GetDefaultParsers()}
+	return NewWithParsers(sourceStorage, GetDefaultParsers())
 }
 
-// FindNewsByResourcesName returns the list of news from the passed sources.
-func (newsCollector *newsCollector) FindNewsByResourcesName(sourcesNames []source.Name) ([]news.News, error) {
-	var foundNews []news.News
-	sources, err := newsCollector.sourceStorage.GetSources()
+// NewWithParsers creates new instance of collector which parses the sources
+// by the parsers of the passed registry.
+func NewWithParsers(sourceStorage storage.Storage, parsers *Parsers) aggregator.Collector {
+	return NewWithConfig(sourceStorage, parsers, DefaultConfig())
+}
+
+// NewWithConfig creates new instance of collector which parses the sources
+// by the parsers of the passed registry within the limits of the config.
+func NewWithConfig(sourceStorage storage.Storage, parsers *Parsers, config Config) aggregator.Collector {
+	return &newsCollector{
+		sourceStorage: sourceStorage,
+		parsers:       parsers,
+		fetcher:       fetcher.New(fetcher.DefaultConfig()),
+		config:        config,
+	}
+}
+
+// FindNewsByResourcesName returns the list of news from the passed sources with the report
+// of the items which the parsers skipped or repaired and the status of every source.
+// The sources are parsed concurrently by the pool of the workers, each one within its own
+// deadline. The news of the healthy sources are returned when the other sources fail,
+// the error is returned only if none of the sources is collected.
+// The same article found in several sources is returned only once.
+// The search stops with the error of the context when the context is done.
+func (newsCollector *newsCollector) FindNewsByResourcesName(ctx context.Context, sourcesNames []source.Name) ([]news.News, parser.Report, error) {
+	var report parser.Report
+	sources, err := newsCollector.sourceStorage.GetSources(ctx)
 	if err != nil {
-		return nil, err
+		return nil, report, err
 	}
+
+	var selectedSources []source.Source
 	for _, sourceName := range sourcesNames {
 		for _, currentSource := range sources {
 			if strings.ToLower(string(currentSource.Name)) == strings.ToLower(string(sourceName)) {
-				newsArticles, err := newsCollector.findNewsForCurrentSource(currentSource, sourceName)
-				if err != nil {
-					return nil, err
-				}
-				foundNews = append(foundNews, newsArticles...)
+				currentSource.Name = sourceName
+				selectedSources = append(selectedSources, currentSource)
+			}
+		}
+	}
+
+	results := newsCollector.collect(ctx, selectedSources)
+	if err := ctx.Err(); err != nil {
+		return nil, report, err
+	}
+
+	var foundNews []news.News
+	var errs []error
+	for i, result := range results {
+		name := selectedSources[i].Name
+		report.Merge(result.report)
+		if result.err != nil {
+			state := parser.Failed
+			if errors.Is(result.err, context.DeadlineExceeded) {
+				state = parser.TimedOut
+			}
+			report.SetStatus(name, parser.SourceStatus{State: state, Error: result.err.Error(), Duration: result.duration})
+			errs = append(errs, fmt.Errorf("source %s: %w", name, result.err))
+			continue
+		}
+		report.SetStatus(name, parser.SourceStatus{State: parser.Collected, Articles: len(result.news), Duration: result.duration})
+		foundNews = append(foundNews, result.news...)
+	}
+	if len(errs) > 0 && len(errs) == len(results) {
+		return nil, report, errors.Join(errs...)
+	}
+	return news.RemoveDuplicates(foundNews), report, nil
+}
+
+// collect parses the sources by the pool of the workers and returns the results in the order of the sources.
+// The sources which are not started before the context is done are left without the result.
+func (newsCollector *newsCollector) collect(ctx context.Context, sources []source.Source) []sourceResult {
+	results := make([]sourceResult, len(sources))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < min(max(newsCollector.config.Workers, 1), len(sources)); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = newsCollector.collectSource(ctx, sources[i])
 			}
+		}()
+	}
+
+sending:
+	for i := range sources {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break sending
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// collectSource parses the single source within the source timeout and measures the time of the parsing.
+func (newsCollector *newsCollector) collectSource(ctx context.Context, currentSource source.Source) sourceResult {
+	start := time.Now()
+	if newsCollector.config.SourceTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, newsCollector.config.SourceTimeout)
+		defer cancel()
+	}
+	newsArticles, report, err := newsCollector.findNewsForCurrentSource(ctx, currentSource, currentSource.Name)
+	if ctxErr := ctx.Err(); err != nil && ctxErr != nil && !errors.Is(err, ctxErr) {
+		err = fmt.Errorf("%w: %v", ctxErr, err)
+	}
+	return sourceResult{
+		news:     withLanguage(newsArticles, currentSource.Language),
+		report:   report,
+		err:      err,
+		duration: time.Since(start),
+	}
+}
+
+// withLanguage returns the copy of the news with the language of the source set to the news
+// without their own language. The news may be shared with the cache, so they are not changed.
+func withLanguage(articles []news.News, language string) []news.News {
+	if language == "" {
+		return articles
+	}
+	result := make([]news.News, len(articles))
+	for i, article := range articles {
+		if article.Language == "" {
+			article.Language = language
 		}
+		result[i] = article
 	}
-	return foundNews, nil
+	return result
 }
 
 // Returns the list of news from the passed source.
-func (newsCollector *newsCollector) findNewsForCurrentSource(currentSource source.Source, name source.Name) ([]news.News, error) {
+func (newsCollector *newsCollector) findNewsForCurrentSource(ctx context.Context, currentSource source.Source, name source.Name) ([]news.News, parser.Report, error) {
 
 	sourceParser, err := newsCollector.parsers.GetParserBySourceType(currentSource.SourceType)
 	if err != nil {
-		return []news.News{}, err
+		return []news.News{}, parser.Report{}, err
 	}
 
-	foundNews, err := sourceParser.Parse(currentSource.PathToFile, name)
-	if err != nil {
-		return nil, err
+	currentSource.Name = name
+	if isNotMirrored(currentSource) {
+		return FetchSource(ctx, newsCollector.fetcher, sourceParser, currentSource)
+	}
+	if newsCollector.config.Cache != nil {
+		return newsCollector.config.Cache.Get(currentSource, func() ([]news.News, parser.Report, error) {
+			return ParseSourceFile(ctx, sourceParser, currentSource)
+		})
 	}
+	return ParseSourceFile(ctx, sourceParser, currentSource)
+}
 
-	return foundNews, nil
+// isNotMirrored reports whether the remote source has not been downloaded to its file yet.
+func isNotMirrored(currentSource source.Source) bool {
+	if currentSource.URL == "" {
+		return false
+	}
+	if currentSource.PathToFile == "" {
+		return true
+	}
+	_, err := os.Stat(string(currentSource.PathToFile))
+	return err != nil
 }
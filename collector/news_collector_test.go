@@ -1,16 +1,25 @@
 package collector
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"io/ioutil"
 	"log"
+	"news-aggregator/cache"
 	"news-aggregator/constant"
+	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
+	"news-aggregator/fetcher"
+	"news-aggregator/parser"
+	"news-aggregator/parser/html"
 	"news-aggregator/storage"
 	newsStorage "news-aggregator/storage/news"
 	sourceStorage "news-aggregator/storage/source"
 	"os"
 	"testing"
+	"time"
 )
 
 var testArticleCollector *newsCollector
@@ -39,7 +48,7 @@ func beforeEach() {
 	sourceStorage, _ := sourceStorage.NewJsonStorage(source.PathToFile(file.Name()))
 	newsJsonStorage, _ := newsStorage.NewJsonStorage(source.PathToFile(constant.PathToResources))
 	newStorage := storage.NewStorage(newsJsonStorage, sourceStorage)
-	testArticleCollector = &newsCollector{sourceStorage: newStorage, parsers: GetDefaultParsers()}
+	testArticleCollector = &newsCollector{sourceStorage: newStorage, parsers: GetDefaultParsers(), fetcher: fetcher.New(fetcher.DefaultConfig())}
 }
 
 func TestFindNewsByResourcesName(t *testing.T) {
@@ -59,13 +68,13 @@ func TestFindNewsByResourcesName(t *testing.T) {
 					"nbc",
 				},
 			},
-			154,
+			151,
 		},
 
 		{"Find articles from one source by his name.",
 			args{
 				[]source.Name{"bbc"}},
-			54,
+			51,
 		},
 		{"Return zero if the source is not correct.",
 			args{
@@ -84,7 +93,7 @@ func TestFindNewsByResourcesName(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, _ := testArticleCollector.FindNewsByResourcesName(tt.args.sourcesNames)
+			got, _, _ := testArticleCollector.FindNewsByResourcesName(context.Background(), tt.args.sourcesNames)
 			if len(got) != tt.wantQuantity {
 				t.Errorf("Actual result = %v, expected = %v", len(got), tt.wantQuantity)
 			}
@@ -118,13 +127,215 @@ func TestFindNewsForCurrentSource(t *testing.T) {
 			},
 			wantQuantity: 100,
 		},
+
+		{name: "Test for HTML source with selectors",
+			args: args{
+				currentSource: source.Source{
+					Name:       "usatoday",
+					PathToFile: "../mnt/resources/testdata/test_usatoday.html",
+					SourceType: source.HTML,
+					Selectors:  &html.UsaTodaySelectors,
+				},
+				name: "usatoday",
+			},
+			wantQuantity: 2,
+		},
+
+		{name: "Test for HTML source with preset selectors",
+			args: args{
+				currentSource: source.Source{
+					Name:       "usatoday",
+					PathToFile: "../mnt/resources/testdata/test_usatoday.html",
+					SourceType: source.HTML,
+				},
+				name: "usatoday",
+			},
+			wantQuantity: 2,
+		},
+
+		{name: "Test for SITEMAP source",
+			args: args{
+				currentSource: source.Source{Name: "sitemap", PathToFile: "../mnt/resources/testdata/news_sitemap.xml", SourceType: source.SITEMAP},
+				name:          "sitemap",
+			},
+			wantQuantity: 2,
+		},
+
+		{name: "Test for EMAIL source in mbox file",
+			args: args{
+				currentSource: source.Source{Name: "newsletters", PathToFile: "../mnt/resources/testdata/newsletters.mbox", SourceType: source.EMAIL},
+				name:          "newsletters",
+			},
+			wantQuantity: 2,
+		},
+
+		{name: "Test for EMAIL source in directory of .eml files",
+			args: args{
+				currentSource: source.Source{Name: "briefs", PathToFile: "../mnt/resources/testdata/newsletters", SourceType: source.EMAIL},
+				name:          "briefs",
+			},
+			wantQuantity: 2,
+		},
+
+		{name: "Test for directory of source which parser does not read directories",
+			args: args{
+				currentSource: source.Source{Name: "directory", PathToFile: "../mnt/resources/testdata/newsletters", SourceType: source.RSS},
+				name:          "directory",
+			},
+			wantQuantity: 0,
+		},
+
+		{name: "Test for HTML source without selectors",
+			args: args{
+				currentSource: source.Source{Name: "site", PathToFile: "../mnt/resources/testdata/test_usatoday.html", SourceType: source.HTML},
+				name:          "site",
+			},
+			wantQuantity: 0,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, _ := testArticleCollector.findNewsForCurrentSource(tt.args.currentSource, tt.args.name)
+			got, _, _ := testArticleCollector.findNewsForCurrentSource(context.Background(), tt.args.currentSource, tt.args.name)
 			if len(got) != tt.wantQuantity {
 				t.Errorf("Actual result = %v, expected = %v", len(got), tt.wantQuantity)
 			}
 		})
 	}
 }
+
+func TestNewWithParsers(t *testing.T) {
+	beforeEach()
+	parsers := NewParsers()
+	if err := parsers.Register("STUB", stubParser{}); err != nil {
+		t.Fatal(err)
+	}
+	customCollector := NewWithParsers(testArticleCollector.sourceStorage, parsers).(*newsCollector)
+
+	got, _, err := customCollector.findNewsForCurrentSource(context.Background(),
+		source.Source{Name: "stub", PathToFile: "../mnt/resources/testdata/json_articles.json", SourceType: "STUB"}, "stub")
+	if err != nil || len(got) != 1 || got[0].Title != "Stub" {
+		t.Errorf("Actual result = %v, %v, expected the news of the registered parser", got, err)
+	}
+
+	if _, _, err := customCollector.findNewsForCurrentSource(context.Background(),
+		source.Source{Name: "bbc", PathToFile: "../mnt/resources/testdata/bbc-world-category-19-05-24.xml", SourceType: source.RSS}, "bbc"); err == nil {
+		t.Errorf("Expected error for the type which is not registered in the custom parsers")
+	}
+}
+
+func TestFindNewsForCurrentSource_Report(t *testing.T) {
+	beforeEach()
+	got, report, err := testArticleCollector.findNewsForCurrentSource(context.Background(),
+		source.Source{Name: "undated", PathToFile: "../mnt/resources/testdata/undated_rss.xml", SourceType: source.RSS}, "undated")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("Actual quantity = %v, expected the items which have the title or the link", len(got))
+	}
+	if len(report.Diagnostics) != 3 || report.Diagnostics[2].Action != parser.Skipped {
+		t.Errorf("Actual report = %v, expected two repaired items and one skipped item", report)
+	}
+}
+
+// slowParser blocks until the context of the source is done.
+type slowParser struct{}
+
+func (slowParser) Parse(ctx context.Context, reader io.Reader, name source.Name) ([]news.News, parser.Report, error) {
+	<-ctx.Done()
+	return nil, parser.Report{}, ctx.Err()
+}
+
+func TestFindNewsByResourcesName_PartialResults(t *testing.T) {
+	beforeEach()
+	parsers := GetDefaultParsers()
+	if err := parsers.Register("SLOW", slowParser{}); err != nil {
+		t.Fatal(err)
+	}
+	sourceStorage := &stubSourceStorage{Storage: testArticleCollector.sourceStorage, sources: []source.Source{
+		{Name: "bbc", PathToFile: "../mnt/resources/testdata/bbc-world-category-19-05-24.xml", SourceType: source.RSS},
+		{Name: "missing", PathToFile: "../mnt/resources/testdata/missing.xml", SourceType: source.RSS},
+		{Name: "slow", PathToFile: "../mnt/resources/testdata/test_rss.xml", SourceType: "SLOW"},
+	}}
+	partialCollector := NewWithConfig(sourceStorage, parsers, Config{Workers: 2, SourceTimeout: 50 * time.Millisecond})
+
+	got, report, err := partialCollector.FindNewsByResourcesName(context.Background(), []source.Name{"bbc", "missing", "slow"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(got) != 51 {
+		t.Errorf("Actual quantity = %v, expected the news of the healthy source", len(got))
+	}
+	wantStates := map[source.Name]parser.State{"bbc": parser.Collected, "missing": parser.Failed, "slow": parser.TimedOut}
+	for name, state := range wantStates {
+		if report.Sources[name].State != state {
+			t.Errorf("Actual status of %s = %v, expected %v", name, report.Sources[name], state)
+		}
+	}
+	if report.Sources["bbc"].Articles != 54 {
+		t.Errorf("Actual articles of bbc = %v, expected 54", report.Sources["bbc"].Articles)
+	}
+	if len(report.Warnings()) != 2 {
+		t.Errorf("Actual warnings = %v, expected the warnings of the failed sources", report.Warnings())
+	}
+
+	_, report, err = partialCollector.FindNewsByResourcesName(context.Background(), []source.Name{"missing", "slow"})
+	if err == nil {
+		t.Errorf("Expected error when none of the sources is collected")
+	}
+	if len(report.Sources) != 2 {
+		t.Errorf("Actual statuses = %v, expected the statuses of the failed sources", report.Sources)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := partialCollector.FindNewsByResourcesName(ctx, []source.Name{"bbc"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Actual error = %v, expected the error of the context", err)
+	}
+}
+
+func TestFindNewsByResourcesName_Language(t *testing.T) {
+	beforeEach()
+	sourceStorage := &stubSourceStorage{Storage: testArticleCollector.sourceStorage, sources: []source.Source{
+		{Name: "nbc", PathToFile: "../mnt/resources/testdata/nbc-news.json", SourceType: source.JSON, Language: "en"},
+	}}
+	languageCollector := NewWithConfig(sourceStorage, GetDefaultParsers(), DefaultConfig())
+
+	got, _, err := languageCollector.FindNewsByResourcesName(context.Background(), []source.Name{"nbc"})
+	if err != nil || len(got) == 0 {
+		t.Fatalf("Actual result = %v, %v, expected the news of the source", len(got), err)
+	}
+	for _, article := range got {
+		if article.Language != "en" {
+			t.Fatalf("Actual language = %q, expected the language of the source", article.Language)
+		}
+	}
+}
+
+// stubSourceStorage returns the passed sources instead of the stored ones.
+type stubSourceStorage struct {
+	storage.Storage
+	sources []source.Source
+}
+
+func (sourceStorage *stubSourceStorage) GetSources(context.Context) ([]source.Source, error) {
+	return sourceStorage.sources, nil
+}
+
+func TestFindNewsByResourcesName_Cache(t *testing.T) {
+	beforeEach()
+	sourceCache := cache.New(cache.DefaultConfig())
+	config := DefaultConfig()
+	config.Cache = sourceCache
+	cachedCollector := NewWithConfig(testArticleCollector.sourceStorage, GetDefaultParsers(), config)
+
+	for i := 0; i < 2; i++ {
+		got, _, err := cachedCollector.FindNewsByResourcesName(context.Background(), []source.Name{"bbc", "nbc"})
+		if err != nil || len(got) != 151 {
+			t.Fatalf("Actual result = %v, %v, expected the news of both sources", len(got), err)
+		}
+	}
+	if stats := sourceCache.Stats(); stats.Hits != 2 || stats.Misses != 2 || stats.Entries != 2 {
+		t.Errorf("Actual statistics = %+v, expected the second search to be answered from the cache", stats)
+	}
+}
@@ -1,19 +1,36 @@
 package collector
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"github.com/sirupsen/logrus"
+	"io"
 	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
 	"news-aggregator/parser"
 	"news-aggregator/parser/html"
+	"news-aggregator/validator"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 )
 
 // Parsers manages the mapping of source types to their corresponding parsers.
+// It is the registry of the parsers, the parsers of the new source types can be registered
+// by the applications which embed the aggregator.
 type Parsers struct {
+	mutex   sync.RWMutex
 	parsers map[source.Type]Parser
 }
 
+// NewParsers returns the empty registry of the parsers.
+func NewParsers() *Parsers {
+	return &Parsers{parsers: map[source.Type]Parser{}}
+}
+
 // GetDefaultParsers initializes and returns a new Parsers with available parsers for different file types.
 func GetDefaultParsers() *Parsers {
 	logrus.Info("Starting initialize parsers")
@@ -21,24 +38,170 @@ func GetDefaultParsers() *Parsers {
 		parsers: map[source.Type]Parser{
 			source.RSS:      parser.Rss{},
 			source.JSON:     parser.Json{},
-			source.UsaToday: html.UsaToday{},
+			source.UsaToday: html.Scraper{Selectors: html.UsaTodaySelectors},
 			source.STORAGE:  parser.Storage{},
+			source.HTML:     html.Scraper{},
+			source.SITEMAP:  parser.Sitemap{},
+			source.EMAIL:    parser.Email{},
 		},
 	}
 }
 
+// Register adds the parser of the new source type.
+// It returns an error if the type is already registered, use Override to replace its parser.
+func (pm *Parsers) Register(typeOfSource source.Type, sourceParser Parser) error {
+	if err := validateRegistration(typeOfSource, sourceParser); err != nil {
+		return err
+	}
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+	if _, exist := pm.parsers[typeOfSource]; exist {
+		return fmt.Errorf("parser of the source type %s is already registered", typeOfSource)
+	}
+	pm.parsers[typeOfSource] = sourceParser
+	logrus.Info("Parser registered for the source type: ", typeOfSource)
+	return nil
+}
+
+// Override sets the parser of the source type replacing the registered one.
+func (pm *Parsers) Override(typeOfSource source.Type, sourceParser Parser) error {
+	if err := validateRegistration(typeOfSource, sourceParser); err != nil {
+		return err
+	}
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+	pm.parsers[typeOfSource] = sourceParser
+	logrus.Info("Parser overridden for the source type: ", typeOfSource)
+	return nil
+}
+
+// Types returns the sorted list of the registered source types.
+func (pm *Parsers) Types() []source.Type {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
+	types := make([]source.Type, 0, len(pm.parsers))
+	for typeOfSource := range pm.parsers {
+		types = append(types, typeOfSource)
+	}
+	slices.Sort(types)
+	return types
+}
+
 // GetParserBySourceType returns the parser that is required for parsing files of the passed type.
 func (pm *Parsers) GetParserBySourceType(typeOfSource source.Type) (Parser, error) {
+	pm.mutex.RLock()
 	parser, exist := pm.parsers[typeOfSource]
+	pm.mutex.RUnlock()
 	if !exist {
-		return nil, errors.New("parser not exist")
+		return nil, validator.ValidateSourceType(typeOfSource, pm.Types())
 	}
 	return parser, nil
 }
 
+func validateRegistration(typeOfSource source.Type, sourceParser Parser) error {
+	if strings.TrimSpace(string(typeOfSource)) == "" {
+		return errors.New("source type of the parser is empty")
+	}
+	if sourceParser == nil {
+		return fmt.Errorf("parser of the source type %s is nil", typeOfSource)
+	}
+	return nil
+}
+
 // A Parser analyzes a source and retrieves a list of articles from that source.
 type Parser interface {
 
-	// Parse returns a list of the source's news by its path.
-	Parse(path source.PathToFile, name source.Name) ([]news.News, error)
+	// Parse returns a list of the source's news read from the reader and the report
+	// of the items which were skipped or repaired. The error is returned only if
+	// the source cannot be parsed at all, the invalid items must be reported instead.
+	// Parsing stops with the error of the context when the context is done.
+	Parse(ctx context.Context, reader io.Reader, name source.Name) ([]news.News, parser.Report, error)
+}
+
+// A SourceParser is a Parser which is configured by the settings stored in the source record,
+// like the selectors of the HTML scraper.
+type SourceParser interface {
+	Parser
+
+	// ParseSource returns a list of the news of the passed source read from the reader.
+	ParseSource(ctx context.Context, reader io.Reader, currentSource source.Source) ([]news.News, parser.Report, error)
+}
+
+// A DirectoryParser is a Parser of the sources which are stored as the directories of files,
+// like the directories of the .eml files of the newsletters. Each file is passed to Parse.
+type DirectoryParser interface {
+	Parser
+
+	// Extensions returns the extensions of the files of the directory which are parsed.
+	Extensions() []string
+}
+
+// ParseFile opens the file specified by the path and parses it by the passed parser.
+func ParseFile(ctx context.Context, sourceParser Parser, path source.PathToFile, name source.Name) ([]news.News, parser.Report, error) {
+	return ParseSourceFile(ctx, sourceParser, source.Source{Name: name, PathToFile: path})
+}
+
+// ParseSourceFile opens the file of the source and parses it by the passed parser.
+// The settings of the source are passed to the parser if it is a SourceParser,
+// and the files of the directory are parsed one by one if the parser is a DirectoryParser.
+func ParseSourceFile(ctx context.Context, sourceParser Parser, currentSource source.Source) ([]news.News, parser.Report, error) {
+	if directoryParser, ok := sourceParser.(DirectoryParser); ok {
+		info, err := os.Stat(string(currentSource.PathToFile))
+		if err != nil {
+			return nil, parser.Report{}, err
+		}
+		if info.IsDir() {
+			return parseDirectory(ctx, directoryParser, currentSource)
+		}
+	}
+	return parseFile(ctx, currentSource.PathToFile, func(reader io.Reader) ([]news.News, parser.Report, error) {
+		return parseSource(ctx, sourceParser, reader, currentSource)
+	})
+}
+
+// parseDirectory parses the files of the directory with the extensions of the parser in the order of their names.
+// The files which cannot be parsed are skipped and listed in the report.
+func parseDirectory(ctx context.Context, directoryParser DirectoryParser, currentSource source.Source) ([]news.News, parser.Report, error) {
+	var report parser.Report
+	entries, err := os.ReadDir(string(currentSource.PathToFile))
+	if err != nil {
+		return nil, report, err
+	}
+
+	var articles []news.News
+	for _, entry := range entries {
+		if entry.IsDir() || !slices.Contains(directoryParser.Extensions(), strings.ToLower(filepath.Ext(entry.Name()))) {
+			continue
+		}
+		path := source.PathToFile(filepath.Join(string(currentSource.PathToFile), entry.Name()))
+		fileArticles, fileReport, err := parseFile(ctx, path, func(reader io.Reader) ([]news.News, parser.Report, error) {
+			return directoryParser.Parse(ctx, reader, currentSource.Name)
+		})
+		report.Merge(fileReport)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, report, ctx.Err()
+			}
+			report.Skip(currentSource.Name, string(path), "the file cannot be parsed: "+err.Error())
+			continue
+		}
+		articles = append(articles, fileArticles...)
+	}
+	return articles, report, nil
+}
+
+func parseFile(ctx context.Context, path source.PathToFile, parse func(reader io.Reader) ([]news.News, parser.Report, error)) (articles []news.News, report parser.Report, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, report, err
+	}
+	file, err := os.Open(string(path))
+	if err != nil {
+		return nil, report, err
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("cannot close file %s: %w", path, cerr)
+		}
+	}()
+	return parse(file)
 }
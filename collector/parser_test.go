@@ -1,8 +1,16 @@
 package collector
 
 import (
+	"context"
+	"errors"
+	"io"
+	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
+	"news-aggregator/parser"
+	"news-aggregator/validator"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestGetParserBySourceType(t *testing.T) {
@@ -28,6 +36,21 @@ func TestGetParserBySourceType(t *testing.T) {
 			sourceType:  source.UsaToday,
 			expectedErr: false,
 		},
+		{
+			name:        "Test with existing HTML parser",
+			sourceType:  source.HTML,
+			expectedErr: false,
+		},
+		{
+			name:        "Test with existing SITEMAP parser",
+			sourceType:  source.SITEMAP,
+			expectedErr: false,
+		},
+		{
+			name:        "Test with existing EMAIL parser",
+			sourceType:  source.EMAIL,
+			expectedErr: false,
+		},
 		{
 			name:        "Test with non-existent parser",
 			sourceType:  "non-existent",
@@ -48,3 +71,75 @@ func TestGetParserBySourceType(t *testing.T) {
 		})
 	}
 }
+
+func TestParseFile(t *testing.T) {
+	cancelledContext, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		path         source.PathToFile
+		wantQuantity int
+		wantErr      error
+	}{
+		{
+			name:         "Parse existing file",
+			ctx:          context.Background(),
+			path:         "../mnt/resources/testdata/json_articles.json",
+			wantQuantity: 2,
+		},
+		{
+			name:    "Parse with cancelled context",
+			ctx:     cancelledContext,
+			path:    "../mnt/resources/testdata/json_articles.json",
+			wantErr: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := ParseFile(tt.ctx, parser.Json{}, tt.path, "testjson")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.wantQuantity {
+				t.Errorf("ParseFile() quantity = %v, want %v", len(got), tt.wantQuantity)
+			}
+		})
+	}
+
+	if _, _, err := ParseFile(context.Background(), parser.Json{}, "non_existent_file.json", "testjson"); err == nil {
+		t.Errorf("ParseFile() expected error for the missing file")
+	}
+}
+
+type stubParser struct{}
+
+func (stubParser) Parse(ctx context.Context, reader io.Reader, name source.Name) ([]news.News, parser.Report, error) {
+	return []news.News{{Title: "Stub", SourceName: name}}, parser.Report{}, nil
+}
+
+func TestParsers_Register(t *testing.T) {
+	parsers := NewParsers()
+
+	assert.NoError(t, parsers.Register("ATOM", stubParser{}))
+	assert.Error(t, parsers.Register("ATOM", parser.Rss{}), "the registered type must not be replaced")
+	assert.Error(t, parsers.Register("", stubParser{}))
+	assert.Error(t, parsers.Register("NIL", nil))
+	assert.NoError(t, parsers.Register(source.RSS, parser.Rss{}))
+	assert.Equal(t, []source.Type{"ATOM", source.RSS}, parsers.Types())
+
+	registeredParser, err := parsers.GetParserBySourceType("ATOM")
+	assert.NoError(t, err)
+	assert.Equal(t, stubParser{}, registeredParser)
+
+	assert.NoError(t, parsers.Override("ATOM", parser.Rss{}))
+	registeredParser, err = parsers.GetParserBySourceType("ATOM")
+	assert.NoError(t, err)
+	assert.Equal(t, parser.Rss{}, registeredParser)
+
+	_, err = parsers.GetParserBySourceType("UNKNOWN")
+	assert.ErrorIs(t, err, validator.ErrUnknownSourceType)
+	assert.ErrorContains(t, err, "ATOM, RSS")
+}
@@ -0,0 +1,142 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io"
+	"news-aggregator/constant"
+	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
+	"news-aggregator/fetcher"
+	"news-aggregator/parser"
+	"news-aggregator/parser/charset"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// mirrorExtensions maps the types of the sources to the extensions of their mirrored files.
+var mirrorExtensions = map[source.Type]string{
+	source.RSS:      ".xml",
+	source.JSON:     ".json",
+	source.HTML:     ".html",
+	source.SITEMAP:  ".xml",
+	source.EMAIL:    ".mbox",
+	source.UsaToday: ".html",
+}
+
+// FetchSource downloads the file of the remote source and passes its body to the parser
+// while it is being downloaded. The file is not saved. The body is decoded by the charset of
+// the Content-Type header, except the mailboxes whose messages declare their own charsets.
+func FetchSource(ctx context.Context, sourceFetcher *fetcher.Fetcher, sourceParser Parser, currentSource source.Source) ([]news.News, parser.Report, error) {
+	response, err := sourceFetcher.Fetch(ctx, string(currentSource.URL), fetcher.Validators{})
+	if err != nil {
+		return nil, parser.Report{}, err
+	}
+	defer closeResponse(response)
+	var body io.Reader = response.Body
+	if currentSource.SourceType != source.EMAIL {
+		body = charset.NewReader(response.Body, response.ContentType)
+	}
+	return parseSource(ctx, sourceParser, body, currentSource)
+}
+
+// MirrorSource downloads the file of the remote source to the PathToFile of the source.
+// The conditional request is sent with the stored validators, and the source is returned
+// unchanged with false if the file is not modified. The file is replaced only if it is
+// parsed without errors, the returned source contains the path and the new validators.
+// The items skipped or repaired by the parser are logged.
+func (pm *Parsers) MirrorSource(ctx context.Context, sourceFetcher *fetcher.Fetcher, currentSource source.Source) (source.Source, bool, error) {
+	if currentSource.URL == "" {
+		return currentSource, false, fmt.Errorf("source %s does not have the URL", currentSource.Name)
+	}
+	sourceParser, err := pm.GetParserBySourceType(currentSource.SourceType)
+	if err != nil {
+		return currentSource, false, err
+	}
+
+	response, err := sourceFetcher.Fetch(ctx, string(currentSource.URL), fetcher.Validators{
+		ETag:         currentSource.ETag,
+		LastModified: currentSource.LastModified,
+	})
+	if err != nil {
+		return currentSource, false, err
+	}
+	if response.NotModified {
+		logrus.Info("collector: Source is not modified: ", currentSource.Name)
+		return currentSource, false, nil
+	}
+	defer closeResponse(response)
+
+	path := currentSource.PathToFile
+	if path == "" {
+		path = mirrorPath(currentSource)
+	}
+	if err := writeMirror(ctx, sourceParser, response.Body, currentSource, path); err != nil {
+		return currentSource, false, err
+	}
+
+	currentSource.PathToFile = path
+	currentSource.ETag = response.Validators.ETag
+	currentSource.LastModified = response.Validators.LastModified
+	logrus.Info("collector: Source is mirrored to: ", path)
+	return currentSource, true, nil
+}
+
+// writeMirror saves the body to the file of the path while the parser checks it.
+// The previous file is kept if the body cannot be parsed.
+func writeMirror(ctx context.Context, sourceParser Parser, body io.Reader, currentSource source.Source, path source.PathToFile) error {
+	directory := filepath.Dir(string(path))
+	if err := os.MkdirAll(directory, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	file, err := os.CreateTemp(directory, filepath.Base(string(path))+".*.download")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer func() {
+		if err := os.Remove(file.Name()); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logrus.Error("collector: Failed to remove downloaded file: ", err)
+		}
+	}()
+
+	reader := io.TeeReader(body, file)
+	_, report, err := parseSource(ctx, sourceParser, reader, currentSource)
+	if err == nil {
+		report.Log()
+		_, err = io.Copy(io.Discard, reader)
+	}
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to mirror source %s: %w", currentSource.Name, err)
+	}
+	return os.Rename(file.Name(), string(path))
+}
+
+// parseSource parses the reader by the parser passing the settings of the source to the SourceParser.
+func parseSource(ctx context.Context, sourceParser Parser, reader io.Reader, currentSource source.Source) ([]news.News, parser.Report, error) {
+	if configurableParser, ok := sourceParser.(SourceParser); ok {
+		return configurableParser.ParseSource(ctx, reader, currentSource)
+	}
+	return sourceParser.Parse(ctx, reader, currentSource.Name)
+}
+
+// mirrorPath returns the default path of the mirrored file in the directory of the source.
+func mirrorPath(currentSource source.Source) source.PathToFile {
+	name := strings.ToLower(string(currentSource.Name))
+	extension, exists := mirrorExtensions[currentSource.SourceType]
+	if !exists {
+		extension = ".txt"
+	}
+	return source.PathToFile(filepath.ToSlash(filepath.Join(constant.PathToResources, name, name+extension)))
+}
+
+func closeResponse(response *fetcher.Response) {
+	if err := response.Body.Close(); err != nil {
+		logrus.Error("collector: Failed to close response body: ", err)
+	}
+}
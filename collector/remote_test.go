@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"news-aggregator/entity/source"
+	"news-aggregator/fetcher"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsers_MirrorSource(t *testing.T) {
+	feed, err := os.ReadFile("../mnt/resources/testdata/rich_rss.xml")
+	require.NoError(t, err)
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch {
+		case r.URL.Path == "/broken":
+			_, _ = w.Write([]byte("<rss><channel><item>"))
+		case r.Header.Get("If-None-Match") == `"v1"`:
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write(feed)
+		}
+	}))
+	defer server.Close()
+
+	parsers := GetDefaultParsers()
+	sourceFetcher := fetcher.New(fetcher.DefaultConfig())
+	path := source.PathToFile(filepath.Join(t.TempDir(), "rich", "rich.xml"))
+	remoteSource := source.Source{Name: "rich", PathToFile: path, SourceType: source.RSS, URL: source.Link(server.URL)}
+
+	mirroredSource, updated, err := parsers.MirrorSource(context.Background(), sourceFetcher, remoteSource)
+	require.NoError(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, `"v1"`, mirroredSource.ETag)
+	mirroredFeed, err := os.ReadFile(string(path))
+	require.NoError(t, err)
+	assert.Equal(t, feed, mirroredFeed)
+
+	notModifiedSource, updated, err := parsers.MirrorSource(context.Background(), sourceFetcher, mirroredSource)
+	require.NoError(t, err)
+	assert.False(t, updated)
+	assert.Equal(t, mirroredSource, notModifiedSource)
+
+	brokenSource := mirroredSource
+	brokenSource.URL = source.Link(server.URL + "/broken")
+	brokenSource.ETag = ""
+	_, updated, err = parsers.MirrorSource(context.Background(), sourceFetcher, brokenSource)
+	assert.Error(t, err)
+	assert.False(t, updated)
+	mirroredFeed, err = os.ReadFile(string(path))
+	require.NoError(t, err)
+	assert.Equal(t, feed, mirroredFeed, "the previous file must be kept when the new one is not parsed")
+
+	entries, err := os.ReadDir(filepath.Dir(string(path)))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, 3, requests)
+}
+
+func TestFindNewsForRemoteSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "../mnt/resources/testdata/json_articles.json")
+	}))
+	defer server.Close()
+
+	beforeEach()
+	remoteSource := source.Source{Name: "remote", SourceType: source.JSON, URL: source.Link(server.URL)}
+
+	got, _, err := testArticleCollector.findNewsForCurrentSource(context.Background(), remoteSource, "remote")
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, source.Name("remote"), got[0].SourceName)
+}
@@ -1,5 +1,7 @@
 package constant
 
+import "time"
+
 const DateOutputLayout = "2006-01-02"
 
 var PathToStorage = "mnt/sources_storage.json"
@@ -8,5 +10,12 @@ const PORT = "443"
 
 var PathToResources = "mnt/resources"
 
+var PathToSearchIndex = "mnt/resources/search_index.json"
+
+var PathToSynonyms = "mnt/synonyms.json"
+
 const PathToCertFile = "web/certificates/server.crt"
 const PathToKeyFile = "web/certificates/server.key"
+
+// RequestTimeout is the default time of the handling of the request by the web server.
+const RequestTimeout = 30 * time.Second
@@ -0,0 +1,186 @@
+package news
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ID is the stable identifier of the news. It does not change when the title
+// or the description of the article are edited.
+type ID string
+
+// trackingParameterPrefixes lists the query parameters which are used only for
+// tracking of the readers and do not change the article the link points to.
+var trackingParameterPrefixes = []string{"utm_"}
+
+var trackingParameters = map[string]struct{}{
+	"fbclid":  {},
+	"gclid":   {},
+	"dclid":   {},
+	"yclid":   {},
+	"msclkid": {},
+	"mc_cid":  {},
+	"mc_eid":  {},
+	"igshid":  {},
+	"_ga":     {},
+	"ocid":    {},
+	"cmpid":   {},
+}
+
+// NewID builds the identifier of the news. The feed GUID is used when the source
+// provides it, otherwise the identifier is built from the canonical form of the link.
+// It returns an empty ID if neither the GUID nor the link are present.
+func NewID(guid string, link Link) ID {
+	key := strings.TrimSpace(guid)
+	if key == "" {
+		key = string(CanonicalLink(link))
+	} else if isAbsoluteURL(key) {
+		key = string(CanonicalLink(Link(key)))
+	}
+	if key == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(key))
+	return ID(hex.EncodeToString(sum[:16]))
+}
+
+// CanonicalLink returns the canonical form of the link: the scheme and the host are
+// lower-cased, the default port, the fragment and the tracking parameters are removed
+// and the rest of the query parameters are sorted.
+func CanonicalLink(link Link) Link {
+	rawLink := strings.TrimSpace(string(link))
+	parsedLink, err := url.Parse(rawLink)
+	if err != nil || parsedLink.Host == "" {
+		return Link(rawLink)
+	}
+
+	parsedLink.Scheme = strings.ToLower(parsedLink.Scheme)
+	parsedLink.Host = strings.ToLower(parsedLink.Host)
+	if port := parsedLink.Port(); (parsedLink.Scheme == "http" && port == "80") ||
+		(parsedLink.Scheme == "https" && port == "443") {
+		parsedLink.Host = parsedLink.Hostname()
+	}
+	if parsedLink.Path == "" {
+		parsedLink.Path = "/"
+	}
+	parsedLink.Fragment = ""
+	parsedLink.RawFragment = ""
+
+	query := parsedLink.Query()
+	for parameter := range query {
+		if isTrackingParameter(parameter) {
+			query.Del(parameter)
+		}
+	}
+	parsedLink.RawQuery = query.Encode()
+
+	return Link(parsedLink.String())
+}
+
+// WithID returns the copy of the news with the identifier built from the link
+// if the news does not have it yet.
+func (n News) WithID() News {
+	if n.ID == "" {
+		n.ID = NewID("", n.Link)
+	}
+	return n
+}
+
+// RemoveDuplicates returns the news without the articles which have the same
+// identifier as one of the previous articles. The news without identifier
+// and without link are always kept.
+func RemoveDuplicates(articles []News) []News {
+	seen := make(map[ID]struct{}, len(articles))
+	var uniqueArticles []News
+	for _, article := range articles {
+		article = article.WithID()
+		if article.ID != "" {
+			if _, exists := seen[article.ID]; exists {
+				continue
+			}
+			seen[article.ID] = struct{}{}
+		}
+		uniqueArticles = append(uniqueArticles, article)
+	}
+	return uniqueArticles
+}
+
+// MergeNews returns the stored news updated by the fetched news and the number of the fetched
+// articles which are new or differ from the stored ones. The stored article is replaced in its
+// place by the fetched article with the same identifier if their content differs, so the edited
+// title or description of the article is saved, and the other fetched articles are added after
+// the stored news. The replaced article keeps the stored date if the date of the fetched article
+// is inferred, since the inferred date changes with every fetch.
+func MergeNews(stored, fetched []News) ([]News, int) {
+	merged := make([]News, 0, len(stored)+len(fetched))
+	positions := make(map[ID]int, len(stored))
+	for _, article := range stored {
+		article = article.WithID()
+		if article.ID != "" {
+			positions[article.ID] = len(merged)
+		}
+		merged = append(merged, article)
+	}
+
+	changed := 0
+	for _, article := range RemoveDuplicates(fetched) {
+		position, exists := positions[article.ID]
+		if article.ID == "" || !exists {
+			merged = append(merged, article)
+			changed++
+			continue
+		}
+		if !sameContent(merged[position], article) {
+			if article.DateInferred {
+				article.Date, article.DateInferred = merged[position].Date, merged[position].DateInferred
+			}
+			merged[position] = article
+			changed++
+		}
+	}
+	return merged, changed
+}
+
+// sameContent reports whether the articles have the same title, description, content, link,
+// authors, categories, images and update time. The other fields, like the date, are not compared,
+// so the article is not saved again only because they are different in the fetched feed.
+func sameContent(stored, fetched News) bool {
+	return stored.Title == fetched.Title &&
+		stored.Description == fetched.Description &&
+		stored.Content == fetched.Content &&
+		stored.Link == fetched.Link &&
+		slices.Equal(stored.Authors, fetched.Authors) &&
+		slices.Equal(stored.Categories, fetched.Categories) &&
+		slices.Equal(stored.Images, fetched.Images) &&
+		sameTime(stored.UpdatedAt, fetched.UpdatedAt)
+}
+
+// sameTime reports whether the optional times are both missing or are the same instant.
+func sameTime(first, second *time.Time) bool {
+	if first == nil || second == nil {
+		return first == second
+	}
+	return first.Equal(*second)
+}
+
+func isTrackingParameter(parameter string) bool {
+	parameter = strings.ToLower(parameter)
+	if _, exists := trackingParameters[parameter]; exists {
+		return true
+	}
+	for _, prefix := range trackingParameterPrefixes {
+		if strings.HasPrefix(parameter, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAbsoluteURL(value string) bool {
+	parsedURL, err := url.Parse(value)
+	return err == nil && (parsedURL.Scheme == "http" || parsedURL.Scheme == "https") && parsedURL.Host != ""
+}
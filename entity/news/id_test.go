@@ -0,0 +1,125 @@
+package news
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalLink(t *testing.T) {
+	tests := []struct {
+		name string
+		link Link
+		want Link
+	}{
+		{
+			name: "Lower-cased host and scheme",
+			link: "HTTPS://WWW.Example.COM/World/Story",
+			want: "https://www.example.com/World/Story",
+		},
+		{
+			name: "Tracking parameters and fragment are removed",
+			link: "https://example.com/story?utm_source=rss&utm_medium=feed&id=5&fbclid=abc#comments",
+			want: "https://example.com/story?id=5",
+		},
+		{
+			name: "Query parameters are sorted",
+			link: "https://example.com/story?b=2&a=1",
+			want: "https://example.com/story?a=1&b=2",
+		},
+		{
+			name: "Default port is removed",
+			link: "http://example.com:80",
+			want: "http://example.com/",
+		},
+		{
+			name: "Relative link is kept as is",
+			link: " /story/1 ",
+			want: "/story/1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CanonicalLink(tt.link))
+		})
+	}
+}
+
+func TestNewID(t *testing.T) {
+	assert.Equal(t,
+		NewID("", "https://example.com/story?utm_campaign=x"),
+		NewID("", "https://EXAMPLE.com/story#top"),
+		"links which differ only in tracking data must have the same ID")
+	assert.Equal(t,
+		NewID("https://www.bbc.com/news/articles/1#0", "https://www.bbc.com/news/articles/1?at_medium=RSS"),
+		NewID("https://www.bbc.com/news/articles/1#1", ""),
+		"URL-like GUIDs must be canonicalized")
+	assert.NotEqual(t, NewID("guid-1", "https://example.com/story"), NewID("", "https://example.com/story"))
+	assert.NotEqual(t, NewID("", "https://example.com/story/1"), NewID("", "https://example.com/story/2"))
+	assert.Empty(t, NewID("", ""))
+}
+
+func TestRemoveDuplicates(t *testing.T) {
+	articles := []News{
+		{Title: "Original title", Link: "https://example.com/story?utm_source=a"},
+		{Title: "Edited title", Link: "https://example.com/story?utm_source=b"},
+		{Title: "Same title", Link: "https://example.com/other"},
+		{Title: "Same title", Link: "https://example.com/another"},
+		{Title: "Without link"},
+		{Title: "Without link"},
+	}
+
+	got := RemoveDuplicates(articles)
+
+	var titles []Title
+	for _, article := range got {
+		titles = append(titles, article.Title)
+	}
+	assert.Equal(t, []Title{"Original title", "Same title", "Same title", "Without link", "Without link"}, titles)
+	assert.Equal(t, NewID("", "https://example.com/story"), got[0].ID)
+}
+
+func TestMergeNews(t *testing.T) {
+	stored := []News{
+		{Title: "Old headline", Description: "Old description", Link: "https://example.com/story?utm_source=rss"},
+		{Title: "Unchanged", Link: "https://example.com/unchanged"},
+	}
+	fetched := []News{
+		{Title: "Edited headline", Description: "Edited description", Link: "https://example.com/story"},
+		{Title: "Unchanged", Link: "https://example.com/unchanged", Date: time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{Title: "New story", Link: "https://example.com/new"},
+	}
+
+	merged, changed := MergeNews(stored, fetched)
+
+	var titles []Title
+	for _, article := range merged {
+		titles = append(titles, article.Title)
+	}
+	assert.Equal(t, []Title{"Edited headline", "Unchanged", "New story"}, titles, "the edited article must replace the stored one")
+	assert.Equal(t, Description("Edited description"), merged[0].Description)
+	assert.True(t, merged[1].Date.IsZero(), "the article with the same content must be kept")
+	assert.Equal(t, 2, changed)
+
+	_, changed = MergeNews(merged, fetched)
+	assert.Zero(t, changed, "the same news must not be reported as changed")
+
+	edited := []News{{Title: "New story", Link: "https://example.com/new", Content: "Full text", Authors: []string{"Jane Doe"}}}
+	merged, changed = MergeNews(merged, edited)
+	assert.Equal(t, 1, changed, "the article with the edited content must be reported as changed")
+	assert.Equal(t, Content("Full text"), merged[2].Content)
+
+	published := time.Date(2024, time.May, 19, 10, 0, 0, 0, time.UTC)
+	stored = []News{{Title: "Story", Link: "https://example.com/dated", Date: published}}
+	fetched = []News{{Title: "Edited story", Link: "https://example.com/dated", Date: time.Now().UTC(), DateInferred: true}}
+	merged, changed = MergeNews(stored, fetched)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, Title("Edited story"), merged[0].Title)
+	assert.Equal(t, published, merged[0].Date, "the stored date must be kept instead of the inferred one")
+	assert.False(t, merged[0].DateInferred)
+
+	fetched[0].Date = fetched[0].Date.Add(time.Hour)
+	_, changed = MergeNews(merged, fetched)
+	assert.Zero(t, changed, "the article with only the new inferred date must not be reported as changed")
+}
@@ -6,12 +6,29 @@ import (
 )
 
 // News is the set of information about news articles in the system.
+// The Date is in UTC, DateInferred reports whether the date or its year was not
+// provided by the source and was inferred from the fetch time or a fallback.
+// Score is the relevance of the news to the keywords of the search, it is set only by the search.
+// Sources and Related are set only by the grouping into the stories: the sources which cover
+// the story of the news and the IDs of the other news of the story. These fields are set only
+// for the response and are not saved to the storage.
 type News struct {
-	Title       Title       `json:"title"`
-	Description Description `json:"description"`
-	Link        Link        `json:"url"`
-	Date        time.Time   `json:"publishedAt"`
-	SourceName  source.Name
+	ID           ID          `json:"id"`
+	Title        Title       `json:"title"`
+	Description  Description `json:"description"`
+	Link         Link        `json:"url"`
+	Date         time.Time   `json:"publishedAt"`
+	DateInferred bool        `json:"dateInferred,omitempty"`
+	SourceName   source.Name
+	Authors      []string      `json:"authors,omitempty"`
+	Categories   []string      `json:"categories,omitempty"`
+	Images       []Link        `json:"images,omitempty"`
+	Content      Content       `json:"content,omitempty"`
+	UpdatedAt    *time.Time    `json:"updatedAt,omitempty"`
+	Language     string        `json:"language,omitempty"`
+	Score        float64       `json:"score,omitempty"`
+	Sources      []source.Name `json:"sources,omitempty"`
+	Related      []ID          `json:"related,omitempty"`
 }
 
 // Description provides brief information about the news.
@@ -21,6 +38,13 @@ func (d Description) String() string {
 	return string(d)
 }
 
+// Content contains the full text of the news if the source provides it.
+type Content string
+
+func (c Content) String() string {
+	return string(c)
+}
+
 // Link contains the url of the news.
 type Link string
 
@@ -6,6 +6,8 @@ import (
 	"github.com/sirupsen/logrus"
 	"io"
 	"os"
+	"slices"
+	"strings"
 )
 
 // PathToFile describes the path to a specific source in the system
@@ -26,6 +28,56 @@ type Source struct {
 	PathToFile PathToFile
 	SourceType Type
 	Link       Link
+	Selectors  *Selectors `json:",omitempty"`
+	// URL is the link to the remote file of the source. The file is fetched over HTTP
+	// and mirrored to the PathToFile when the URL is set.
+	URL Link `json:",omitempty"`
+	// ETag and LastModified are the validators of the last fetched file,
+	// they allow to skip the unchanged file of the remote source.
+	ETag         string `json:",omitempty"`
+	LastModified string `json:",omitempty"`
+	// Tags group the sources, like world, ukraine or tech. The queries select
+	// all sources with the tag by the "tag:" prefix.
+	Tags []string `json:",omitempty"`
+	// Language is the code of the language of the news of the source, like en or uk.
+	// The language of the news is detected from its text if it is empty.
+	Language string `json:",omitempty"`
+}
+
+// Selectors describes where the HTML scraper finds the news on the page of the source.
+// The selectors of the item's fields are applied inside the item, the empty selector means the item itself.
+type Selectors struct {
+	// Item selects the elements of the single news on the page.
+	Item string
+	// Title selects the element with the title, its text is used as the title.
+	Title string `json:",omitempty"`
+	// Link selects the element with the href attribute.
+	Link string `json:",omitempty"`
+	// Description selects the element with the description.
+	Description string `json:",omitempty"`
+	// DescriptionAttribute is the attribute with the description, the text of the element is used if it is empty.
+	DescriptionAttribute string `json:",omitempty"`
+	// Date selects the element with the publication date.
+	Date string `json:",omitempty"`
+	// DateAttribute is the attribute with the date, the text of the element is used if it is empty.
+	DateAttribute string `json:",omitempty"`
+	// DatePattern is the regular expression which extracts the date from the attribute or the text.
+	DatePattern string `json:",omitempty"`
+	// DateLayout is the layout of the date in the format of the time package.
+	// The current year is used if the layout does not contain the year.
+	DateLayout string `json:",omitempty"`
+	// Category selects the element with the category of the news.
+	Category string `json:",omitempty"`
+	// CategoryAttribute is the attribute with the category, the text of the element is used if it is empty.
+	CategoryAttribute string `json:",omitempty"`
+	// Image selects the image elements of the news.
+	Image string `json:",omitempty"`
+	// ImageAttribute is the attribute with the link to the image, the src attribute is used if it is empty.
+	ImageAttribute string `json:",omitempty"`
+	// Language is the language of the news on the page.
+	Language string `json:",omitempty"`
+	// BaseURL is prepended to the relative links of the news and images.
+	BaseURL string `json:",omitempty"`
 }
 
 // Stores all types of sources provided.
@@ -34,6 +86,9 @@ const (
 	JSON     Type = "JSON"
 	UsaToday Type = "UsaToday"
 	STORAGE  Type = "STORAGE"
+	HTML     Type = "HTML"
+	SITEMAP  Type = "SITEMAP"
+	EMAIL    Type = "EMAIL"
 )
 
 // LoadExistingSourcesFromStorage loads sources from a JSON file
@@ -64,3 +119,15 @@ func LoadExistingSourcesFromStorage(filename string) ([]Source, error) {
 
 	return sources, nil
 }
+
+// NormalizeTags returns the lowercase tags without the surrounding spaces, the empty tags and the duplicates.
+func NormalizeTags(tags []string) []string {
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,5 @@
+// Package fetcher downloads the remote sources over HTTP.
+// Fetcher sends the conditional requests with the stored ETag and Last-Modified values,
+// so the unchanged sources are answered with 304 and are not parsed again.
+// The timeouts, the maximum size of the body and the retries with backoff are set by Config.
+package fetcher
@@ -0,0 +1,195 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ErrBodyTooLarge is returned when the body of the response exceeds the maximum size.
+var ErrBodyTooLarge = errors.New("response body is too large")
+
+// Config describes the limits of the requests sent by the Fetcher.
+type Config struct {
+	// Timeout limits the whole request including the reading of the body.
+	Timeout time.Duration
+	// ResponseHeaderTimeout limits the waiting for the headers of the response.
+	ResponseHeaderTimeout time.Duration
+	// MaxBodySize is the maximum size of the body in bytes, zero means no limit.
+	MaxBodySize int64
+	// Retries is the number of the repeated requests after the network error or the server error.
+	Retries int
+	// Backoff is the delay before the first retry, it is doubled before each next retry.
+	Backoff time.Duration
+	// UserAgent is sent in the User-Agent header.
+	UserAgent string
+}
+
+// DefaultConfig returns the configuration used by the aggregator.
+func DefaultConfig() Config {
+	return Config{
+		Timeout:               30 * time.Second,
+		ResponseHeaderTimeout: 10 * time.Second,
+		MaxBodySize:           10 << 20,
+		Retries:               2,
+		Backoff:               500 * time.Millisecond,
+		UserAgent:             "news-aggregator",
+	}
+}
+
+// Validators are the values of the previous response which allow the server
+// to answer that the source is not modified.
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// Response is the result of the fetching of the source.
+type Response struct {
+	// Body is the content of the source, it is nil if the source is not modified.
+	// The caller must close it.
+	Body io.ReadCloser
+	// NotModified reports that the server answered 304 to the conditional request.
+	NotModified bool
+	// Validators are the validators of the fetched content.
+	Validators Validators
+	// ContentType is the Content-Type header of the response, its charset is
+	// used to decode the content.
+	ContentType string
+}
+
+// Fetcher downloads the sources over HTTP.
+type Fetcher struct {
+	client *http.Client
+	config Config
+}
+
+// New creates the new instance of the Fetcher with the passed configuration.
+func New(config Config) *Fetcher {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = config.ResponseHeaderTimeout
+	return &Fetcher{
+		client: &http.Client{Timeout: config.Timeout, Transport: transport},
+		config: config,
+	}
+}
+
+// Fetch sends the GET request to the url. The passed validators are sent in the
+// If-None-Match and If-Modified-Since headers. The request is repeated after the
+// network errors and the 5xx and 429 answers until the retries are exhausted.
+func (fetcher *Fetcher) Fetch(ctx context.Context, url string, validators Validators) (*Response, error) {
+	var lastErr error
+	for attempt := 0; attempt <= fetcher.config.Retries; attempt++ {
+		if attempt > 0 {
+			if err := fetcher.wait(ctx, attempt); err != nil {
+				return nil, err
+			}
+			logrus.Infof("fetcher: Retrying request to %s, attempt %d", url, attempt+1)
+		}
+
+		response, retry, err := fetcher.fetch(ctx, url, validators)
+		if err == nil {
+			return response, nil
+		}
+		if !retry || ctx.Err() != nil {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// fetch sends the single request and reports whether the failed request may be repeated.
+func (fetcher *Fetcher) fetch(ctx context.Context, url string, validators Validators) (*Response, bool, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if fetcher.config.UserAgent != "" {
+		request.Header.Set("User-Agent", fetcher.config.UserAgent)
+	}
+	if validators.ETag != "" {
+		request.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		request.Header.Set("If-Modified-Since", validators.LastModified)
+	}
+
+	response, err := fetcher.client.Do(request)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+
+	switch {
+	case response.StatusCode == http.StatusNotModified:
+		closeBody(response.Body)
+		return &Response{NotModified: true, Validators: validators}, false, nil
+	case response.StatusCode == http.StatusOK:
+		if fetcher.config.MaxBodySize > 0 && response.ContentLength > fetcher.config.MaxBodySize {
+			closeBody(response.Body)
+			return nil, false, fmt.Errorf("failed to fetch %s: %w", url, ErrBodyTooLarge)
+		}
+		return &Response{
+			Body: limitBody(response.Body, fetcher.config.MaxBodySize),
+			Validators: Validators{
+				ETag:         response.Header.Get("ETag"),
+				LastModified: response.Header.Get("Last-Modified"),
+			},
+			ContentType: response.Header.Get("Content-Type"),
+		}, false, nil
+	default:
+		closeBody(response.Body)
+		retry := response.StatusCode >= http.StatusInternalServerError || response.StatusCode == http.StatusTooManyRequests
+		return nil, retry, fmt.Errorf("failed to fetch %s: unexpected status %s", url, response.Status)
+	}
+}
+
+// wait sleeps before the retry or returns the error of the context if it is done earlier.
+func (fetcher *Fetcher) wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(fetcher.config.Backoff << (attempt - 1))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func closeBody(body io.ReadCloser) {
+	if err := body.Close(); err != nil {
+		logrus.Error("fetcher: Failed to close response body: ", err)
+	}
+}
+
+// limitedBody returns ErrBodyTooLarge after the reading of more than the maximum size.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func limitBody(body io.ReadCloser, maxBodySize int64) io.ReadCloser {
+	if maxBodySize <= 0 {
+		return body
+	}
+	return &limitedBody{ReadCloser: body, remaining: maxBodySize}
+}
+
+func (body *limitedBody) Read(p []byte) (int, error) {
+	if body.remaining < 0 {
+		return 0, ErrBodyTooLarge
+	}
+	if int64(len(p)) > body.remaining+1 {
+		p = p[:body.remaining+1]
+	}
+	n, err := body.ReadCloser.Read(p)
+	body.remaining -= int64(n)
+	if body.remaining < 0 {
+		return n + int(body.remaining), ErrBodyTooLarge
+	}
+	return n, err
+}
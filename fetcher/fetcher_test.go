@@ -0,0 +1,122 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() Config {
+	config := DefaultConfig()
+	config.Timeout = time.Second
+	config.Backoff = time.Millisecond
+	return config
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 03 Jun 2024 10:00:00 GMT")
+		_, _ = w.Write([]byte("<rss></rss>"))
+	}))
+	defer server.Close()
+
+	fetcher := New(testConfig())
+
+	response, err := fetcher.Fetch(context.Background(), server.URL, Validators{})
+	require.NoError(t, err)
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.NoError(t, response.Body.Close())
+	assert.Equal(t, "<rss></rss>", string(body))
+	assert.False(t, response.NotModified)
+	assert.Equal(t, Validators{ETag: `"v1"`, LastModified: "Mon, 03 Jun 2024 10:00:00 GMT"}, response.Validators)
+
+	response, err = fetcher.Fetch(context.Background(), server.URL, response.Validators)
+	require.NoError(t, err)
+	assert.True(t, response.NotModified)
+	assert.Nil(t, response.Body)
+}
+
+func TestFetcher_FetchRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	response, err := New(testConfig()).Fetch(context.Background(), server.URL, Validators{})
+	require.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, int32(3), requests.Load())
+
+	requests.Store(0)
+	config := testConfig()
+	config.Retries = 1
+	_, err = New(config).Fetch(context.Background(), server.URL, Validators{})
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestFetcher_FetchErrors(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		case "/stream":
+			w.(http.Flusher).Flush()
+			_, _ = w.Write([]byte(strings.Repeat("a", 100)))
+		default:
+			_, _ = w.Write([]byte(strings.Repeat("a", 100)))
+		}
+	}))
+	defer server.Close()
+
+	config := testConfig()
+	config.MaxBodySize = 10
+	fetcher := New(config)
+
+	_, err := fetcher.Fetch(context.Background(), server.URL+"/missing", Validators{})
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), requests.Load(), "client errors must not be retried")
+
+	_, err = fetcher.Fetch(context.Background(), server.URL+"/large", Validators{})
+	assert.ErrorIs(t, err, ErrBodyTooLarge)
+
+	response, err := fetcher.Fetch(context.Background(), server.URL+"/stream", Validators{})
+	require.NoError(t, err)
+	_, err = io.ReadAll(response.Body)
+	assert.ErrorIs(t, err, ErrBodyTooLarge)
+	require.NoError(t, response.Body.Close())
+
+	config.Timeout = 50 * time.Millisecond
+	config.Retries = 0
+	_, err = New(config).Fetch(context.Background(), server.URL+"/slow", Validators{})
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = fetcher.Fetch(ctx, server.URL, Validators{})
+	assert.True(t, errors.Is(err, context.Canceled))
+}
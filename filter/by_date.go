@@ -7,7 +7,8 @@ import (
 )
 
 // ByDate filters the slice of news by a provided date range and returns
-// the slice of matching news.
+// the slice of matching news. The bounds are inclusive, the zero bound
+// leaves the range open from its side.
 type ByDate struct {
 	StartDate time.Time
 	EndDate   time.Time
@@ -17,9 +18,13 @@ type ByDate struct {
 func (dateFilter ByDate) Filter(articles []news.News) []news.News {
 	var matchingNews []news.News
 	for _, a := range articles {
-		if a.Date.After(dateFilter.StartDate) && a.Date.Before(dateFilter.EndDate) {
-			matchingNews = append(matchingNews, a)
+		if !dateFilter.StartDate.IsZero() && a.Date.Before(dateFilter.StartDate) {
+			continue
 		}
+		if !dateFilter.EndDate.IsZero() && a.Date.After(dateFilter.EndDate) {
+			continue
+		}
+		matchingNews = append(matchingNews, a)
 	}
 	if len(matchingNews) == 0 {
 		fmt.Println("No articles were found in this time period.")
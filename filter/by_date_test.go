@@ -40,6 +40,53 @@ func TestByDate_Filter(t *testing.T) {
 				{Title: "News 2", Date: parseDate("2023-06-10")},
 			},
 		},
+		{
+			name: "Bounds are inclusive",
+			fields: fields{
+				StartDate: parseDate("2023-01-01"),
+				EndDate:   parseDate("2023-12-31"),
+			},
+			args: args{
+				articles: []news.News{
+					{Title: "News 1", Date: parseDate("2023-01-01")},
+					{Title: "News 2", Date: parseDate("2023-12-31")},
+				},
+			},
+			want: []news.News{
+				{Title: "News 1", Date: parseDate("2023-01-01")},
+				{Title: "News 2", Date: parseDate("2023-12-31")},
+			},
+		},
+		{
+			name: "Only start date",
+			fields: fields{
+				StartDate: parseDate("2023-06-01"),
+			},
+			args: args{
+				articles: []news.News{
+					{Title: "News 1", Date: parseDate("2023-03-15")},
+					{Title: "News 2", Date: parseDate("2024-01-01")},
+				},
+			},
+			want: []news.News{
+				{Title: "News 2", Date: parseDate("2024-01-01")},
+			},
+		},
+		{
+			name: "Only end date",
+			fields: fields{
+				EndDate: parseDate("2023-06-01"),
+			},
+			args: args{
+				articles: []news.News{
+					{Title: "News 1", Date: parseDate("2023-03-15")},
+					{Title: "News 2", Date: parseDate("2024-01-01")},
+				},
+			},
+			want: []news.News{
+				{Title: "News 1", Date: parseDate("2023-03-15")},
+			},
+		},
 		{
 			name: "Empty news list",
 			fields: fields{
@@ -2,34 +2,32 @@ package filter
 
 import (
 	"fmt"
-	"github.com/reiver/go-porterstemmer"
+	"news-aggregator/analysis"
 	"news-aggregator/entity/news"
 	"strings"
 )
 
 // ByKeyword filters the slice of news by provided keyword and returns
-// the slice of matching news.
+// the slice of matching news. The keywords are expanded by the dictionary of Synonyms,
+// which may be nil.
 type ByKeyword struct {
 	Keywords []string
+	Synonyms *analysis.Synonyms
 }
 
-// Filter filters the incoming news from different sources by keywords.
+// Filter filters the incoming news from different sources by keywords. The keywords are
+// expanded by their synonyms and stemmed in every language of their script, and the news are
+// stemmed in their language without the stopwords. The news which match several keywords
+// are returned once in their original order.
 func (keywordFilter ByKeyword) Filter(newsArticles []news.News) []news.News {
-	var matchingNews []news.News
+	var variants []string
 	for _, keyword := range keywordFilter.Keywords {
-		stemmedKeyword := porterstemmer.StemString(strings.ToLower(keyword))
-		matchingNews = append(matchingNews, filterNewsByKeyword(stemmedKeyword, newsArticles)...)
+		variants = append(variants, analysis.KeywordVariants(keyword, keywordFilter.Synonyms)...)
 	}
-	return matchingNews
-}
 
-// filterNewsByKeyword filters the incoming news by keyword and returns the filtered list.
-func filterNewsByKeyword(keyword string, newsArticles []news.News) []news.News {
 	var matchingNews []news.News
-
 	for _, article := range newsArticles {
-
-		if matchesConditions(article, keyword) {
+		if matchesConditions(article, variants) {
 			matchingNews = append(matchingNews, article)
 		}
 	}
@@ -41,16 +39,19 @@ func filterNewsByKeyword(keyword string, newsArticles []news.News) []news.News {
 	return matchingNews
 }
 
-// matchesConditions checks if news matches at least one condition.
-func matchesConditions(a news.News, keyword string) bool {
+// matchesConditions checks if the stemmed title or description of the news contains at least one variant of the keywords.
+func matchesConditions(a news.News, variants []string) bool {
+	language := analysis.LanguageOf(a)
 	conditions := []string{
-		porterstemmer.StemString(strings.ToLower(string(a.Title))),
-		porterstemmer.StemString(strings.ToLower(string(a.Description))),
+		strings.Join(analysis.Terms(string(a.Title), language), " "),
+		strings.Join(analysis.Terms(string(a.Description), language), " "),
 	}
 
 	for _, condition := range conditions {
-		if strings.Contains(condition, keyword) {
-			return true
+		for _, variant := range variants {
+			if strings.Contains(condition, variant) {
+				return true
+			}
 		}
 	}
 	return false
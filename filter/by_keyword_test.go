@@ -1,6 +1,7 @@
 package filter
 
 import (
+	"news-aggregator/analysis"
 	"news-aggregator/entity/news"
 	"reflect"
 	"testing"
@@ -64,6 +65,25 @@ func TestByKeyword_Filter(t *testing.T) {
 				{Description: "someukrWord"},
 			},
 		},
+		{name: "News matching several keywords are returned once",
+			fields: fields{
+				Keywords: []string{
+					"ukraine", "war"},
+			},
+			args: args{
+				articles: []news.News{
+					{Title: "War in Ukraine"},
+					{Title: "News 1"},
+					{Description: "The war"},
+					{Title: "Ukraine", Description: "war"},
+				},
+			},
+			want: []news.News{
+				{Title: "War in Ukraine"},
+				{Description: "The war"},
+				{Title: "Ukraine", Description: "war"},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -76,3 +96,56 @@ func TestByKeyword_Filter(t *testing.T) {
 		})
 	}
 }
+
+func TestByKeyword_FilterLanguages(t *testing.T) {
+	synonyms := analysis.NewSynonyms([][]string{{"kyiv", "kiev", "київ", "києві", "киев", "киеве"}})
+
+	tests := []struct {
+		name     string
+		keywords []string
+		articles []news.News
+		want     []news.News
+	}{
+		{name: "Synonyms in other languages",
+			keywords: []string{"Kyiv"},
+			articles: []news.News{
+				{Title: "Повітряна тривога у Києві", Language: "uk"},
+				{Title: "Тревога в Киеве", Language: "ru"},
+				{Title: "Air alert in Kiev"},
+				{Title: "Air alert in Lviv"},
+			},
+			want: []news.News{
+				{Title: "Повітряна тривога у Києві", Language: "uk"},
+				{Title: "Тревога в Киеве", Language: "ru"},
+				{Title: "Air alert in Kiev"},
+			},
+		},
+		{name: "Inflected Ukrainian keyword",
+			keywords: []string{"новини"},
+			articles: []news.News{
+				{Title: "Головна новина дня"},
+				{Title: "Погода на завтра"},
+			},
+			want: []news.News{
+				{Title: "Головна новина дня"},
+			},
+		},
+		{name: "Inflected German keyword",
+			keywords: []string{"Häuser"},
+			articles: []news.News{
+				{Title: "Das Haus ist verkauft", Language: "de"},
+				{Title: "Die Stadt wächst", Language: "de"},
+			},
+			want: []news.News{
+				{Title: "Das Haus ist verkauft", Language: "de"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (ByKeyword{Keywords: tt.keywords, Synonyms: synonyms}).Filter(tt.articles); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Actual result: = %v Expexted: %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package filter
+
+import (
+	"fmt"
+	"news-aggregator/constant"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDateRange returns the date filter of the since and until bounds and the last window.
+// The bounds are the dates like 2024-05-19, the RFC3339 timestamps like 2024-05-19T10:00:00+03:00
+// or the durations before now like 24h, 7d or 2w. The since date means its first moment and
+// the until date means its last moment in the location of now. The last window, like 24h,
+// starts the duration before now and cannot be combined with since.
+func ParseDateRange(since, until, last string, now time.Time) (ByDate, error) {
+	var dateFilter ByDate
+	if last != "" {
+		if since != "" {
+			return ByDate{}, fmt.Errorf("last and since cannot be combined")
+		}
+		window, err := ParseDuration(last)
+		if err != nil {
+			return ByDate{}, fmt.Errorf("invalid last %q: must be duration like 24h or 7d", last)
+		}
+		dateFilter.StartDate = now.Add(-window)
+	}
+	if since != "" {
+		start, err := ParseDateBound(since, now, false)
+		if err != nil {
+			return ByDate{}, fmt.Errorf("invalid since %q: %w", since, err)
+		}
+		dateFilter.StartDate = start
+	}
+	if until != "" {
+		end, err := ParseDateBound(until, now, true)
+		if err != nil {
+			return ByDate{}, fmt.Errorf("invalid until %q: %w", until, err)
+		}
+		dateFilter.EndDate = end
+	}
+	if !dateFilter.StartDate.IsZero() && !dateFilter.EndDate.IsZero() && dateFilter.StartDate.After(dateFilter.EndDate) {
+		return ByDate{}, fmt.Errorf("since must not be after until")
+	}
+	return dateFilter, nil
+}
+
+// ParseDateBound parses the bound of the date range. The date is its first moment, or
+// its last moment if the bound is the end of the range, in the location of now.
+func ParseDateBound(value string, now time.Time, end bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if date, err := time.ParseInLocation(constant.DateOutputLayout, value, now.Location()); err == nil {
+		if end {
+			return date.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+		}
+		return date, nil
+	}
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return timestamp, nil
+	}
+	if duration, err := ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+	return time.Time{}, fmt.Errorf("must be date YYYY-MM-DD, RFC3339 timestamp or duration like 24h or 7d")
+}
+
+// ParseDuration parses the positive duration of time.ParseDuration or the whole number
+// of days or weeks like 7d or 2w.
+func ParseDuration(value string) (time.Duration, error) {
+	var duration time.Duration
+	switch unit := strings.TrimLeft(value, "0123456789"); unit {
+	case "d", "w":
+		count, err := strconv.Atoi(strings.TrimSuffix(value, unit))
+		if err != nil {
+			return 0, err
+		}
+		duration = time.Duration(count) * 24 * time.Hour
+		if unit == "w" {
+			duration *= 7
+		}
+	default:
+		var err error
+		if duration, err = time.ParseDuration(value); err != nil {
+			return 0, err
+		}
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("duration must be positive")
+	}
+	return duration, nil
+}
@@ -0,0 +1,95 @@
+package filter
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseDateRange(t *testing.T) {
+	kyiv := time.FixedZone("EEST", 3*60*60)
+	now := time.Date(2024, time.May, 19, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		since   string
+		until   string
+		last    string
+		now     time.Time
+		want    ByDate
+		wantErr bool
+	}{
+		{name: "Dates are the whole days", since: "2024-05-01", until: "2024-05-02", now: now,
+			want: ByDate{
+				StartDate: time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2024, time.May, 2, 23, 59, 59, 999999999, time.UTC),
+			}},
+		{name: "Dates in the location of now", since: "2024-05-01", now: now.In(kyiv),
+			want: ByDate{StartDate: time.Date(2024, time.May, 1, 0, 0, 0, 0, kyiv)}},
+		{name: "RFC3339 timestamps", since: "2024-05-01T10:00:00+03:00", until: "2024-05-01T12:30:00Z", now: now,
+			want: ByDate{
+				StartDate: time.Date(2024, time.May, 1, 7, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2024, time.May, 1, 12, 30, 0, 0, time.UTC),
+			}},
+		{name: "Relative bounds", since: "7d", until: "90m", now: now,
+			want: ByDate{StartDate: now.AddDate(0, 0, -7), EndDate: now.Add(-90 * time.Minute)}},
+		{name: "Weeks", since: "2w", now: now, want: ByDate{StartDate: now.AddDate(0, 0, -14)}},
+		{name: "Last window", last: "24h", now: now, want: ByDate{StartDate: now.Add(-24 * time.Hour)}},
+		{name: "Last window with until", last: "7d", until: "2024-05-18", now: now,
+			want: ByDate{
+				StartDate: now.AddDate(0, 0, -7),
+				EndDate:   time.Date(2024, time.May, 18, 23, 59, 59, 999999999, time.UTC),
+			}},
+		{name: "Empty range", now: now, want: ByDate{}},
+		{name: "Last with since", since: "2024-05-01", last: "24h", now: now, wantErr: true},
+		{name: "Since after until", since: "2024-05-02", until: "2024-05-01", now: now, wantErr: true},
+		{name: "Invalid date", since: "2024-13-01", now: now, wantErr: true},
+		{name: "Negative duration", last: "-24h", now: now, wantErr: true},
+		{name: "Zero duration", until: "0d", now: now, wantErr: true},
+		{name: "Unknown unit", since: "7y", now: now, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDateRange(tt.since, tt.until, tt.last, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDateRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.StartDate.Equal(tt.want.StartDate) || !got.EndDate.Equal(tt.want.EndDate) {
+				t.Errorf("ParseDateRange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseDateBound runs the cases which are shared with the copy of the parser in the
+// validation webhook of the operator, so both parsers accept the same bounds.
+func TestParseDateBound(t *testing.T) {
+	content, err := os.ReadFile("../mnt/resources/testdata/date_bounds.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cases struct {
+		Now   time.Time `json:"now"`
+		Cases []struct {
+			Name    string    `json:"name"`
+			Value   string    `json:"value"`
+			End     bool      `json:"end"`
+			Want    time.Time `json:"want"`
+			WantErr bool      `json:"wantErr"`
+		} `json:"cases"`
+	}
+	if err := json.Unmarshal(content, &cases); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range cases.Cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			got, err := ParseDateBound(tt.Value, cases.Now, tt.End)
+			if (err != nil) != tt.WantErr {
+				t.Fatalf("ParseDateBound() error = %v, wantErr %v", err, tt.WantErr)
+			}
+			if !got.Equal(tt.Want) {
+				t.Errorf("ParseDateBound() = %v, want %v", got, tt.Want)
+			}
+		})
+	}
+}
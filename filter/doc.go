@@ -3,4 +3,8 @@
 // The filter package defines interfaces and implementations for filtering articles by various criteria,
 // such as date ranges and keywords. This allows for flexible and customizable filtering of news articles
 // fetched from various sources.
+//
+// ParseQuery compiles the boolean search query with the AND, OR and NOT operators, the parentheses,
+// the quoted phrases, the title:, description: and source: fields and the prefix wildcards into the
+// ByQuery filter. The syntax errors of the query are returned as the QueryError with the column.
 package filter
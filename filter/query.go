@@ -0,0 +1,439 @@
+package filter
+
+import (
+	"fmt"
+	"news-aggregator/analysis"
+	"news-aggregator/entity/news"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// The fields of the news which can be scoped in the query, like "title:ukraine".
+const (
+	TitleField       = "title"
+	DescriptionField = "description"
+	SourceField      = "source"
+)
+
+// QueryError is the syntax error of the search query.
+type QueryError struct {
+	// Column is the position of the character of the query, starting from 1, where the error is found.
+	Column int
+	// Message describes the error.
+	Message string
+}
+
+func (queryError *QueryError) Error() string {
+	return fmt.Sprintf("invalid query at column %d: %s", queryError.Column, queryError.Message)
+}
+
+// ByQuery filters the slice of news by the boolean search query and returns
+// the slice of matching news. ByQuery is created by ParseQuery.
+type ByQuery struct {
+	Query      string
+	expression expression
+}
+
+// ParseQuery compiles the search query into the filter. The terms of the query are
+// matched with the words of the title and the description of the news stemmed in their
+// language, and the single words are also matched by their synonyms from the dictionary,
+// which may be nil.
+// The query supports:
+//   - AND, OR and NOT operators, the adjacent terms are joined by AND;
+//   - parentheses to group the terms;
+//   - quoted phrases like "air alert";
+//   - scoping of the terms, the phrases and the groups by the fields, like title:ukraine,
+//     description:"air alert" or source:(bbc OR cnn);
+//   - prefix wildcards like ukr*.
+//
+// The returned error is the *QueryError with the column of the syntax error.
+func ParseQuery(query string, synonyms *analysis.Synonyms) (ByQuery, error) {
+	tokens, err := tokenizeQuery(query)
+	if err != nil {
+		return ByQuery{}, err
+	}
+	queryParser := &queryParser{tokens: tokens, end: len([]rune(query)) + 1, synonyms: synonyms}
+	if len(tokens) == 0 {
+		return ByQuery{}, &QueryError{Column: 1, Message: "query is empty"}
+	}
+	parsed, err := queryParser.parseOr("")
+	if err != nil {
+		return ByQuery{}, err
+	}
+	if next := queryParser.peek(); next.kind != endToken {
+		if next.kind == closeToken {
+			return ByQuery{}, &QueryError{Column: next.column, Message: "unexpected closing parenthesis"}
+		}
+		return ByQuery{}, &QueryError{Column: next.column, Message: fmt.Sprintf("unexpected %q", next.text)}
+	}
+	return ByQuery{Query: query, expression: parsed}, nil
+}
+
+// Filter returns the news which match the query in their original order.
+func (queryFilter ByQuery) Filter(articles []news.News) []news.News {
+	var matchingNews []news.News
+	for _, article := range articles {
+		if queryFilter.expression != nil && queryFilter.expression.matches(newDocument(article)) {
+			matchingNews = append(matchingNews, article)
+		}
+	}
+	return matchingNews
+}
+
+// String returns the source text of the query.
+func (queryFilter ByQuery) String() string {
+	return queryFilter.Query
+}
+
+// word is the word of the news with its stem.
+type word struct {
+	text string
+	stem string
+}
+
+// document is the news prepared for the matching of the query.
+type document struct {
+	title       []word
+	description []word
+	source      string
+}
+
+func newDocument(article news.News) document {
+	language := analysis.LanguageOf(article)
+	return document{
+		title:       splitWords(string(article.Title), language),
+		description: splitWords(string(article.Description), language),
+		source:      strings.ToLower(string(article.SourceName)),
+	}
+}
+
+// splitWords returns the lowercase words of the text with their stems in the language.
+func splitWords(text string, language analysis.Language) []word {
+	var words []word
+	for _, field := range analysis.Words(text) {
+		words = append(words, word{text: field, stem: analysis.Stem(language, field)})
+	}
+	return words
+}
+
+// fields returns the words of the document in the scoped field or in the title and the description.
+func (doc document) fields(field string) [][]word {
+	switch field {
+	case TitleField:
+		return [][]word{doc.title}
+	case DescriptionField:
+		return [][]word{doc.description}
+	default:
+		return [][]word{doc.title, doc.description}
+	}
+}
+
+type expression interface {
+	matches(doc document) bool
+}
+
+type andExpression struct {
+	left, right expression
+}
+
+func (e andExpression) matches(doc document) bool {
+	return e.left.matches(doc) && e.right.matches(doc)
+}
+
+type orExpression struct {
+	left, right expression
+}
+
+func (e orExpression) matches(doc document) bool {
+	return e.left.matches(doc) || e.right.matches(doc)
+}
+
+type notExpression struct {
+	operand expression
+}
+
+func (e notExpression) matches(doc document) bool {
+	return !e.operand.matches(doc)
+}
+
+// queryWord is the word of the query with its stems in every language of its script.
+type queryWord struct {
+	text  string
+	stems []string
+}
+
+// termExpression matches any of the alternative sequences of the words, the single word is
+// the sequence of one word. The alternatives are the synonyms of the single word. The last
+// word is matched as the prefix of the words when the term ends with the wildcard.
+type termExpression struct {
+	field        string
+	alternatives [][]queryWord
+	prefix       bool
+	// text is the lowercase text of the term which is compared with the name of the source.
+	text string
+}
+
+func (e termExpression) matches(doc document) bool {
+	if e.field == SourceField {
+		if e.prefix {
+			return strings.HasPrefix(doc.source, e.text)
+		}
+		return doc.source == e.text
+	}
+	for _, words := range doc.fields(e.field) {
+		for _, sequence := range e.alternatives {
+			for start := 0; start+len(sequence) <= len(words); start++ {
+				if e.matchesAt(sequence, words[start:]) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (e termExpression) matchesAt(sequence []queryWord, words []word) bool {
+	for i, expected := range sequence {
+		if e.prefix && i == len(sequence)-1 {
+			if !strings.HasPrefix(words[i].text, expected.text) {
+				return false
+			}
+		} else if !slices.Contains(expected.stems, words[i].stem) {
+			return false
+		}
+	}
+	return true
+}
+
+// newSequence returns the words of the text with their stems.
+func newSequence(text string) []queryWord {
+	var sequence []queryWord
+	for _, field := range analysis.Words(text) {
+		sequence = append(sequence, queryWord{text: field, stems: analysis.Stems(field)})
+	}
+	return sequence
+}
+
+type tokenKind int
+
+const (
+	endToken tokenKind = iota
+	wordToken
+	phraseToken
+	fieldToken
+	andToken
+	orToken
+	notToken
+	openToken
+	closeToken
+)
+
+type queryToken struct {
+	kind   tokenKind
+	text   string
+	column int
+}
+
+// tokenizeQuery splits the query into the tokens, the columns of the tokens start from 1.
+func tokenizeQuery(query string) ([]queryToken, error) {
+	runes := []rune(query)
+	var tokens []queryToken
+	for position := 0; position < len(runes); {
+		current := runes[position]
+		column := position + 1
+		switch {
+		case unicode.IsSpace(current):
+			position++
+		case current == '(':
+			tokens = append(tokens, queryToken{kind: openToken, text: "(", column: column})
+			position++
+		case current == ')':
+			tokens = append(tokens, queryToken{kind: closeToken, text: ")", column: column})
+			position++
+		case current == '"':
+			end := position + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, &QueryError{Column: column, Message: "phrase is not closed by the quote"}
+			}
+			tokens = append(tokens, queryToken{kind: phraseToken, text: string(runes[position+1 : end]), column: column})
+			position = end + 1
+		default:
+			end := position
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(`()"`, runes[end]) {
+				end++
+			}
+			wordTokens, err := tokenizeWord(string(runes[position:end]), column)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, wordTokens...)
+			position = end
+		}
+	}
+	return tokens, nil
+}
+
+// tokenizeWord returns the tokens of the word which may be the operator or be scoped by the field.
+func tokenizeWord(text string, column int) ([]queryToken, error) {
+	switch text {
+	case "AND":
+		return []queryToken{{kind: andToken, text: text, column: column}}, nil
+	case "OR":
+		return []queryToken{{kind: orToken, text: text, column: column}}, nil
+	case "NOT":
+		return []queryToken{{kind: notToken, text: text, column: column}}, nil
+	}
+
+	var tokens []queryToken
+	if name, rest, found := strings.Cut(text, ":"); found && name != "" && strings.IndexFunc(name, isNotLetter) == -1 {
+		field := strings.ToLower(name)
+		if field != TitleField && field != DescriptionField && field != SourceField {
+			return nil, &QueryError{Column: column, Message: fmt.Sprintf("unknown field %q, the supported fields are %s, %s and %s",
+				name, TitleField, DescriptionField, SourceField)}
+		}
+		tokens = append(tokens, queryToken{kind: fieldToken, text: field, column: column})
+		column += len([]rune(name)) + 1
+		text = rest
+	}
+	if text == "" {
+		return tokens, nil
+	}
+	if wildcard := strings.IndexRune(text, '*'); wildcard != -1 && wildcard != len(text)-1 {
+		return nil, &QueryError{Column: column + len([]rune(text[:wildcard])), Message: "wildcard is supported only at the end of the term"}
+	}
+	return append(tokens, queryToken{kind: wordToken, text: text, column: column}), nil
+}
+
+func isNotLetter(r rune) bool {
+	return !unicode.IsLetter(r)
+}
+
+// queryParser builds the expression from the tokens by the recursive descent. The precedence
+// of the operators from the lowest is OR, AND and NOT.
+type queryParser struct {
+	tokens   []queryToken
+	position int
+	// end is the column after the end of the query.
+	end      int
+	synonyms *analysis.Synonyms
+}
+
+func (p *queryParser) peek() queryToken {
+	if p.position < len(p.tokens) {
+		return p.tokens[p.position]
+	}
+	return queryToken{kind: endToken, column: p.end}
+}
+
+func (p *queryParser) next() queryToken {
+	token := p.peek()
+	if token.kind != endToken {
+		p.position++
+	}
+	return token
+}
+
+func (p *queryParser) parseOr(field string) (expression, error) {
+	left, err := p.parseAnd(field)
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == orToken {
+		p.next()
+		right, err := p.parseAnd(field)
+		if err != nil {
+			return nil, err
+		}
+		left = orExpression{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd(field string) (expression, error) {
+	left, err := p.parseNot(field)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek().kind {
+		case andToken:
+			p.next()
+		case wordToken, phraseToken, fieldToken, notToken, openToken:
+		default:
+			return left, nil
+		}
+		right, err := p.parseNot(field)
+		if err != nil {
+			return nil, err
+		}
+		left = andExpression{left: left, right: right}
+	}
+}
+
+func (p *queryParser) parseNot(field string) (expression, error) {
+	if p.peek().kind == notToken {
+		p.next()
+		operand, err := p.parseNot(field)
+		if err != nil {
+			return nil, err
+		}
+		return notExpression{operand: operand}, nil
+	}
+	return p.parsePrimary(field)
+}
+
+func (p *queryParser) parsePrimary(field string) (expression, error) {
+	token := p.next()
+	switch token.kind {
+	case openToken:
+		grouped, err := p.parseOr(field)
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != closeToken {
+			return nil, &QueryError{Column: token.column, Message: "parenthesis is not closed"}
+		}
+		return grouped, nil
+	case fieldToken:
+		if field != "" {
+			return nil, &QueryError{Column: token.column, Message: fmt.Sprintf("field %s is used inside the field %s", token.text, field)}
+		}
+		switch p.peek().kind {
+		case wordToken, phraseToken, openToken:
+			return p.parsePrimary(token.text)
+		}
+		return nil, &QueryError{Column: p.peek().column, Message: fmt.Sprintf("expected the term after the field %s", token.text)}
+	case wordToken, phraseToken:
+		return p.newTermExpression(token, field)
+	case endToken:
+		return nil, &QueryError{Column: token.column, Message: "expected the term at the end of the query"}
+	case closeToken:
+		return nil, &QueryError{Column: token.column, Message: "expected the term before the closing parenthesis"}
+	default:
+		return nil, &QueryError{Column: token.column, Message: fmt.Sprintf("expected the term before %s", token.text)}
+	}
+}
+
+func (p *queryParser) newTermExpression(token queryToken, field string) (expression, error) {
+	text := strings.ToLower(token.text)
+	prefix := token.kind == wordToken && strings.HasSuffix(text, "*")
+	text = strings.TrimSuffix(text, "*")
+	term := termExpression{field: field, prefix: prefix, text: strings.TrimSpace(text)}
+	sequence := newSequence(text)
+	if len(sequence) == 0 || (field == SourceField && term.text == "") {
+		return nil, &QueryError{Column: token.column, Message: fmt.Sprintf("term %q has no letters or digits", token.text)}
+	}
+	term.alternatives = [][]queryWord{sequence}
+	if token.kind == wordToken && !prefix && len(sequence) == 1 {
+		for _, synonym := range p.synonyms.Expand(text)[1:] {
+			if synonymSequence := newSequence(synonym); len(synonymSequence) > 0 {
+				term.alternatives = append(term.alternatives, synonymSequence)
+			}
+		}
+	}
+	return term, nil
+}
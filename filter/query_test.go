@@ -0,0 +1,116 @@
+package filter
+
+import (
+	"errors"
+	"news-aggregator/analysis"
+	"news-aggregator/entity/news"
+	"reflect"
+	"testing"
+)
+
+func TestByQuery_Filter(t *testing.T) {
+	articles := []news.News{
+		{Title: "Air alert in Kyiv", Description: "The missiles were launched", SourceName: "pravda"},
+		{Title: "Elections in the US", Description: "Ukraine is discussed by the candidates", SourceName: "bbc"},
+		{Title: "Ukrainian drones", Description: "The alert in the air was announced", SourceName: "nbc"},
+		{Title: "Football", Description: "Results of the match", SourceName: "usatoday"},
+	}
+	tests := []struct {
+		name  string
+		query string
+		want  []int
+	}{
+		{name: "Stemmed term", query: "launch", want: []int{0}},
+		{name: "Implicit AND", query: "alert kyiv", want: []int{0}},
+		{name: "AND", query: "alert AND missiles", want: []int{0}},
+		{name: "OR", query: "football OR elections", want: []int{1, 3}},
+		{name: "NOT", query: "alert NOT kyiv", want: []int{2}},
+		{name: "Precedence of AND over OR", query: "football OR alert AND kyiv", want: []int{0, 3}},
+		{name: "Parentheses", query: "(football OR alert) AND NOT kyiv", want: []int{2, 3}},
+		{name: "Phrase", query: `"air alert"`, want: []int{0}},
+		{name: "Phrase of the stemmed words", query: `"alerts in kyiv"`, want: []int{0}},
+		{name: "Field", query: "title:ukraine", want: nil},
+		{name: "Description field", query: "description:ukraine", want: []int{1}},
+		{name: "Field of the phrase", query: `description:"the air"`, want: []int{2}},
+		{name: "Field of the group", query: "title:(drones OR football)", want: []int{2, 3}},
+		{name: "Source field", query: "source:BBC OR source:nb*", want: []int{1, 2}},
+		{name: "Prefix wildcard", query: "ukr*", want: []int{1, 2}},
+		{name: "Prefix wildcard in the phrase field", query: "title:ukr*", want: []int{2}},
+		{name: "Every news is returned once", query: "alert OR air OR kyiv", want: []int{0, 2}},
+		{name: "Term with punctuation is the phrase", query: "air-alert", want: []int{0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queryFilter, err := ParseQuery(tt.query, nil)
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+			var want []news.News
+			for _, index := range tt.want {
+				want = append(want, articles[index])
+			}
+			if got := queryFilter.Filter(articles); !reflect.DeepEqual(got, want) {
+				t.Errorf("Actual result: = %v Expexted: %v", got, want)
+			}
+		})
+	}
+}
+
+func TestByQuery_FilterSynonyms(t *testing.T) {
+	articles := []news.News{
+		{Title: "Air alert in Kiev"},
+		{Title: "Повітряна тривога у Києві", Language: "uk"},
+		{Title: "Air alert in Lviv"},
+	}
+	synonyms := analysis.NewSynonyms([][]string{{"kyiv", "kiev", "києві"}})
+
+	queryFilter, err := ParseQuery("alert kyiv OR тривога kyiv", synonyms)
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+	if got := queryFilter.Filter(articles); !reflect.DeepEqual(got, articles[:2]) {
+		t.Errorf("Actual result: = %v Expexted: %v", got, articles[:2])
+	}
+
+	queryFilter, err = ParseQuery("alert kyiv", nil)
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+	if got := queryFilter.Filter(articles); got != nil {
+		t.Errorf("Actual result: = %v Expexted: nil", got)
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantColumn int
+	}{
+		{name: "Empty query", query: "  ", wantColumn: 1},
+		{name: "Unclosed phrase", query: `title:"air alert`, wantColumn: 7},
+		{name: "Unclosed parenthesis", query: "kyiv AND (air OR alert", wantColumn: 10},
+		{name: "Unexpected closing parenthesis", query: "kyiv) alert", wantColumn: 5},
+		{name: "Empty parentheses", query: "kyiv ()", wantColumn: 7},
+		{name: "Operator without the term", query: "kyiv AND", wantColumn: 9},
+		{name: "Two operators", query: "kyiv OR AND alert", wantColumn: 9},
+		{name: "Unknown field", query: "kyiv author:smith", wantColumn: 6},
+		{name: "Field without the term", query: "title: AND kyiv", wantColumn: 8},
+		{name: "Nested field", query: "title:(source:bbc)", wantColumn: 8},
+		{name: "Wildcard inside the term", query: "alert uk*ne", wantColumn: 9},
+		{name: "Term without letters", query: "kyiv *", wantColumn: 6},
+		{name: "Column counts characters", query: "Київ AND", wantColumn: 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseQuery(tt.query, nil)
+			var queryError *QueryError
+			if !errors.As(err, &queryError) {
+				t.Fatalf("ParseQuery() error = %v, want QueryError", err)
+			}
+			if queryError.Column != tt.wantColumn {
+				t.Errorf("ParseQuery() column = %d, want %d: %v", queryError.Column, tt.wantColumn, err)
+			}
+		})
+	}
+}
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
 	"github.com/sirupsen/logrus"
+	"news-aggregator/analysis"
 	"news-aggregator/constant"
 	"news-aggregator/entity/source"
+	"news-aggregator/search"
 	"news-aggregator/storage"
 	newsStorage "news-aggregator/storage/news"
 	sourceStorage "news-aggregator/storage/source"
 	"news-updater/updater"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
@@ -26,9 +32,20 @@ func main() {
 		logrus.Fatal(sourceStorageErr)
 	}
 
-	resourcesStorage := storage.NewStorage(newsJsonStorage, sourceJsonStorage)
+	synonyms, err := analysis.LoadSynonyms(constant.PathToSynonyms)
+	if err != nil {
+		logrus.Error("Failed to load synonyms: ", err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	searchIndex, err := search.Open(ctx, constant.PathToSearchIndex, storage.NewStorage(newsJsonStorage, sourceJsonStorage), synonyms)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	resourcesStorage := search.NewIndexedStorage(storage.NewStorage(newsJsonStorage, sourceJsonStorage), searchIndex)
 
 	service := updater.Service{Storage: resourcesStorage}
-	service.UpdateNews()
+	service.UpdateNews(ctx)
 
 }
@@ -1,57 +1,87 @@
 package updater
 
 import (
+	"context"
 	"github.com/sirupsen/logrus"
+	"news-aggregator/collector"
+	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
+	"news-aggregator/fetcher"
 	"news-aggregator/storage"
 	"news-aggregator/web/feed"
 	"sync"
 )
 
 // Service represents the service for updating news.
+// The remote sources are fetched by the Fetcher, the default one is used if it is not set.
 type Service struct {
 	Storage storage.Storage
+	Fetcher *fetcher.Fetcher
 }
 
-// UpdateNews updates news for all sources.
-func (service Service) UpdateNews() {
+// UpdateNews updates news for all sources. The downloading of the feeds
+// and the reading and saving of the news are stopped when the context is done.
+func (service Service) UpdateNews(ctx context.Context) {
 	logrus.Info("Starting update of news")
-	sources, err := service.Storage.GetSources()
+	sources, err := service.Storage.GetSources(ctx)
 	if err != nil {
 		logrus.Error("Failed to retrieve sources: ", err)
 		return
 	}
 
+	sourceFetcher := service.Fetcher
+	if sourceFetcher == nil {
+		sourceFetcher = fetcher.New(fetcher.DefaultConfig())
+	}
+	parsers := collector.GetDefaultParsers()
+
 	var wg sync.WaitGroup
+	mirroredSources := make(chan source.Source, len(sources))
 	for _, src := range sources {
 		wg.Add(1)
 		go func(src source.Source) {
 			defer wg.Done()
 			if src.SourceType == source.STORAGE {
-				err := updateSourceNews(src, service.Storage)
+				err := updateSourceNews(ctx, src, service.Storage)
 				if err != nil {
 					logrus.Error("Failed to update news for source: ", src.Name)
 				}
+			} else if src.URL != "" {
+				mirroredSource, updated, err := parsers.MirrorSource(ctx, sourceFetcher, src)
+				if err != nil {
+					logrus.Error("Failed to fetch remote source: ", src.Name, " ", err)
+				} else if updated {
+					mirroredSources <- mirroredSource
+				}
 			}
 		}(src)
 	}
 	wg.Wait()
+	close(mirroredSources)
+
+	for mirroredSource := range mirroredSources {
+		if err := service.Storage.UpdateSource(ctx, mirroredSource, string(mirroredSource.Name)); err != nil {
+			logrus.Error("Failed to save validators of source: ", mirroredSource.Name)
+		}
+	}
 	logrus.Info("Update of news completed")
 }
 
 // updateSourceNews updates the news of the input source
-func updateSourceNews(inputSource source.Source, storage storage.Storage) error {
-	rssURL, err := feed.GetRssFeedLink(string(inputSource.Link))
+func updateSourceNews(ctx context.Context, inputSource source.Source, storage storage.Storage) error {
+	currentNews, report, err := feed.ParseSiteNews(ctx, string(inputSource.Link), string(inputSource.Name))
 	if err != nil {
 		return err
 	}
+	report.Log()
 
-	currentNews, err := feed.ParseRssFeed(rssURL, string(inputSource.Name))
+	existingNews, err := storage.GetNews(ctx, string(inputSource.PathToFile))
 	if err != nil {
 		return err
 	}
 
-	_, err = storage.SaveNews(inputSource, currentNews)
+	mergedNews, _ := news.MergeNews(existingNews, currentNews)
+	_, err = storage.SaveNews(ctx, inputSource, mergedNews)
 	if err != nil {
 		return err
 	}
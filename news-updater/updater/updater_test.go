@@ -1,14 +1,19 @@
 package updater
 
 import (
+	"context"
 	"errors"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
+	"net/http"
+	"net/http/httptest"
 	"news-aggregator/entity/source"
 	"news-aggregator/storage/mock_aggregator"
+	"os"
+	"path/filepath"
 )
 
 var _ = Describe("News Updater", func() {
@@ -31,8 +36,8 @@ var _ = Describe("News Updater", func() {
 	})
 	Context("Negative cases for UpdateNews method", func() {
 		It("UpdateNews should returns and log error when GetSources return error", func() {
-			Storage.EXPECT().GetSources().Return(nil, errors.New("storage errors"))
-			service.UpdateNews()
+			Storage.EXPECT().GetSources(gomock.Any()).Return(nil, errors.New("storage errors"))
+			service.UpdateNews(context.Background())
 
 			Expect(logHook.LastEntry().Level).To(Equal(logrus.ErrorLevel))
 			Expect(logHook.LastEntry().Message).To(ContainSubstring("Failed to retrieve sources"))
@@ -47,9 +52,9 @@ var _ = Describe("News Updater", func() {
 				},
 			}
 
-			Storage.EXPECT().GetSources().Return(testSources, nil)
+			Storage.EXPECT().GetSources(gomock.Any()).Return(testSources, nil)
 
-			service.UpdateNews()
+			service.UpdateNews(context.Background())
 
 			entries := logHook.AllEntries()
 
@@ -64,6 +69,38 @@ var _ = Describe("News Updater", func() {
 			Expect(found).To(BeTrue(), "Expected log entry with error message not found")
 		})
 	})
+	Context("Remote sources", func() {
+		It("UpdateNews should mirror the remote source and skip it when it is not modified", func() {
+			articles, err := os.ReadFile("../../mnt/resources/testdata/json_articles.json")
+			Expect(err).NotTo(HaveOccurred())
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("If-None-Match") == `"v1"` {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set("ETag", `"v1"`)
+				_, _ = w.Write(articles)
+			}))
+			defer server.Close()
+
+			path := filepath.Join(GinkgoT().TempDir(), "remote.json")
+			remoteSource := source.Source{Name: "remote", PathToFile: source.PathToFile(path), SourceType: source.JSON, URL: source.Link(server.URL)}
+			mirroredSource := remoteSource
+			mirroredSource.ETag = `"v1"`
+
+			Storage.EXPECT().GetSources(gomock.Any()).Return([]source.Source{remoteSource}, nil)
+			Storage.EXPECT().UpdateSource(gomock.Any(), mirroredSource, "remote").Return(nil)
+			service.UpdateNews(context.Background())
+
+			Expect(path).To(BeAnExistingFile())
+			content, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("Test News 1"))
+
+			Storage.EXPECT().GetSources(gomock.Any()).Return([]source.Source{mirroredSource}, nil)
+			service.UpdateNews(context.Background())
+		})
+	})
 	Context("Negative cases for updateSourceNews method", func() {
 		It("updateSourceNews should returns error when GetRssFeedLink return err", func() {
 			testSource := source.Source{
@@ -71,7 +108,7 @@ var _ = Describe("News Updater", func() {
 				Link:       "",
 				SourceType: source.STORAGE,
 			}
-			err := updateSourceNews(testSource, nil)
+			err := updateSourceNews(context.Background(), testSource, nil)
 			Expect(err).To(HaveOccurred())
 		})
 
@@ -83,9 +120,10 @@ var _ = Describe("News Updater", func() {
 				SourceType: source.STORAGE,
 			}
 
-			Storage.EXPECT().SaveNews(gomock.Any(), gomock.Any()).Return(source.Source{Name: "pravda"}, errors.New("storage errors"))
+			Storage.EXPECT().GetNews(gomock.Any(), gomock.Any()).Return(nil, nil)
+			Storage.EXPECT().SaveNews(gomock.Any(), gomock.Any(), gomock.Any()).Return(source.Source{Name: "pravda"}, errors.New("storage errors"))
 
-			err := updateSourceNews(testSource, Storage)
+			err := updateSourceNews(context.Background(), testSource, Storage)
 			Expect(err).To(HaveOccurred())
 		})
 	})
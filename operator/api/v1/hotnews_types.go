@@ -9,12 +9,20 @@ type HotNewsSpec struct {
 	// Keywords specifies the list of keywords used to filter news articles
 	Keywords []string `json:"keywords,omitempty"`
 
-	// DateStart defines the start date for collecting news, in the format YYYY-MM-DD
+	// DateStart defines the inclusive start of the range for collecting news: the date in the format YYYY-MM-DD,
+	// the RFC3339 timestamp or the duration before now like 7d. The range is open if it is empty
 	DateStart string `json:"dateStart,omitempty"`
 
-	// DateEnd defines the end date for collecting news, in the format YYYY-MM-DD
+	// DateEnd defines the inclusive end of the range for collecting news: the date in the format YYYY-MM-DD,
+	// the RFC3339 timestamp or the duration before now like 1h. The range is open if it is empty
 	DateEnd string `json:"dateEnd,omitempty"`
 
+	// Last defines the window before now for collecting news, like 24h or 7d. It cannot be combined with DateStart
+	Last string `json:"last,omitempty"`
+
+	// Timezone defines the IANA time zone of the dates without the time zone, like Europe/Kyiv
+	Timezone string `json:"timezone,omitempty"`
+
 	// FeedsName lists the names of the news sources to collect articles from
 	FeedsName []string `json:"feedsName,omitempty"`
 
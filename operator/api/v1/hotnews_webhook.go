@@ -10,6 +10,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -132,27 +134,93 @@ func (r *HotNews) validateFeeds() error {
 	return nil
 }
 
-// validateDate checks that the type is correct and that either two dates or no dates have been transmitted.
+// validateDate checks that the dates are the dates, the RFC3339 timestamps or the durations,
+// that the window is not combined with the start date and that the start is not after the end.
 func (r *HotNews) validateDate() error {
-
-	if r.Spec.DateStart != "" || r.Spec.DateEnd != "" {
-		if r.Spec.DateStart == "" || r.Spec.DateEnd == "" {
-			return fmt.Errorf("both DateStart and DateEnd must be provided if one is specified")
+	location := time.UTC
+	if r.Spec.Timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(r.Spec.Timezone); err != nil {
+			return fmt.Errorf("invalid Timezone: %v", err)
 		}
+	}
+	now := time.Now().In(location)
 
-		dateStart, err := time.Parse("2006-01-02", r.Spec.DateStart)
-		if err != nil {
-			return fmt.Errorf("invalid DateStart format: must be yyyy-mm-dd")
+	var dateStart, dateEnd time.Time
+	if r.Spec.Last != "" {
+		if r.Spec.DateStart != "" {
+			return fmt.Errorf("Last and DateStart cannot be combined")
 		}
-		dateEnd, err := time.Parse("2006-01-02", r.Spec.DateEnd)
+		window, err := parseDuration(r.Spec.Last)
 		if err != nil {
-			return fmt.Errorf("invalid DateEnd format: must be yyyy-mm-dd")
+			return fmt.Errorf("invalid Last format: must be duration like 24h or 7d")
 		}
-
-		if !dateStart.Before(dateEnd) {
-			return fmt.Errorf("DateStart must be before DateEnd")
+		dateStart = now.Add(-window)
+	}
+	if r.Spec.DateStart != "" {
+		var err error
+		if dateStart, err = parseDateBound(r.Spec.DateStart, now, false); err != nil {
+			return fmt.Errorf("invalid DateStart format: must be yyyy-mm-dd, RFC3339 timestamp or duration like 7d")
+		}
+	}
+	if r.Spec.DateEnd != "" {
+		var err error
+		if dateEnd, err = parseDateBound(r.Spec.DateEnd, now, true); err != nil {
+			return fmt.Errorf("invalid DateEnd format: must be yyyy-mm-dd, RFC3339 timestamp or duration like 1h")
 		}
 	}
 
+	if !dateStart.IsZero() && !dateEnd.IsZero() && dateStart.After(dateEnd) {
+		return fmt.Errorf("DateStart must not be after DateEnd")
+	}
+
 	return nil
 }
+
+// parseDateBound parses the date, the RFC3339 timestamp or the duration before now. The date is
+// its first moment, or its last moment if the bound is the end of the range, in the location of now.
+// The operator is the separate module, so parseDateBound and parseDuration are the copies of
+// ParseDateBound and parseDuration of the filter package of the aggregator and must accept the same
+// bounds, the cases of mnt/resources/testdata/date_bounds.json are checked against both copies.
+func parseDateBound(value string, now time.Time, end bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if date, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		if end {
+			return date.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+		}
+		return date, nil
+	}
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return timestamp, nil
+	}
+	duration, err := parseDuration(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return now.Add(-duration), nil
+}
+
+// parseDuration parses the positive duration like 90m, 24h, 7d or 2w.
+func parseDuration(value string) (time.Duration, error) {
+	var duration time.Duration
+	switch unit := strings.TrimLeft(value, "0123456789"); unit {
+	case "d", "w":
+		count, err := strconv.Atoi(strings.TrimSuffix(value, unit))
+		if err != nil {
+			return 0, err
+		}
+		duration = time.Duration(count) * 24 * time.Hour
+		if unit == "w" {
+			duration *= 7
+		}
+	default:
+		var err error
+		if duration, err = time.ParseDuration(value); err != nil {
+			return 0, err
+		}
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("duration must be positive")
+	}
+	return duration, nil
+}
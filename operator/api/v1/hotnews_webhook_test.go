@@ -2,11 +2,14 @@ package v1
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/runtime"
+	"os"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"testing"
+	"time"
 )
 
 func TestValidateCreate(t *testing.T) {
@@ -106,6 +109,52 @@ func TestValidateHotNews(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "open date range with timestamp",
+			hotNews: &HotNews{
+				Spec: HotNewsSpec{
+					DateStart: "2023-01-01T10:00:00+02:00",
+					Keywords:  []string{"keyword1"},
+					FeedsName: []string{"feed1"},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "last window in time zone",
+			hotNews: &HotNews{
+				Spec: HotNewsSpec{
+					Last:      "24h",
+					Timezone:  "UTC",
+					Keywords:  []string{"keyword1"},
+					FeedsName: []string{"feed1"},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "last window with start date",
+			hotNews: &HotNews{
+				Spec: HotNewsSpec{
+					DateStart: "7d",
+					Last:      "24h",
+					Keywords:  []string{"keyword1"},
+					FeedsName: []string{"feed1"},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid date format",
+			hotNews: &HotNews{
+				Spec: HotNewsSpec{
+					DateEnd:   "01/02/2023",
+					Keywords:  []string{"keyword1"},
+					FeedsName: []string{"feed1"},
+				},
+			},
+			expectErr: true,
+		},
 		{
 			name: "invalid date range",
 			hotNews: &HotNews{
@@ -209,3 +258,34 @@ func TestValidateFeeds(t *testing.T) {
 		})
 	}
 }
+
+// TestParseDateBound runs the cases which are shared with ParseDateBound of the filter package
+// of the aggregator, so the webhook accepts the same bounds as the aggregator.
+func TestParseDateBound(t *testing.T) {
+	content, err := os.ReadFile("../../../mnt/resources/testdata/date_bounds.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cases struct {
+		Now   time.Time `json:"now"`
+		Cases []struct {
+			Name    string    `json:"name"`
+			Value   string    `json:"value"`
+			End     bool      `json:"end"`
+			Want    time.Time `json:"want"`
+			WantErr bool      `json:"wantErr"`
+		} `json:"cases"`
+	}
+	if err := json.Unmarshal(content, &cases); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range cases.Cases {
+		t.Run(tt.Name, func(t *testing.T) {
+			got, err := parseDateBound(tt.Value, cases.Now, tt.End)
+			if (err != nil) != tt.WantErr {
+				t.Fatalf("parseDateBound() error = %v, wantErr %v", err, tt.WantErr)
+			}
+			assert.True(t, got.Equal(tt.Want), "parseDateBound() = %v, want %v", got, tt.Want)
+		})
+	}
+}
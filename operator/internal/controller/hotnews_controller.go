@@ -210,9 +210,17 @@ func (r *HotNewsReconciler) createUrl(hotNews aggregatorv1.HotNews, feedNames []
 		params.Add("keywords", strings.Join(hotNews.Spec.Keywords, ","))
 	}
 
-	if hotNews.Spec.DateStart != "" && hotNews.Spec.DateEnd != "" {
-		params.Add("startDate", hotNews.Spec.DateStart)
-		params.Add("endDate", hotNews.Spec.DateEnd)
+	if hotNews.Spec.DateStart != "" {
+		params.Add("since", hotNews.Spec.DateStart)
+	}
+	if hotNews.Spec.DateEnd != "" {
+		params.Add("until", hotNews.Spec.DateEnd)
+	}
+	if hotNews.Spec.Last != "" {
+		params.Add("last", hotNews.Spec.Last)
+	}
+	if hotNews.Spec.Timezone != "" {
+		params.Add("tz", hotNews.Spec.Timezone)
 	}
 
 	return baseUrl + "?" + params.Encode(), nil
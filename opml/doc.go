@@ -0,0 +1,4 @@
+// Package opml converts the sources of the aggregator to the OPML 2.0 subscription lists and back.
+// The remote sources are exported as the feed outlines with the xmlUrl attribute, the STORAGE
+// sources are exported with the htmlUrl of the site which feed is discovered during the import.
+package opml
@@ -0,0 +1,160 @@
+package opml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"news-aggregator/entity/source"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Version is the version of the OPML format written by the aggregator.
+const Version = "2.0"
+
+// Status describes the result of the import of the single outline.
+type Status string
+
+const (
+	Created Status = "created"
+	Skipped Status = "skipped"
+	Failed  Status = "failed"
+)
+
+// Document is the OPML document with the list of the subscriptions.
+type Document struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    Head     `xml:"head"`
+	Body    Body     `xml:"body"`
+}
+
+// Head contains the metadata of the document.
+type Head struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+// Body contains the outlines of the document.
+type Body struct {
+	Outlines []Outline `xml:"outline"`
+}
+
+// Outline is the single subscription or the category which groups the nested outlines.
+type Outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr,omitempty"`
+	Type     string    `xml:"type,attr,omitempty"`
+	XMLURL   string    `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string    `xml:"htmlUrl,attr,omitempty"`
+	Outlines []Outline `xml:"outline"`
+}
+
+// Result is the result of the import of the outline.
+type Result struct {
+	Name   source.Name `json:"name"`
+	URL    string      `json:"url"`
+	Status Status      `json:"status"`
+	Error  string      `json:"error,omitempty"`
+}
+
+var notAllowedNameCharacters = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// FromSources builds the document of the sources. The sources without the URL and the link
+// are read from the local files only, so they are not exported.
+func FromSources(sources []source.Source, created time.Time) Document {
+	document := Document{
+		Version: Version,
+		Head: Head{
+			Title:       "news-aggregator sources",
+			DateCreated: created.UTC().Format(time.RFC1123Z),
+		},
+	}
+	for _, currentSource := range sources {
+		outline := Outline{Text: string(currentSource.Name), Title: string(currentSource.Name)}
+		switch {
+		case currentSource.URL != "":
+			outline.Type = strings.ToLower(string(currentSource.SourceType))
+			outline.XMLURL = string(currentSource.URL)
+			outline.HTMLURL = string(currentSource.Link)
+		case currentSource.Link != "":
+			outline.HTMLURL = string(currentSource.Link)
+		default:
+			continue
+		}
+		document.Body.Outlines = append(document.Body.Outlines, outline)
+	}
+	return document
+}
+
+// Encode writes the document with the XML declaration to the writer.
+func (document Document) Encode(writer io.Writer) error {
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("failed to encode OPML document: %w", err)
+	}
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
+
+// Decode reads the document from the reader.
+func Decode(reader io.Reader) (Document, error) {
+	var document Document
+	if err := xml.NewDecoder(reader).Decode(&document); err != nil {
+		return Document{}, fmt.Errorf("failed to decode OPML document: %w", err)
+	}
+	return document, nil
+}
+
+// Subscriptions returns the outlines with the links of the feeds or the sites.
+// The outlines of the categories are flattened.
+func (document Document) Subscriptions() []Outline {
+	return subscriptions(document.Body.Outlines)
+}
+
+func subscriptions(outlines []Outline) []Outline {
+	var found []Outline
+	for _, outline := range outlines {
+		if outline.XMLURL != "" || outline.HTMLURL != "" {
+			found = append(found, outline)
+		}
+		found = append(found, subscriptions(outline.Outlines)...)
+	}
+	return found
+}
+
+// SourceName returns the name of the source for the outline: the lower-cased text or title
+// with the spaces replaced by hyphens. It is empty if the outline does not have the text.
+func (outline Outline) SourceName() source.Name {
+	name := strings.TrimSpace(outline.Text)
+	if name == "" {
+		name = strings.TrimSpace(outline.Title)
+	}
+	name = strings.Join(strings.Fields(strings.ToLower(name)), "-")
+	return source.Name(strings.Trim(notAllowedNameCharacters.ReplaceAllString(name, ""), "-"))
+}
+
+// SourceType returns the type of the source for the outline. The outline without the feed link
+// is the site which feed must be discovered, so its source has the STORAGE type.
+func (outline Outline) SourceType() source.Type {
+	if outline.XMLURL == "" {
+		return source.STORAGE
+	}
+	if outline.Type == "" {
+		return source.RSS
+	}
+	return source.Type(strings.ToUpper(outline.Type))
+}
+
+// URL returns the link of the feed or the site of the outline.
+func (outline Outline) URL() string {
+	if outline.XMLURL != "" {
+		return outline.XMLURL
+	}
+	return outline.HTMLURL
+}
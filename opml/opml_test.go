@@ -0,0 +1,61 @@
+package opml
+
+import (
+	"bytes"
+	"news-aggregator/entity/source"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromSources(t *testing.T) {
+	sources := []source.Source{
+		{Name: "bbc", PathToFile: "mnt/resources/bbc.xml", SourceType: source.RSS},
+		{Name: "pravda", PathToFile: "mnt/resources/pravda/pravda.json", SourceType: source.STORAGE, Link: "https://www.pravda.com.ua/"},
+		{Name: "remote", SourceType: source.JSON, URL: "https://example.com/news.json"},
+	}
+
+	var output bytes.Buffer
+	require.NoError(t, FromSources(sources, time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)).Encode(&output))
+
+	document, err := Decode(&output)
+	require.NoError(t, err)
+	assert.Equal(t, Version, document.Version)
+	assert.Equal(t, "Sat, 01 Jun 2024 00:00:00 +0000", document.Head.DateCreated)
+	assert.Equal(t, []Outline{
+		{Text: "pravda", Title: "pravda", HTMLURL: "https://www.pravda.com.ua/"},
+		{Text: "remote", Title: "remote", Type: "json", XMLURL: "https://example.com/news.json"},
+	}, document.Subscriptions(), "the local sources must not be exported")
+}
+
+func TestDocument_Subscriptions(t *testing.T) {
+	document, err := Decode(strings.NewReader(`<?xml version="1.0"?>
+<opml version="2.0">
+  <head><title>Feeds</title></head>
+  <body>
+    <outline text="World">
+      <outline text="BBC World News" type="rss" xmlUrl="https://feeds.bbci.co.uk/news/world/rss.xml"/>
+      <outline text="Kyiv Independent" htmlUrl="https://kyivindependent.com/"/>
+    </outline>
+    <outline text="Without links"/>
+  </body>
+</opml>`))
+	require.NoError(t, err)
+
+	subscriptions := document.Subscriptions()
+	require.Len(t, subscriptions, 2)
+
+	assert.Equal(t, source.Name("bbc-world-news"), subscriptions[0].SourceName())
+	assert.Equal(t, source.RSS, subscriptions[0].SourceType())
+	assert.Equal(t, "https://feeds.bbci.co.uk/news/world/rss.xml", subscriptions[0].URL())
+
+	assert.Equal(t, source.Name("kyiv-independent"), subscriptions[1].SourceName())
+	assert.Equal(t, source.STORAGE, subscriptions[1].SourceType())
+	assert.Equal(t, "https://kyivindependent.com/", subscriptions[1].URL())
+
+	_, err = Decode(strings.NewReader("not an OPML document"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,8 @@
+// Package pagination splits the aggregated news into the pages.
+//
+// The page is requested by the offset of its first news or by the cursor of the neighbouring page, and
+// has at most the limit of the news. The cursor is the opaque token with the ID of the last news of the
+// previous page or the first news of the next page, so the pages requested by the cursors do not shift
+// when the new news are inserted before them. The cursor of the news which is not found anymore falls
+// back to the offset of that news.
+package pagination
@@ -0,0 +1,159 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"news-aggregator/entity/news"
+	"strconv"
+)
+
+// ErrInvalidCursor is returned for the cursor which is not issued by Paginate.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Order sorts the news before the page is taken from them.
+type Order func(articles []news.News) ([]news.News, error)
+
+// Request is the requested page of the news. Limit is the maximum number of the news of the page,
+// all news are returned if it is 0. Offset is the number of the news before the page and Cursor is
+// the Next or the Previous cursor of the other page, the Offset is ignored if the Cursor is set.
+// Order sorts the news before the page is taken, the news are kept in their order if it is nil.
+type Request struct {
+	Limit  int
+	Offset int
+	Cursor string
+	Order  Order
+}
+
+// Page is the part of the news. Total is the number of all news, Offset is the number of the news
+// before the page, Next and Previous are the cursors of the neighbouring pages, they are empty if
+// there is no such page.
+type Page struct {
+	News     []news.News
+	Total    int
+	Offset   int
+	Limit    int
+	Next     string
+	Previous string
+}
+
+// cursor is the content of the token of the cursor: the news to start after or to end before
+// and its offset which is used when the news is not found.
+type cursor struct {
+	After  news.ID `json:"after,omitempty"`
+	Before news.ID `json:"before,omitempty"`
+	Offset int     `json:"offset"`
+}
+
+// ParseRequest returns the request of the page with the limit, the offset and the cursor,
+// the empty parameters are left zero.
+func ParseRequest(limit, offset, cursor string) (Request, error) {
+	var request Request
+	var err error
+	if limit != "" {
+		if request.Limit, err = strconv.Atoi(limit); err != nil || request.Limit <= 0 {
+			return request, errors.New("wrong limit parameter, must be positive number: " + limit)
+		}
+	}
+	if offset != "" {
+		if request.Offset, err = strconv.Atoi(offset); err != nil || request.Offset < 0 {
+			return request, errors.New("wrong offset parameter, must be number from 0: " + offset)
+		}
+	}
+	if cursor != "" {
+		if _, err = decodeCursor(cursor); err != nil {
+			return request, err
+		}
+		request.Cursor = cursor
+	}
+	return request, nil
+}
+
+// Paged reports whether the request asks for the part of the news.
+func (request Request) Paged() bool {
+	return request.Limit > 0 || request.Offset > 0 || request.Cursor != ""
+}
+
+// Paginate sorts the news by the Order of the request and returns the requested page of them.
+func Paginate(articles []news.News, request Request) (Page, error) {
+	if request.Order != nil {
+		var err error
+		if articles, err = request.Order(articles); err != nil {
+			return Page{}, err
+		}
+	}
+
+	total := len(articles)
+	start := min(request.Offset, total)
+	end := total
+	if request.Cursor != "" {
+		position, err := decodeCursor(request.Cursor)
+		if err != nil {
+			return Page{}, err
+		}
+		start, end = position.bounds(articles, request.Limit)
+	} else if request.Limit > 0 {
+		end = min(start+request.Limit, total)
+	}
+
+	page := Page{News: articles[start:end], Total: total, Offset: start, Limit: request.Limit}
+	if len(page.News) == 0 {
+		return page, nil
+	}
+	if end < total {
+		page.Next = encodeCursor(cursor{After: page.News[len(page.News)-1].WithID().ID, Offset: end})
+	}
+	if start > 0 {
+		page.Previous = encodeCursor(cursor{Before: page.News[0].WithID().ID, Offset: start})
+	}
+	return page, nil
+}
+
+// bounds returns the start and the end of the page of the news after or before the news of the cursor.
+func (position cursor) bounds(articles []news.News, limit int) (int, int) {
+	total := len(articles)
+	boundary := min(position.Offset, total)
+	if index := indexOf(articles, position.After+position.Before); index >= 0 {
+		boundary = index
+		if position.After != "" {
+			boundary++
+		}
+	}
+	if position.After != "" || position.Before == "" {
+		if limit <= 0 {
+			return boundary, total
+		}
+		return boundary, min(boundary+limit, total)
+	}
+	if limit <= 0 {
+		return 0, boundary
+	}
+	return max(boundary-limit, 0), boundary
+}
+
+func indexOf(articles []news.News, id news.ID) int {
+	if id == "" {
+		return -1
+	}
+	for i, article := range articles {
+		if article.WithID().ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func encodeCursor(position cursor) string {
+	content, _ := json.Marshal(position)
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+func decodeCursor(token string) (cursor, error) {
+	var position cursor
+	content, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || json.Unmarshal(content, &position) != nil || position.Offset < 0 ||
+		position.After != "" && position.Before != "" {
+		return cursor{}, ErrInvalidCursor
+	}
+	return position, nil
+}
@@ -0,0 +1,139 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"news-aggregator/entity/news"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testNews(titles ...string) []news.News {
+	articles := make([]news.News, len(titles))
+	for i, title := range titles {
+		articles[i] = news.News{Title: news.Title(title), Link: news.Link("https://example.com/" + title)}.WithID()
+	}
+	return articles
+}
+
+func titles(articles []news.News) []string {
+	var result []string
+	for _, article := range articles {
+		result = append(result, string(article.Title))
+	}
+	return result
+}
+
+func TestParseRequest(t *testing.T) {
+	validCursor := encodeCursor(cursor{After: "id", Offset: 2})
+	tests := []struct {
+		name                  string
+		limit, offset, cursor string
+		expected              Request
+		expectError           bool
+	}{
+		{name: "Empty parameters", expected: Request{}},
+		{name: "All parameters", limit: "10", offset: "20", cursor: validCursor, expected: Request{Limit: 10, Offset: 20, Cursor: validCursor}},
+		{name: "Zero limit", limit: "0", expectError: true},
+		{name: "Negative offset", offset: "-1", expectError: true},
+		{name: "Wrong cursor", cursor: "not a cursor", expectError: true},
+		{name: "Cursor of both directions", cursor: encodeCursor(cursor{After: "a", Before: "b"}), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := ParseRequest(tt.limit, tt.offset, tt.cursor)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, request)
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	articles := testNews("a", "b", "c", "d", "e")
+	tests := []struct {
+		name             string
+		request          Request
+		expected         []string
+		expectedOffset   int
+		expectNext       bool
+		expectPrevious   bool
+		expectedErrorMsg string
+	}{
+		{name: "All news without limit", request: Request{}, expected: []string{"a", "b", "c", "d", "e"}},
+		{name: "First page", request: Request{Limit: 2}, expected: []string{"a", "b"}, expectNext: true},
+		{name: "Middle page", request: Request{Limit: 2, Offset: 2}, expected: []string{"c", "d"}, expectedOffset: 2, expectNext: true, expectPrevious: true},
+		{name: "Last page", request: Request{Limit: 2, Offset: 4}, expected: []string{"e"}, expectedOffset: 4, expectPrevious: true},
+		{name: "Offset after the news", request: Request{Limit: 2, Offset: 10}, expectedOffset: 5},
+		{
+			name:     "Ordered news",
+			request:  Request{Limit: 2, Order: func(articles []news.News) ([]news.News, error) { return reversed(articles), nil }},
+			expected: []string{"e", "d"}, expectNext: true,
+		},
+		{
+			name:             "Order error",
+			request:          Request{Order: func([]news.News) ([]news.News, error) { return nil, errors.New("wrong sorting parameter") }},
+			expectedErrorMsg: "wrong sorting parameter",
+		},
+		{name: "Invalid cursor", request: Request{Cursor: "%%%"}, expectedErrorMsg: ErrInvalidCursor.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := Paginate(slices.Clone(articles), tt.request)
+			if tt.expectedErrorMsg != "" {
+				assert.EqualError(t, err, tt.expectedErrorMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, titles(page.News))
+			assert.Equal(t, len(articles), page.Total)
+			assert.Equal(t, tt.expectedOffset, page.Offset)
+			assert.Equal(t, tt.expectNext, page.Next != "", fmt.Sprintf("next cursor %q", page.Next))
+			assert.Equal(t, tt.expectPrevious, page.Previous != "", fmt.Sprintf("previous cursor %q", page.Previous))
+		})
+	}
+}
+
+func TestPaginate_Cursors(t *testing.T) {
+	articles := testNews("c", "d", "e", "f", "g")
+	first, err := Paginate(articles, Request{Limit: 2})
+	require.NoError(t, err)
+
+	second, err := Paginate(articles, Request{Limit: 2, Cursor: first.Next})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"e", "f"}, titles(second.News))
+
+	inserted := append(testNews("a", "b"), articles...)
+	stable, err := Paginate(inserted, Request{Limit: 2, Cursor: first.Next})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"e", "f"}, titles(stable.News), "the page must not shift when the news are inserted before it")
+	assert.Equal(t, 4, stable.Offset)
+	assert.Equal(t, 7, stable.Total)
+
+	previous, err := Paginate(inserted, Request{Limit: 2, Cursor: stable.Previous})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "d"}, titles(previous.News))
+
+	removed := slices.Delete(slices.Clone(articles), 1, 2)
+	fallback, err := Paginate(removed, Request{Limit: 2, Cursor: first.Next})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"f", "g"}, titles(fallback.News), "the cursor of the removed news must fall back to its offset")
+
+	all, err := Paginate(articles, Request{Cursor: first.Next})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"e", "f", "g"}, titles(all.News), "all news after the cursor must be returned without the limit")
+}
+
+func reversed(articles []news.News) []news.News {
+	result := slices.Clone(articles)
+	slices.Reverse(result)
+	return result
+}
@@ -0,0 +1,212 @@
+package charset
+
+import (
+	"bufio"
+	"bytes"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+	"io"
+	"mime"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// sniffLength is the length of the beginning of the document used for the detection.
+const sniffLength = 4096
+
+// The names of the encodings which are detected without the declaration.
+const (
+	UTF8        = "utf-8"
+	UTF16LE     = "utf-16le"
+	UTF16BE     = "utf-16be"
+	Windows1252 = "windows-1252"
+	Windows1251 = "windows-1251"
+	KOI8U       = "koi8-u"
+	undefined   = ""
+)
+
+var (
+	xmlDeclarationPattern = regexp.MustCompile(`^\s*<\?xml[^>]*\?>`)
+	xmlEncodingPattern    = regexp.MustCompile(`encoding\s*=\s*["']([A-Za-z0-9._:-]+)["']`)
+	metaCharsetPattern    = regexp.MustCompile(`(?i)<meta[^>]+charset\s*=\s*["']?\s*([A-Za-z0-9._:-]+)`)
+)
+
+// Reader is the reader of the document transcoded to UTF-8.
+type Reader struct {
+	reader io.Reader
+	// Encoding is the name of the detected encoding of the source document.
+	Encoding string
+}
+
+// Read reads the transcoded document.
+func (reader *Reader) Read(p []byte) (int, error) {
+	return reader.reader.Read(p)
+}
+
+// NewReader returns the reader of the document transcoded to UTF-8. The content type is the value
+// of the Content-Type header of the response, it is empty for the files. The reader which is
+// already returned by NewReader is returned as is, so the document is never decoded twice.
+func NewReader(reader io.Reader, contentType string) *Reader {
+	if decoded, ok := reader.(*Reader); ok {
+		return decoded
+	}
+
+	buffered := bufio.NewReaderSize(reader, sniffLength)
+	prefix, _ := buffered.Peek(sniffLength)
+
+	name, bomLength := detectBOM(prefix)
+	if name == undefined {
+		name = Detect(prefix, contentType)
+	}
+	if bomLength > 0 {
+		_, _ = buffered.Discard(bomLength)
+	}
+
+	decoded := io.Reader(buffered)
+	if documentEncoding := lookup(name); documentEncoding != nil && documentEncoding != encoding.Nop && name != UTF8 {
+		decoded = transform.NewReader(buffered, documentEncoding.NewDecoder())
+	}
+	return &Reader{reader: rewriteDeclaration(decoded), Encoding: name}
+}
+
+// Detect returns the name of the encoding of the document by the beginning of the document
+// and the value of the Content-Type header. The byte order mark is checked first, then the
+// charset of the header, the XML declaration and the <meta charset> of the HTML page.
+// The encoding is sniffed from the content if none of them is present.
+func Detect(prefix []byte, contentType string) string {
+	if name, _ := detectBOM(prefix); name != undefined {
+		return name
+	}
+	if _, parameters, err := mime.ParseMediaType(contentType); err == nil {
+		if name := normalize(parameters["charset"]); name != undefined {
+			return name
+		}
+	}
+	if declaration := xmlDeclarationPattern.Find(prefix); declaration != nil {
+		if match := xmlEncodingPattern.FindSubmatch(declaration); match != nil {
+			if name := normalize(string(match[1])); name != undefined {
+				return name
+			}
+		}
+	}
+	if match := metaCharsetPattern.FindSubmatch(prefix); match != nil {
+		if name := normalize(string(match[1])); name != undefined {
+			return name
+		}
+	}
+	return sniff(prefix)
+}
+
+// detectBOM returns the encoding of the byte order mark and its length.
+func detectBOM(prefix []byte) (string, int) {
+	switch {
+	case bytes.HasPrefix(prefix, []byte{0xEF, 0xBB, 0xBF}):
+		return UTF8, 3
+	case bytes.HasPrefix(prefix, []byte{0xFF, 0xFE}):
+		return UTF16LE, 2
+	case bytes.HasPrefix(prefix, []byte{0xFE, 0xFF}):
+		return UTF16BE, 2
+	}
+	return undefined, 0
+}
+
+// sniff guesses the encoding of the undeclared document. The valid UTF-8 is UTF-8. The Cyrillic
+// words are the runs of the bytes of the upper half of the code page, while the accented Latin
+// letters of windows-1252 are mostly single between the ASCII ones. In windows-1251 the lowercase
+// Cyrillic letters, which are the most of the text, are 0xE0-0xFF, and in KOI8-U they are 0xC0-0xDF.
+func sniff(prefix []byte) string {
+	if validUTF8(prefix) {
+		return UTF8
+	}
+	var upperHalf, runs, lower, upper int
+	for i, b := range prefix {
+		if b < 0x80 {
+			continue
+		}
+		upperHalf++
+		if i+1 < len(prefix) && prefix[i+1] >= 0x80 {
+			runs++
+		}
+		switch {
+		case b >= 0xE0:
+			lower++
+		case b >= 0xC0:
+			upper++
+		}
+	}
+	if runs*2 < upperHalf {
+		return Windows1252
+	}
+	if upper > lower {
+		return KOI8U
+	}
+	return Windows1251
+}
+
+// validUTF8 reports whether the beginning of the document is valid UTF-8.
+// The last character may be cut by the length of the beginning.
+func validUTF8(prefix []byte) bool {
+	for i := 0; i < utf8.UTFMax && len(prefix) > 0; i++ {
+		if utf8.Valid(prefix) {
+			return true
+		}
+		if len(prefix) < sniffLength {
+			return false
+		}
+		prefix = prefix[:len(prefix)-1]
+	}
+	return false
+}
+
+// normalize returns the canonical name of the encoding label or an empty string if it is unknown.
+func normalize(label string) string {
+	label = strings.ToLower(strings.TrimSpace(label))
+	switch label {
+	case "":
+		return undefined
+	case "utf-16", "utf-16le":
+		return UTF16LE
+	case "utf-16be":
+		return UTF16BE
+	}
+	documentEncoding, err := htmlindex.Get(label)
+	if err != nil {
+		return undefined
+	}
+	name, err := htmlindex.Name(documentEncoding)
+	if err != nil {
+		return undefined
+	}
+	return strings.ToLower(name)
+}
+
+// lookup returns the encoding by its canonical name.
+func lookup(name string) encoding.Encoding {
+	switch name {
+	case UTF16LE:
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+	case UTF16BE:
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)
+	}
+	documentEncoding, err := htmlindex.Get(name)
+	if err != nil {
+		return nil
+	}
+	return documentEncoding
+}
+
+// rewriteDeclaration replaces the encoding of the XML declaration of the transcoded document with UTF-8.
+func rewriteDeclaration(reader io.Reader) io.Reader {
+	buffered := bufio.NewReaderSize(reader, sniffLength)
+	prefix, _ := buffered.Peek(sniffLength)
+	declaration := xmlDeclarationPattern.Find(prefix)
+	if declaration == nil || !xmlEncodingPattern.Match(declaration) {
+		return buffered
+	}
+	rewritten := xmlEncodingPattern.ReplaceAll(declaration, []byte(`encoding="UTF-8"`))
+	_, _ = buffered.Discard(len(declaration))
+	return io.MultiReader(bytes.NewReader(rewritten), buffered)
+}
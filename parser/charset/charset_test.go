@@ -0,0 +1,118 @@
+package charset
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func encode(t *testing.T, text string, encoder interface {
+	String(string) (string, error)
+}) string {
+	encoded, err := encoder.String(text)
+	require.NoError(t, err)
+	return encoded
+}
+
+func TestNewReader(t *testing.T) {
+	const text = "Новини України: їжак, єнот і ґанок"
+	tests := []struct {
+		name         string
+		content      string
+		contentType  string
+		want         string
+		wantEncoding string
+	}{
+		{
+			name:         "UTF-8 without declaration",
+			content:      text,
+			want:         text,
+			wantEncoding: UTF8,
+		},
+		{
+			name:         "UTF-8 byte order mark is removed",
+			content:      "\xEF\xBB\xBF" + text,
+			want:         text,
+			wantEncoding: UTF8,
+		},
+		{
+			name:         "UTF-16 byte order mark",
+			content:      encode(t, text, unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder()),
+			want:         text,
+			wantEncoding: UTF16LE,
+		},
+		{
+			name:         "Charset of the Content-Type header",
+			content:      encode(t, text, charmap.Windows1251.NewEncoder()),
+			contentType:  "text/plain; charset=cp1251",
+			want:         text,
+			wantEncoding: Windows1251,
+		},
+		{
+			name:         "XML declaration is rewritten to UTF-8",
+			content:      encode(t, `<?xml version="1.0" encoding="KOI8-U"?><title>`+text+`</title>`, charmap.KOI8U.NewEncoder()),
+			want:         `<?xml version="1.0" encoding="UTF-8"?><title>` + text + `</title>`,
+			wantEncoding: KOI8U,
+		},
+		{
+			name:         "Meta charset of the HTML page",
+			content:      encode(t, `<html><head><meta http-equiv="Content-Type" content="text/html; charset=windows-1251"></head>`+text, charmap.Windows1251.NewEncoder()),
+			want:         `<html><head><meta http-equiv="Content-Type" content="text/html; charset=windows-1251"></head>` + text,
+			wantEncoding: Windows1251,
+		},
+		{
+			name:         "Sniffed windows-1251",
+			content:      encode(t, text, charmap.Windows1251.NewEncoder()),
+			want:         text,
+			wantEncoding: Windows1251,
+		},
+		{
+			name:         "Sniffed KOI8-U",
+			content:      encode(t, text, charmap.KOI8U.NewEncoder()),
+			want:         text,
+			wantEncoding: KOI8U,
+		},
+		{
+			name:         "Sniffed windows-1252",
+			content:      encode(t, "Café crème brûlée", charmap.Windows1252.NewEncoder()),
+			want:         "Café crème brûlée",
+			wantEncoding: Windows1252,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := NewReader(strings.NewReader(tt.content), tt.contentType)
+			got, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+			assert.Equal(t, tt.wantEncoding, reader.Encoding)
+		})
+	}
+}
+
+func TestNewReader_DecodedOnce(t *testing.T) {
+	content := encode(t, `<html><head><meta charset="koi8-u"></head>Новини</html>`, charmap.KOI8U.NewEncoder())
+	reader := NewReader(strings.NewReader(content), "")
+
+	assert.Same(t, reader, NewReader(reader, "text/html; charset=windows-1251"))
+	got, err := io.ReadAll(NewReader(reader, ""))
+	require.NoError(t, err)
+	assert.Equal(t, `<html><head><meta charset="koi8-u"></head>Новини</html>`, string(got))
+}
+
+func TestNewReader_Fixture(t *testing.T) {
+	file, err := os.Open("../../mnt/resources/testdata/windows1251_rss.xml")
+	require.NoError(t, err)
+	defer file.Close()
+
+	got, err := io.ReadAll(NewReader(file, ""))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(got), `<?xml version="1.0" encoding="UTF-8"?>`))
+	assert.Contains(t, string(got), "Їжак і єнот")
+}
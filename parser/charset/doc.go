@@ -0,0 +1,5 @@
+// Package charset detects the encoding of the documents read from the sources and transcodes them to UTF-8.
+// The encoding is taken from the byte order mark, the charset of the Content-Type header, the XML declaration
+// or the <meta charset> of the HTML page, and is sniffed from the content if none of them is present.
+// The XML declaration of the transcoded document is rewritten to UTF-8, so the XML parsers do not decode it again.
+package charset
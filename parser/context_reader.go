@@ -0,0 +1,25 @@
+package parser
+
+import (
+	"context"
+	"io"
+)
+
+// contextReader stops reading of the source as soon as the context is done.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+// NewContextReader returns the reader which returns the error of the context
+// instead of the data after the context is cancelled or its deadline is exceeded.
+func NewContextReader(ctx context.Context, reader io.Reader) io.Reader {
+	return &contextReader{ctx: ctx, reader: reader}
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.reader.Read(p)
+}
@@ -0,0 +1,271 @@
+package dates
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	// The time zone database is embedded for the containers which do not have it.
+	_ "time/tzdata"
+)
+
+// ErrEmpty is returned for the empty date.
+var ErrEmpty = errors.New("date is empty")
+
+// yearTolerance is how far in the future from the fetch time the date without the year may be,
+// the later dates are moved to the previous year.
+const yearTolerance = 48 * time.Hour
+
+// Options configure the normalization of the date.
+type Options struct {
+	// Layout is the layout of the source which is tried before the known layouts.
+	Layout string
+	// Reference is the fetch time. The missing years and the relative dates are inferred
+	// relative to it, the current time is used if it is zero.
+	Reference time.Time
+	// Location is the time zone of the dates without the offset, UTC is used if it is nil.
+	Location *time.Location
+}
+
+// Date is the normalized date of the news.
+type Date struct {
+	// Time is the date in UTC.
+	Time time.Time
+	// Inferred reports whether the year or the whole date was inferred from the fetch time.
+	Inferred bool
+}
+
+// layouts are the known layouts of the dates. The commas and the week days are removed from
+// the dates and the month names are shortened before the parsing, so the layouts do not have them.
+var layouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"20060102T150405Z0700",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05",
+	"2 Jan 2006 15:04 -0700",
+	"2 Jan 2006 15:04 MST",
+	"2 Jan 2006 15:04",
+	"2 Jan 06 15:04:05 -0700",
+	"2 Jan 06 15:04 -0700",
+	"2 Jan 06 15:04 MST",
+	"2 Jan 2006",
+	"2-Jan-06 15:04:05 MST",
+	"2-Jan-06 15:04:05 -0700",
+	"Jan 2 2006 3:04:05 PM",
+	"Jan 2 2006 3:04 PM",
+	"Jan 2 2006 15:04:05",
+	"Jan 2 2006 15:04",
+	"Jan 2 15:04:05 2006",
+	"Jan 2 15:04:05 MST 2006",
+	"Jan 2 2006",
+	"2.1.2006 15:04:05",
+	"2.1.2006 15:04",
+	"2.1.2006",
+	"Jan 2 3:04 PM",
+	"Jan 2 15:04",
+	"2 Jan 15:04",
+	"Jan 2",
+	"2 Jan",
+	"2.1 15:04",
+	"2.1",
+}
+
+// zoneOffsets are the offsets of the named time zones which are used by the news sites.
+var zoneOffsets = map[string]string{
+	"UT":   "+0000",
+	"UTC":  "+0000",
+	"GMT":  "+0000",
+	"Z":    "+0000",
+	"WET":  "+0000",
+	"BST":  "+0100",
+	"WEST": "+0100",
+	"CET":  "+0100",
+	"CEST": "+0200",
+	"EET":  "+0200",
+	"EEST": "+0300",
+	"MSK":  "+0300",
+	"IST":  "+0530",
+	"JST":  "+0900",
+	"AEST": "+1000",
+	"AEDT": "+1100",
+	"EST":  "-0500",
+	"EDT":  "-0400",
+	"CST":  "-0600",
+	"CDT":  "-0500",
+	"MST":  "-0700",
+	"MDT":  "-0600",
+	"PST":  "-0800",
+	"PDT":  "-0700",
+}
+
+// months maps the full English month names and the Ukrainian and Russian month names in the
+// nominative and the genitive case and their abbreviations to the short English names.
+var months = map[string]string{
+	"january": "Jan", "february": "Feb", "march": "Mar", "april": "Apr", "june": "Jun", "july": "Jul",
+	"august": "Aug", "september": "Sep", "sept": "Sep", "october": "Oct", "november": "Nov", "december": "Dec",
+	"січень": "Jan", "січня": "Jan", "січ": "Jan",
+	"лютий": "Feb", "лютого": "Feb", "лют": "Feb",
+	"березень": "Mar", "березня": "Mar", "бер": "Mar",
+	"квітень": "Apr", "квітня": "Apr", "квіт": "Apr", "кві": "Apr",
+	"травень": "May", "травня": "May", "трав": "May", "тра": "May",
+	"червень": "Jun", "червня": "Jun", "черв": "Jun", "чер": "Jun",
+	"липень": "Jul", "липня": "Jul", "лип": "Jul",
+	"серпень": "Aug", "серпня": "Aug", "серп": "Aug", "сер": "Aug",
+	"вересень": "Sep", "вересня": "Sep", "вер": "Sep",
+	"жовтень": "Oct", "жовтня": "Oct", "жовт": "Oct", "жов": "Oct",
+	"листопад": "Nov", "листопада": "Nov", "лист": "Nov", "лис": "Nov",
+	"грудень": "Dec", "грудня": "Dec", "груд": "Dec", "гру": "Dec",
+	"январь": "Jan", "января": "Jan", "янв": "Jan",
+	"февраль": "Feb", "февраля": "Feb", "фев": "Feb",
+	"март": "Mar", "марта": "Mar", "мар": "Mar",
+	"апрель": "Apr", "апреля": "Apr", "апр": "Apr",
+	"май": "May", "мая": "May",
+	"июнь": "Jun", "июня": "Jun", "июн": "Jun",
+	"июль": "Jul", "июля": "Jul", "июл": "Jul",
+	"август": "Aug", "августа": "Aug", "авг": "Aug",
+	"сентябрь": "Sep", "сентября": "Sep", "сен": "Sep", "сент": "Sep",
+	"октябрь": "Oct", "октября": "Oct", "окт": "Oct",
+	"ноябрь": "Nov", "ноября": "Nov", "ноя": "Nov", "нояб": "Nov",
+	"декабрь": "Dec", "декабря": "Dec", "дек": "Dec",
+}
+
+// skippedWords are the week days and the words around the date and the time which are removed before the parsing.
+var skippedWords = map[string]struct{}{
+	"mon": {}, "monday": {}, "tue": {}, "tues": {}, "tuesday": {}, "wed": {}, "wednesday": {},
+	"thu": {}, "thur": {}, "thurs": {}, "thursday": {}, "fri": {}, "friday": {},
+	"sat": {}, "saturday": {}, "sun": {}, "sunday": {},
+	"понеділок": {}, "вівторок": {}, "середа": {}, "четвер": {}, "пʼятниця": {}, "п'ятниця": {}, "субота": {}, "неділя": {},
+	"пн": {}, "вт": {}, "ср": {}, "чт": {}, "пт": {}, "сб": {}, "нд": {}, "вс": {},
+	"понедельник": {}, "вторник": {}, "среда": {}, "четверг": {}, "пятница": {}, "суббота": {}, "воскресенье": {},
+	"at": {}, "on": {}, "о": {}, "об": {}, "в": {},
+	"р": {}, "року": {}, "рік": {}, "г": {}, "года": {}, "год": {},
+	"published": {}, "updated": {},
+}
+
+var (
+	commentPattern = regexp.MustCompile(`\([^)]*\)`)
+	ordinalPattern = regexp.MustCompile(`\b(\d{1,2})(st|nd|rd|th)\b`)
+)
+
+// Normalize parses the date and returns it in UTC. The layout of the options is tried first,
+// then the relative expressions and the known layouts. The date without the year gets the year
+// of the fetch time, or the previous one if the date would be in the future otherwise.
+func Normalize(value string, options Options) (Date, error) {
+	reference := options.Reference
+	if reference.IsZero() {
+		reference = time.Now()
+	}
+	location := options.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Date{}, ErrEmpty
+	}
+	if options.Layout != "" {
+		if date, ok := parse(value, options.Layout, reference, location); ok {
+			return date, nil
+		}
+	}
+
+	if date, ok := parseRelative(value, reference); ok {
+		return Date{Time: date.UTC(), Inferred: true}, nil
+	}
+	cleaned := clean(value)
+	if cleaned == "" {
+		return Date{}, ErrEmpty
+	}
+
+	cleaned, location = extractZone(cleaned, location)
+	if options.Layout != "" {
+		if date, ok := parse(cleaned, options.Layout, reference, location); ok {
+			return date, nil
+		}
+	}
+	for _, layout := range layouts {
+		if date, ok := parse(cleaned, layout, reference, location); ok {
+			return date, nil
+		}
+	}
+	return Date{}, fmt.Errorf("unknown format of date %q", value)
+}
+
+// parse parses the date by the layout and infers the year if the layout does not have it.
+func parse(value, layout string, reference time.Time, location *time.Location) (Date, bool) {
+	parsed, err := time.ParseInLocation(layout, value, location)
+	if err != nil {
+		return Date{}, false
+	}
+	if parsed.Year() != 0 {
+		return Date{Time: parsed.UTC()}, true
+	}
+	return Date{Time: inferYear(parsed, reference).UTC(), Inferred: true}, true
+}
+
+// inferYear sets the year of the fetch time to the date. The previous year is used
+// if the date would be too far in the future relative to the fetch time.
+func inferYear(date, reference time.Time) time.Time {
+	inferred := time.Date(reference.In(date.Location()).Year(), date.Month(), date.Day(),
+		date.Hour(), date.Minute(), date.Second(), date.Nanosecond(), date.Location())
+	if inferred.After(reference.Add(yearTolerance)) {
+		inferred = inferred.AddDate(-1, 0, 0)
+	}
+	return inferred
+}
+
+// clean removes the comments, the commas, the ordinal suffixes, the week days and the filler
+// words from the date and replaces the month names with the short English ones.
+func clean(value string) string {
+	value = commentPattern.ReplaceAllString(value, " ")
+	value = strings.ReplaceAll(value, ",", " ")
+	value = ordinalPattern.ReplaceAllString(value, "$1")
+
+	var words []string
+	for _, word := range strings.Fields(value) {
+		key := strings.TrimSuffix(strings.ToLower(word), ".")
+		if _, skipped := skippedWords[key]; skipped {
+			continue
+		}
+		if month, exists := months[key]; exists {
+			word = month
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " ")
+}
+
+// extractZone replaces the named time zone at the end of the date with its offset.
+// The IANA name of the zone is removed from the date and returned as the location.
+func extractZone(value string, location *time.Location) (string, *time.Location) {
+	separator := strings.LastIndex(value, " ")
+	if separator < 0 {
+		return value, location
+	}
+	zone := value[separator+1:]
+	if offset, exists := zoneOffsets[strings.ToUpper(zone)]; exists {
+		return value[:separator+1] + offset, location
+	}
+	if strings.Contains(zone, "/") {
+		if zoneLocation, err := time.LoadLocation(zone); err == nil {
+			return value[:separator], zoneLocation
+		}
+	}
+	return value, location
+}
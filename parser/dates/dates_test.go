@@ -0,0 +1,154 @@
+package dates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	reference := time.Date(2024, time.June, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		value        string
+		options      Options
+		want         time.Time
+		wantInferred bool
+	}{
+		{
+			name:  "RFC 1123 with the numeric zone",
+			value: "Mon, 03 Jun 2024 09:15:00 +0300",
+			want:  time.Date(2024, time.June, 3, 6, 15, 0, 0, time.UTC),
+		},
+		{
+			name:  "RFC 1123 with the named zone",
+			value: "Mon, 03 Jun 2024 09:15:00 EDT",
+			want:  time.Date(2024, time.June, 3, 13, 15, 0, 0, time.UTC),
+		},
+		{
+			name:  "RFC 5322 with the comment",
+			value: "Mon, 3 Jun 2024 09:15:00 +0000 (UTC)",
+			want:  time.Date(2024, time.June, 3, 9, 15, 0, 0, time.UTC),
+		},
+		{
+			name:  "RFC 3339 with the offset",
+			value: "2024-06-03T09:15:00+03:00",
+			want:  time.Date(2024, time.June, 3, 6, 15, 0, 0, time.UTC),
+		},
+		{
+			name:  "ISO date",
+			value: "2024-06-03",
+			want:  time.Date(2024, time.June, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "ISO date and time without the zone",
+			value: "2024-06-03 09:15",
+			want:  time.Date(2024, time.June, 3, 9, 15, 0, 0, time.UTC),
+		},
+		{
+			name:    "IANA zone name",
+			value:   "2024-06-03 09:15 Europe/Kyiv",
+			options: Options{},
+			want:    time.Date(2024, time.June, 3, 6, 15, 0, 0, time.UTC),
+		},
+		{
+			name:    "Location of the dates without the zone",
+			value:   "03.06.2024 09:15",
+			options: Options{Location: time.FixedZone("EEST", 3*60*60)},
+			want:    time.Date(2024, time.June, 3, 6, 15, 0, 0, time.UTC),
+		},
+		{
+			name:  "English month name with the ordinal day",
+			value: "June 3rd, 2024 at 9:15 AM",
+			want:  time.Date(2024, time.June, 3, 9, 15, 0, 0, time.UTC),
+		},
+		{
+			name:  "Ukrainian month name",
+			value: "3 червня 2024 р. о 09:15",
+			want:  time.Date(2024, time.June, 3, 9, 15, 0, 0, time.UTC),
+		},
+		{
+			name:  "Russian month name",
+			value: "3 июня 2024 г.",
+			want:  time.Date(2024, time.June, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "Missing year is the year of the fetch time",
+			value:        "June 3",
+			options:      Options{Layout: "January 2"},
+			want:         time.Date(2024, time.June, 3, 0, 0, 0, 0, time.UTC),
+			wantInferred: true,
+		},
+		{
+			name:         "Missing year of the future date is the previous year",
+			value:        "28 грудня",
+			want:         time.Date(2023, time.December, 28, 0, 0, 0, 0, time.UTC),
+			wantInferred: true,
+		},
+		{
+			name:         "Hours ago",
+			value:        "3 hours ago",
+			want:         time.Date(2024, time.June, 10, 9, 0, 0, 0, time.UTC),
+			wantInferred: true,
+		},
+		{
+			name:         "An hour ago",
+			value:        "an hour ago",
+			want:         time.Date(2024, time.June, 10, 11, 0, 0, 0, time.UTC),
+			wantInferred: true,
+		},
+		{
+			name:         "Ukrainian minutes ago",
+			value:        "15 хвилин тому",
+			want:         time.Date(2024, time.June, 10, 11, 45, 0, 0, time.UTC),
+			wantInferred: true,
+		},
+		{
+			name:         "Ukrainian hour ago",
+			value:        "годину тому",
+			want:         time.Date(2024, time.June, 10, 11, 0, 0, 0, time.UTC),
+			wantInferred: true,
+		},
+		{
+			name:         "Russian days ago",
+			value:        "2 дня назад",
+			want:         time.Date(2024, time.June, 8, 12, 0, 0, 0, time.UTC),
+			wantInferred: true,
+		},
+		{
+			name:         "Russian years ago",
+			value:        "2 года назад",
+			want:         time.Date(2022, time.June, 10, 12, 0, 0, 0, time.UTC),
+			wantInferred: true,
+		},
+		{
+			name:         "Yesterday",
+			value:        "вчора",
+			want:         time.Date(2024, time.June, 9, 12, 0, 0, 0, time.UTC),
+			wantInferred: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.options.Reference = reference
+			got, err := Normalize(tt.value, tt.options)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got.Time), "got %v, want %v", got.Time, tt.want)
+			assert.Equal(t, time.UTC, got.Time.Location())
+			assert.Equal(t, tt.wantInferred, got.Inferred)
+		})
+	}
+}
+
+func TestNormalize_Errors(t *testing.T) {
+	_, err := Normalize("  ", Options{})
+	assert.ErrorIs(t, err, ErrEmpty)
+
+	_, err = Normalize("sometime soon", Options{})
+	assert.Error(t, err)
+
+	_, err = Normalize("3 parsecs ago", Options{})
+	assert.Error(t, err)
+}
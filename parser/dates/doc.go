@@ -0,0 +1,6 @@
+// Package dates normalizes the dates of the news found in the sources to UTC.
+// Normalize understands the RFC 1123, RFC 3339 and ISO 8601 variants, the dates without
+// the year, the relative expressions like "3 hours ago", the named time zones and the
+// English, Ukrainian and Russian month names. The missing years and the relative dates
+// are inferred relative to the fetch time and such dates are marked as inferred.
+package dates
@@ -0,0 +1,111 @@
+package dates
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unit is the unit of the relative date.
+type unit struct {
+	duration      time.Duration
+	months, years int
+}
+
+var (
+	second = unit{duration: time.Second}
+	minute = unit{duration: time.Minute}
+	hour   = unit{duration: time.Hour}
+	day    = unit{duration: 24 * time.Hour}
+	week   = unit{duration: 7 * 24 * time.Hour}
+	month  = unit{months: 1}
+	year   = unit{years: 1}
+)
+
+// relativeSuffixes are the words which end the relative dates: "3 hours ago", "3 години тому", "3 часа назад".
+var relativeSuffixes = []string{"ago", "тому", "назад"}
+
+// relativeDays are the relative dates which are the whole words, in days before the fetch time.
+var relativeDays = map[string]int{
+	"now": 0, "just now": 0, "today": 0, "yesterday": 1,
+	"щойно": 0, "зараз": 0, "сьогодні": 0, "вчора": 1, "учора": 1, "позавчора": 2,
+	"только что": 0, "сейчас": 0, "сегодня": 0, "вчера": 1, "позавчера": 2,
+}
+
+// yearWords are the forms of the year in Ukrainian and Russian, which are compared exactly
+// because "год" is also the beginning of the Ukrainian hour.
+var yearWords = map[string]struct{}{
+	"рік": {}, "роки": {}, "років": {}, "год": {}, "года": {}, "годы": {}, "лет": {},
+}
+
+// unitPrefixes are the beginnings of the units in English, Ukrainian and Russian.
+var unitPrefixes = []struct {
+	prefix string
+	unit   unit
+}{
+	{"sec", second}, {"секунд", second}, {"сек", second},
+	{"min", minute}, {"хвилин", minute}, {"хв", minute}, {"минут", minute}, {"мин", minute},
+	{"hour", hour}, {"hr", hour}, {"годин", hour}, {"час", hour},
+	{"day", day}, {"ден", day}, {"дн", day},
+	{"week", week}, {"тиж", week}, {"недел", week}, {"нед", week},
+	{"month", month}, {"місяц", month}, {"міс", month}, {"месяц", month}, {"мес", month},
+	{"year", year}, {"yr", year},
+}
+
+// parseRelative parses the relative date like "3 hours ago", "вчора" or "2 дня назад".
+func parseRelative(value string, reference time.Time) (time.Time, bool) {
+	value = strings.Join(strings.Fields(strings.ToLower(value)), " ")
+	if days, exists := relativeDays[value]; exists {
+		return reference.AddDate(0, 0, -days), true
+	}
+
+	words := strings.Fields(value)
+	if len(words) < 2 || !isRelativeSuffix(words[len(words)-1]) {
+		return time.Time{}, false
+	}
+	words = words[:len(words)-1]
+
+	quantity := 1
+	if len(words) == 2 {
+		var err error
+		if quantity, err = strconv.Atoi(words[0]); err != nil {
+			if words[0] != "a" && words[0] != "an" && words[0] != "one" {
+				return time.Time{}, false
+			}
+			quantity = 1
+		}
+		words = words[1:]
+	}
+	if len(words) != 1 {
+		return time.Time{}, false
+	}
+
+	relativeUnit, ok := parseUnit(words[0])
+	if !ok {
+		return time.Time{}, false
+	}
+	return reference.
+		Add(-time.Duration(quantity)*relativeUnit.duration).
+		AddDate(-quantity*relativeUnit.years, -quantity*relativeUnit.months, 0), true
+}
+
+func isRelativeSuffix(word string) bool {
+	for _, suffix := range relativeSuffixes {
+		if word == suffix {
+			return true
+		}
+	}
+	return false
+}
+
+func parseUnit(word string) (unit, bool) {
+	if _, exists := yearWords[word]; exists {
+		return year, true
+	}
+	for _, unitPrefix := range unitPrefixes {
+		if strings.HasPrefix(word, unitPrefix.prefix) {
+			return unitPrefix.unit, true
+		}
+	}
+	return unit{}, false
+}
@@ -1,3 +1,3 @@
-// Package parser provides parsers for different types of sources (RSS, JSON, HTML).
+// Package parser provides parsers for different types of sources (RSS, JSON, HTML, news sitemaps, email newsletters).
 // It includes functionality to parse files and retrieve articles from these sources.
 package parser
@@ -0,0 +1,294 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// descriptionLength is the maximum length of the excerpt of the newsletter used as the description.
+const descriptionLength = 300
+
+var (
+	textLinkPattern = regexp.MustCompile(`https?://[^\s<>"')\]]+`)
+	mboxFromEscape  = regexp.MustCompile(`^>+From `)
+)
+
+// Email analyzes the newsletters stored in the mbox files or in the directories of the .eml files.
+type Email struct {
+}
+
+// emailBody is the decoded text of the message.
+type emailBody struct {
+	text  string
+	html  string
+	links []string
+}
+
+// Extensions returns the extensions of the message files read from the directory of the source.
+func (email Email) Extensions() []string {
+	return []string{".eml"}
+}
+
+// Parse reads the mbox file or the single message from the reader and returns the news
+// of the messages: the subject is the title, the excerpt of the text is the description
+// and the canonical or the first link of the message is the link of the news.
+// The messages which cannot be read are skipped and listed in the report,
+// the error is returned only if none of the messages can be read.
+func (email Email) Parse(ctx context.Context, reader io.Reader, name source.Name) ([]news.News, Report, error) {
+	var articles []news.News
+	var report Report
+	var messageError error
+	index := 0
+	err := splitMessages(NewContextReader(ctx, reader), func(message []byte) error {
+		defer func() { index++ }()
+		article, err := parseMessage(message, name, index, &report)
+		if err != nil {
+			messageError = err
+			report.Skip(name, ItemName(index, "", ""), err.Error())
+			return nil
+		}
+		articles = append(articles, article)
+		return nil
+	})
+	if err != nil {
+		return nil, report, err
+	}
+	if len(articles) == 0 && messageError != nil {
+		return nil, report, messageError
+	}
+	return articles, report, nil
+}
+
+// splitMessages passes the messages of the mbox file to the handler. The reader
+// without the "From " separator line is the single message.
+func splitMessages(reader io.Reader, handle func(message []byte) error) error {
+	bufferedReader := bufio.NewReader(reader)
+	var message bytes.Buffer
+	isMbox, previousLineEmpty, firstLine := false, true, true
+
+	flush := func() error {
+		if len(bytes.TrimSpace(message.Bytes())) == 0 {
+			return nil
+		}
+		err := handle(bytes.Clone(message.Bytes()))
+		message.Reset()
+		return err
+	}
+
+	for {
+		line, err := bufferedReader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if line != "" {
+			if strings.HasPrefix(line, "From ") && (firstLine || (isMbox && previousLineEmpty)) {
+				isMbox = true
+				if err := flush(); err != nil {
+					return err
+				}
+			} else {
+				if isMbox && mboxFromEscape.MatchString(line) {
+					line = line[1:]
+				}
+				message.WriteString(line)
+			}
+			previousLineEmpty = strings.TrimRight(line, "\r\n") == ""
+			firstLine = false
+		}
+		if errors.Is(err, io.EOF) {
+			return flush()
+		}
+	}
+}
+
+// parseMessage converts the single message to the news. The message without
+// the valid date gets the fetch time, which is listed in the report.
+func parseMessage(rawMessage []byte, name source.Name, index int, report *Report) (news.News, error) {
+	message, err := mail.ReadMessage(bytes.NewReader(rawMessage))
+	if err != nil {
+		return news.News{}, fmt.Errorf("failed to read email message: %w", err)
+	}
+
+	decoder := mime.WordDecoder{}
+	subject, err := decoder.DecodeHeader(message.Header.Get("Subject"))
+	if err != nil {
+		subject = message.Header.Get("Subject")
+	}
+
+	var body emailBody
+	if err := body.read(message.Header.Get("Content-Type"), message.Header.Get("Content-Transfer-Encoding"), message.Body); err != nil {
+		return news.News{}, err
+	}
+
+	text := body.text
+	if strings.TrimSpace(text) == "" {
+		text = htmlToText(body.html)
+	}
+	text = strings.Join(strings.Fields(text), " ")
+
+	link := news.Link(body.link())
+	article := news.News{
+		ID:          news.NewID(message.Header.Get("Message-Id"), link),
+		Title:       news.Title(strings.TrimSpace(subject)),
+		Description: news.Description(excerpt(text, descriptionLength)),
+		Link:        link,
+		SourceName:  name,
+		Content:     news.Content(text),
+	}
+	if date, err := normalizeDate(message.Header.Get("Date")); err == nil {
+		article.Date, article.DateInferred = date.Time, date.Inferred
+	} else if date, err := message.Header.Date(); err == nil {
+		article.Date = date.UTC()
+	} else {
+		reason := "the date is missing"
+		if message.Header.Get("Date") != "" {
+			reason = fmt.Sprintf("the date %q cannot be parsed", message.Header.Get("Date"))
+		}
+		article.Date, article.DateInferred = now().UTC(), true
+		report.Repair(name, ItemName(index, string(article.Title), string(article.Link)), reason, FallbackFetchTime)
+	}
+	if addresses, err := message.Header.AddressList("From"); err == nil {
+		for _, address := range addresses {
+			if address.Name != "" {
+				article.Authors = append(article.Authors, address.Name)
+			} else {
+				article.Authors = append(article.Authors, address.Address)
+			}
+		}
+	}
+	return article, nil
+}
+
+// read decodes the part of the message with the passed content type and transfer encoding.
+// The text and the HTML parts of the multipart messages are collected, the attachments are skipped.
+func (body *emailBody) read(contentType, transferEncoding string, reader io.Reader) error {
+	mediaType, parameters, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		multipartReader := multipart.NewReader(reader, parameters["boundary"])
+		for {
+			part, err := multipartReader.NextPart()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read part of email message: %w", err)
+			}
+			if strings.HasPrefix(part.Header.Get("Content-Disposition"), "attachment") {
+				continue
+			}
+			if err := body.read(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part); err != nil {
+				return err
+			}
+		}
+	}
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return nil
+	}
+
+	content, err := io.ReadAll(decodeTransfer(transferEncoding, reader))
+	if err != nil {
+		return fmt.Errorf("failed to decode email message: %w", err)
+	}
+	if !utf8.Valid(content) {
+		content = bytes.ToValidUTF8(content, []byte("�"))
+	}
+
+	if mediaType == "text/html" {
+		body.html += string(content)
+		body.links = append(body.links, htmlLinks(string(content))...)
+	} else {
+		body.text += string(content)
+		body.links = append(body.links, textLinkPattern.FindAllString(string(content), -1)...)
+	}
+	return nil
+}
+
+// link returns the canonical link of the HTML part or the first link of the message
+// which is not the link for unsubscribing.
+func (body *emailBody) link() string {
+	if body.html != "" {
+		if document, err := goquery.NewDocumentFromReader(strings.NewReader(body.html)); err == nil {
+			if canonical, exists := document.Find(`link[rel="canonical"]`).Attr("href"); exists && strings.TrimSpace(canonical) != "" {
+				return strings.TrimSpace(canonical)
+			}
+		}
+	}
+	for _, link := range body.links {
+		if !strings.Contains(strings.ToLower(link), "unsubscribe") {
+			return link
+		}
+	}
+	return ""
+}
+
+func decodeTransfer(transferEncoding string, reader io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(transferEncoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, reader)
+	case "quoted-printable":
+		return quotedprintable.NewReader(reader)
+	default:
+		return reader
+	}
+}
+
+// htmlLinks returns the http links of the anchors of the HTML document.
+func htmlLinks(html string) []string {
+	document, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil
+	}
+	var links []string
+	document.Find("a[href]").Each(func(i int, anchor *goquery.Selection) {
+		href := strings.TrimSpace(anchor.AttrOr("href", ""))
+		if strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://") {
+			links = append(links, href)
+		}
+	})
+	return links
+}
+
+// htmlToText returns the visible text of the HTML document.
+func htmlToText(html string) string {
+	document, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return html
+	}
+	document.Find("script, style, head").Remove()
+	document.Find("br, p, div, li, tr, h1, h2, h3, h4, h5, h6").Each(func(i int, element *goquery.Selection) {
+		element.AppendHtml(" ")
+	})
+	return document.Text()
+}
+
+// excerpt returns the beginning of the text which is not longer than the limit, cut by the word.
+func excerpt(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)[:limit]
+	cut := string(runes)
+	if index := strings.LastIndex(cut, " "); index > 0 {
+		cut = cut[:index]
+	}
+	return strings.TrimSpace(cut) + "..."
+}
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"context"
+	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmail_Parse(t *testing.T) {
+	file, err := os.Open("../mnt/resources/testdata/newsletters.mbox")
+	require.NoError(t, err)
+	defer file.Close()
+
+	got, _, err := Email{}.Parse(context.Background(), file, "newsletters")
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	weekly := got[0]
+	assert.Equal(t, news.NewID("<weekly-42@example.com>", ""), weekly.ID)
+	assert.Equal(t, news.Title("Weekly digest: energy market"), weekly.Title)
+	assert.Equal(t, news.Link("https://news.example.com/weekly/42?utm_source=newsletter"), weekly.Link)
+	assert.True(t, weekly.Date.Equal(time.Date(2024, time.June, 3, 10, 0, 0, 0, time.UTC)))
+	assert.Equal(t, []string{"Example Weekly"}, weekly.Authors)
+	assert.Equal(t, source.Name("newsletters"), weekly.SourceName)
+	assert.True(t, strings.HasPrefix(string(weekly.Description), "Hello readers, This week: the reform of the energy market — read more"),
+		"quoted-printable text part must be decoded: %s", weekly.Description)
+	assert.Contains(t, weekly.Content, "From now on the digest is sent every Monday.", "mbox escaping must be removed")
+
+	alert := got[1]
+	assert.Equal(t, news.Title("Breaking: elections results"), alert.Title)
+	assert.Equal(t, news.Link("https://example.net/elections/results"), alert.Link, "canonical link must be preferred")
+	assert.Equal(t, news.Description("Elections results The votes are counted. Other news"), alert.Description)
+	assert.Equal(t, []string{"alerts@example.net"}, alert.Authors)
+}
+
+func TestEmail_ParseSingleMessage(t *testing.T) {
+	message := "From: Brief <brief@example.org>\r\n" +
+		"Subject: Brief\r\n" +
+		"Date: Wed, 05 Jun 2024 07:00:00 +0000\r\n" +
+		"Content-Type: text/html; charset=utf-8\r\n" +
+		"Content-Transfer-Encoding: base64\r\n\r\n" +
+		"PHA+UmVhZCA8YSBocmVmPSJodHRwczovL2V4YW1wbGUub3JnL3Vuc3Vic2NyaWJlIj51bnN1YnNj\r\n" +
+		"cmliZTwvYT4gb3IgPGEgaHJlZj0iaHR0cHM6Ly9leGFtcGxlLm9yZy9zdG9yeSI+c3Rvcnk8L2E+\r\n" +
+		"PC9wPg==\r\n"
+
+	got, _, err := Email{}.Parse(context.Background(), strings.NewReader(message), "brief")
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, news.Link("https://example.org/story"), got[0].Link, "unsubscribe link must be skipped")
+	assert.Equal(t, news.Description("Read unsubscribe or story"), got[0].Description)
+
+	_, _, err = Email{}.Parse(context.Background(), strings.NewReader("not a message"), "brief")
+	assert.Error(t, err)
+}
+
+func TestExcerpt(t *testing.T) {
+	assert.Equal(t, "short text", excerpt("short text", 20))
+	assert.Equal(t, "the long...", excerpt("the long text", 10))
+}
@@ -1,2 +1,4 @@
 // Package html is used for parsers that parse specific news news that have html structure.
+// Scraper parses the page of any site by the selectors stored in the source record,
+// and the selectors of the known sites, like USA Today, are shipped as presets.
 package html
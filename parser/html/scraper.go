@@ -0,0 +1,199 @@
+package html
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"io"
+	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
+	"news-aggregator/parser"
+	"news-aggregator/parser/charset"
+	"news-aggregator/parser/dates"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// now returns the fetch time used as the reference of the dates of the news.
+var now = time.Now
+
+// Scraper reads and parses the HTML page of any site and returns a slice of news.
+// The places of the news on the page are described by the selectors.
+type Scraper struct {
+	Selectors source.Selectors
+}
+
+// Parse parses the page read from the reader using the selectors of the scraper.
+func (scraper Scraper) Parse(ctx context.Context, reader io.Reader, name source.Name) ([]news.News, parser.Report, error) {
+	return scrape(ctx, reader, name, scraper.Selectors)
+}
+
+// ParseSource parses the page of the passed source using the selectors stored in the source record.
+// The preset of the name of the source is used if the source does not have its own selectors,
+// and the selectors of the scraper are used if there is no such preset.
+func (scraper Scraper) ParseSource(ctx context.Context, reader io.Reader, currentSource source.Source) ([]news.News, parser.Report, error) {
+	selectors := scraper.Selectors
+	if currentSource.Selectors != nil {
+		selectors = *currentSource.Selectors
+	} else if preset, exists := Presets[strings.ToLower(string(currentSource.Name))]; exists {
+		selectors = preset
+	}
+	return scrape(ctx, reader, currentSource.Name, selectors)
+}
+
+// scrape parses the items of the page. The items without both the title and the link are skipped,
+// and the items without the valid date get the current day. Both are listed in the report.
+func scrape(ctx context.Context, reader io.Reader, name source.Name, selectors source.Selectors) (newsArticles []news.News, report parser.Report, parseError error) {
+	if strings.TrimSpace(selectors.Item) == "" {
+		return nil, report, errors.New("the item selector is not specified for source: " + string(name))
+	}
+
+	var datePattern *regexp.Regexp
+	if selectors.DatePattern != "" {
+		var err error
+		datePattern, err = regexp.Compile(selectors.DatePattern)
+		if err != nil {
+			return nil, report, fmt.Errorf("invalid date pattern of source %s: %w", name, err)
+		}
+	}
+
+	doc, err := goquery.NewDocumentFromReader(parser.NewContextReader(ctx, charset.NewReader(reader, "")))
+	if err != nil {
+		return nil, report, err
+	}
+
+	doc.Find(selectors.Item).EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if err := ctx.Err(); err != nil {
+			parseError = err
+			return false
+		}
+		title := elementValue(s, selectors.Title, "")
+		description := optionalElementValue(s, selectors.Description, selectors.DescriptionAttribute)
+		link := absoluteLink(selectors.BaseURL, attributeValue(s, selectors.Link, "href"))
+		itemName := parser.ItemName(i, title, link)
+		if title == "" && link == "" {
+			report.Skip(name, itemName, "the item has neither title nor link")
+			return true
+		}
+
+		date, dateInferred := scrapeDate(optionalElementValue(s, selectors.Date, selectors.DateAttribute),
+			datePattern, selectors, name, itemName, &report)
+
+		newsLink := news.Link(link)
+		newsArticles = append(newsArticles, news.News{
+			ID:           news.NewID("", newsLink),
+			Title:        news.Title(title),
+			Description:  news.Description(description),
+			Link:         newsLink,
+			Date:         date,
+			DateInferred: dateInferred,
+			SourceName:   name,
+			Categories:   categories(s, selectors),
+			Images:       images(s, selectors),
+			Language:     selectors.Language,
+		})
+
+		return true
+	})
+
+	if parseError != nil {
+		return nil, report, parseError
+	}
+
+	return newsArticles, report, nil
+}
+
+// scrapeDate normalizes the date of the news using the layout of the selectors. The year is
+// inferred from the fetch time if the layout does not contain it. The fetch time is used if the
+// news does not have the valid date, which is reported if the selectors describe the date.
+func scrapeDate(rawDate string, datePattern *regexp.Regexp, selectors source.Selectors, name source.Name,
+	itemName string, report *parser.Report) (time.Time, bool) {
+	if datePattern != nil {
+		rawDate = datePattern.FindString(rawDate)
+	}
+	fetchTime := now()
+	date, err := dates.Normalize(rawDate, dates.Options{Layout: selectors.DateLayout, Reference: fetchTime})
+	if err == nil {
+		return date.Time, date.Inferred
+	}
+	if rawDate != "" {
+		report.Repair(name, itemName, fmt.Sprintf("the date %q cannot be parsed: %v", rawDate, err), parser.FallbackFetchTime)
+	} else if selectors.Date != "" || selectors.DateAttribute != "" {
+		report.Repair(name, itemName, "the date is missing", parser.FallbackFetchTime)
+	}
+	return fetchTime.UTC(), true
+}
+
+// categories returns the category of the news if the selectors describe it.
+func categories(s *goquery.Selection, selectors source.Selectors) []string {
+	category := optionalElementValue(s, selectors.Category, selectors.CategoryAttribute)
+	if category == "" {
+		return nil
+	}
+	return []string{category}
+}
+
+// images returns the absolute links to the images of the news if the selectors describe them.
+func images(s *goquery.Selection, selectors source.Selectors) []news.Link {
+	if selectors.Image == "" {
+		return nil
+	}
+	var links []news.Link
+	s.Find(selectors.Image).Each(func(i int, image *goquery.Selection) {
+		link, exists := image.Attr(selectors.ImageAttribute)
+		if selectors.ImageAttribute == "" || !exists {
+			link, _ = image.Attr("src")
+		}
+		if link = strings.TrimSpace(link); link != "" {
+			links = append(links, news.Link(absoluteLink(selectors.BaseURL, link)))
+		}
+	})
+	return links
+}
+
+// elementValue returns the trimmed value of the attribute of the selected element
+// or its text if the attribute is not specified.
+func elementValue(s *goquery.Selection, selector, attribute string) string {
+	element := selectElement(s, selector)
+	if attribute == "" {
+		return strings.TrimSpace(element.Text())
+	}
+	value, _ := element.Attr(attribute)
+	return strings.TrimSpace(value)
+}
+
+// optionalElementValue returns the value of the element like elementValue,
+// but returns an empty string if neither the selector nor the attribute are specified.
+func optionalElementValue(s *goquery.Selection, selector, attribute string) string {
+	if selector == "" && attribute == "" {
+		return ""
+	}
+	return elementValue(s, selector, attribute)
+}
+
+// attributeValue returns the trimmed value of the attribute of the selected element.
+func attributeValue(s *goquery.Selection, selector, attribute string) string {
+	value, _ := selectElement(s, selector).Attr(attribute)
+	return strings.TrimSpace(value)
+}
+
+// selectElement returns the first element matching the selector inside the item or the item itself.
+func selectElement(s *goquery.Selection, selector string) *goquery.Selection {
+	if selector == "" {
+		return s
+	}
+	return s.Find(selector).First()
+}
+
+// absoluteLink prepends the base URL to the relative link.
+func absoluteLink(baseURL, link string) string {
+	if link == "" || strings.HasPrefix(link, "http") {
+		return link
+	}
+	if !strings.HasPrefix(link, "/") {
+		link = "/" + link
+	}
+	return strings.TrimSuffix(baseURL, "/") + link
+}
@@ -0,0 +1,188 @@
+package html
+
+import (
+	"context"
+	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
+	"news-aggregator/parser"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScraper_ParseSource(t *testing.T) {
+	fetchTime := time.Date(2024, time.June, 10, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fetchTime }
+	defer func() { now = time.Now }()
+
+	selectors := source.Selectors{
+		Item:          "section.feed article.card",
+		Title:         "h2.card-title",
+		Link:          "a.card-link",
+		Description:   "p.card-summary",
+		Date:          "time",
+		DateAttribute: "datetime",
+		DateLayout:    time.RFC3339,
+		Image:         "img",
+		Language:      "en",
+		BaseURL:       "https://example.com/",
+	}
+
+	tests := []struct {
+		name          string
+		currentSource source.Source
+		scraper       Scraper
+		want          []news.News
+		wantReport    parser.Report
+		wantErr       bool
+	}{
+		{
+			name: "Parse page with the selectors of the source",
+			currentSource: source.Source{
+				Name:       "example",
+				PathToFile: "../../mnt/resources/testdata/test_scraper.html",
+				SourceType: source.HTML,
+				Selectors:  &selectors,
+			},
+			want: []news.News{
+				{
+					ID:          news.NewID("", "https://example.com/news/1"),
+					Title:       "Scraped News 1",
+					Description: "Summary 1",
+					Link:        "https://example.com/news/1",
+					Date:        time.Date(2024, time.June, 1, 10, 30, 0, 0, time.UTC),
+					SourceName:  "example",
+					Images:      []news.Link{"https://example.com/img/1.png"},
+					Language:    "en",
+				},
+				{
+					ID:          news.NewID("", "https://example.com/news/2"),
+					Title:       "Scraped News 2",
+					Description: "Summary 2",
+					Link:        "https://example.com/news/2",
+					Date:        time.Date(2024, time.June, 2, 8, 0, 0, 0, time.UTC),
+					SourceName:  "example",
+					Language:    "en",
+				},
+			},
+			wantReport: parser.Report{Diagnostics: []parser.Diagnostic{
+				{Source: "example", Item: "item 3", Action: parser.Skipped, Reason: "the item has neither title nor link"},
+			}},
+		},
+		{
+			name: "Parse page without the item selector",
+			currentSource: source.Source{
+				Name:       "example",
+				PathToFile: "../../mnt/resources/testdata/test_scraper.html",
+				SourceType: source.HTML,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Parse page with the dates without the year",
+			currentSource: source.Source{
+				Name:       "example",
+				PathToFile: "../../mnt/resources/testdata/test_scraper.html",
+				SourceType: source.HTML,
+				Selectors: &source.Selectors{
+					Item:       "article.card",
+					Title:      "h2.card-title",
+					Link:       "a.card-link",
+					Date:       "time",
+					DateLayout: time.RFC3339,
+				},
+			},
+			want: []news.News{
+				{
+					ID:           news.NewID("", "/news/1"),
+					Title:        "Scraped News 1",
+					Link:         "/news/1",
+					Date:         time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+					DateInferred: true,
+					SourceName:   "example",
+				},
+				{
+					ID:           news.NewID("", "https://example.com/news/2"),
+					Title:        "Scraped News 2",
+					Link:         "https://example.com/news/2",
+					Date:         time.Date(2024, time.June, 2, 0, 0, 0, 0, time.UTC),
+					DateInferred: true,
+					SourceName:   "example",
+				},
+			},
+			wantReport: parser.Report{Diagnostics: []parser.Diagnostic{
+				{Source: "example", Item: "item 3", Action: parser.Skipped, Reason: "the item has neither title nor link"},
+			}},
+		},
+		{
+			name: "Parse page with the invalid dates",
+			currentSource: source.Source{
+				Name:       "example",
+				PathToFile: "../../mnt/resources/testdata/test_scraper.html",
+				SourceType: source.HTML,
+				Selectors: &source.Selectors{
+					Item:  "article.card",
+					Title: "h2.card-title",
+					Link:  "a.card-link",
+					Date:  "p.card-summary",
+				},
+			},
+			want: []news.News{
+				{
+					ID:           news.NewID("", "/news/1"),
+					Title:        "Scraped News 1",
+					Link:         "/news/1",
+					Date:         fetchTime,
+					DateInferred: true,
+					SourceName:   "example",
+				},
+				{
+					ID:           news.NewID("", "https://example.com/news/2"),
+					Title:        "Scraped News 2",
+					Link:         "https://example.com/news/2",
+					Date:         fetchTime,
+					DateInferred: true,
+					SourceName:   "example",
+				},
+			},
+			wantReport: parser.Report{Diagnostics: []parser.Diagnostic{
+				{
+					Source:   "example",
+					Item:     "Scraped News 1",
+					Action:   parser.Repaired,
+					Reason:   `the date "Summary 1" cannot be parsed: unknown format of date "Summary 1"`,
+					Fallback: parser.FallbackFetchTime,
+				},
+				{
+					Source:   "example",
+					Item:     "Scraped News 2",
+					Action:   parser.Repaired,
+					Reason:   `the date "Summary 2" cannot be parsed: unknown format of date "Summary 2"`,
+					Fallback: parser.FallbackFetchTime,
+				},
+				{Source: "example", Item: "item 3", Action: parser.Skipped, Reason: "the item has neither title nor link"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := os.Open(string(tt.currentSource.PathToFile))
+			if err != nil {
+				t.Fatalf("Failed to open file: %v", err)
+			}
+			defer file.Close()
+
+			got, report, err := tt.scraper.ParseSource(context.Background(), file, tt.currentSource)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSource() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(report, tt.wantReport) {
+				t.Errorf("ParseSource() report = %v, want %v", report, tt.wantReport)
+			}
+		})
+	}
+}
@@ -1,14 +1,19 @@
 package html
 
 import (
+	"context"
 	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
+	"os"
 	"reflect"
 	"testing"
 	"time"
 )
 
 func TestUsaToday_ParseSource(t *testing.T) {
+	now = func() time.Time { return time.Date(2024, time.June, 10, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
 	type args struct {
 		path source.PathToFile
 		name source.Name
@@ -26,26 +31,61 @@ func TestUsaToday_ParseSource(t *testing.T) {
 			},
 			want: []news.News{
 				{
-					Title:       "Test News 1",
-					Description: "Description 1",
-					Link:        "https://www.usatoday.com/story/1",
-					Date:        time.Date(time.Now().Year(), time.June, 1, 0, 0, 0, 0, time.UTC),
-					SourceName:  "testusatoday",
+					ID:           news.NewID("", "https://www.usatoday.com/story/1"),
+					Title:        "Test News 1",
+					Description:  "Description 1",
+					Link:         "https://www.usatoday.com/story/1",
+					Date:         time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+					DateInferred: true,
+					SourceName:   "testusatoday",
+					Categories:   []string{"WORLD"},
+					Images:       []news.Link{"https://www.usatoday.com/images/1.jpg"},
+					Language:     "en",
+				},
+				{
+					ID:           news.NewID("", "https://www.usatoday.com/story/2"),
+					Title:        "Test News 2",
+					Description:  "Description 2",
+					Link:         "https://www.usatoday.com/story/2",
+					Date:         time.Date(2024, time.June, 2, 0, 0, 0, 0, time.UTC),
+					DateInferred: true,
+					SourceName:   "testusatoday",
+					Language:     "en",
 				},
+			},
+		},
+		{
+			name: "Parse KOI8-U HTML file",
+			args: args{
+				path: "../../mnt/resources/testdata/koi8u_usatoday.html",
+				name: "testusatoday",
+			},
+			want: []news.News{
 				{
-					Title:       "Test News 2",
-					Description: "Description 2",
-					Link:        "https://www.usatoday.com/story/2",
-					Date:        time.Date(time.Now().Year(), time.June, 2, 0, 0, 0, 0, time.UTC),
-					SourceName:  "testusatoday",
+					ID:           news.NewID("", "https://www.usatoday.com/story/1"),
+					Title:        "Новина про їжака",
+					Description:  "Опис новини про ґанок",
+					Link:         "https://www.usatoday.com/story/1",
+					Date:         time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+					DateInferred: true,
+					SourceName:   "testusatoday",
+					Categories:   []string{"СВІТ"},
+					Images:       []news.Link{"https://www.usatoday.com/images/1.jpg"},
+					Language:     "en",
 				},
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			htmlParser := UsaToday{}
-			if got, _ := htmlParser.Parse(tt.args.path, tt.args.name); !reflect.DeepEqual(got, tt.want) {
+			htmlParser := Scraper{Selectors: UsaTodaySelectors}
+			file, err := os.Open(string(tt.args.path))
+			if err != nil {
+				t.Fatalf("Failed to open file: %v", err)
+			}
+			defer file.Close()
+
+			if got, _, _ := htmlParser.Parse(context.Background(), file, tt.args.name); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Parse() = %v, want %v", got, tt.want)
 			}
 		})
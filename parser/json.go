@@ -1,37 +1,104 @@
 package parser
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
-	"os"
+	"news-aggregator/parser/charset"
+	"strings"
+	"time"
 )
 
 // Json analyzes JSON sources.
 type Json struct {
 }
 
-// Parse reads and parses a JSON file specified by the path and returns a slice of news.
-func (jsonFile Json) Parse(path source.PathToFile, name source.Name) ([]news.News, error) {
+// jsonArticle is the article in the format of the JSON sources.
+type jsonArticle struct {
+	Title       news.Title       `json:"title"`
+	Description news.Description `json:"description"`
+	Link        news.Link        `json:"url"`
+	Date        string           `json:"publishedAt"`
+	Author      string           `json:"author"`
+	Categories  []string         `json:"categories"`
+	Image       news.Link        `json:"urlToImage"`
+	Content     news.Content     `json:"content"`
+	UpdatedAt   string           `json:"updatedAt"`
+	Language    string           `json:"language"`
+}
 
-	newsContent, err := os.ReadFile(string(path))
-	if err != nil {
-		return nil, errors.New("Error with parse JSON content: " + err.Error())
-	}
+// Parse reads and parses a JSON document from the reader and returns a slice of news.
+// The articles without both the title and the link are skipped, and the articles without
+// the valid publication date get the updated date or the fetch time instead.
+// Every skipped or repaired article is listed in the report.
+func (jsonFile Json) Parse(ctx context.Context, reader io.Reader, name source.Name) ([]news.News, Report, error) {
 
+	var report Report
 	var newsData struct {
-		News []news.News `json:"articles"`
+		News []jsonArticle `json:"articles"`
 	}
 
-	err = json.Unmarshal(newsContent, &newsData)
+	err := json.NewDecoder(NewContextReader(ctx, charset.NewReader(reader, ""))).Decode(&newsData)
 	if err != nil {
-		return nil, errors.New("Error with parse JSON content: " + err.Error())
+		return nil, report, errors.New("Error with parse JSON content: " + err.Error())
 	}
 
-	for i := range newsData.News {
-		newsData.News[i].SourceName = name
+	var articles []news.News
+	for index, article := range newsData.News {
+		itemName := ItemName(index, string(article.Title), string(article.Link))
+		if strings.TrimSpace(string(article.Title)) == "" && strings.TrimSpace(string(article.Link)) == "" {
+			report.Skip(name, itemName, "the article has neither title nor link")
+			continue
+		}
+		articles = append(articles, article.toNews(name, itemName, &report))
 	}
 
-	return newsData.News, nil
+	return articles, report, nil
+}
+
+// toNews converts the JSON article to the news of the passed source.
+func (article jsonArticle) toNews(name source.Name, itemName string, report *Report) news.News {
+	converted := news.News{
+		ID:          news.NewID("", article.Link),
+		Title:       article.Title,
+		Description: article.Description,
+		Link:        article.Link,
+		SourceName:  name,
+		Categories:  article.Categories,
+		Content:     article.Content,
+		UpdatedAt:   normalizePointer(article.UpdatedAt),
+		Language:    article.Language,
+	}
+	converted.Date, converted.DateInferred = article.date(name, itemName, report)
+	for _, author := range strings.Split(article.Author, ",") {
+		if author = strings.TrimSpace(author); author != "" {
+			converted.Authors = append(converted.Authors, author)
+		}
+	}
+	if article.Image != "" {
+		converted.Images = []news.Link{article.Image}
+	}
+	return converted
+}
+
+// date returns the publication date of the article in UTC and whether it was inferred.
+// If the article does not have the valid date, the updated date or the fetch time is used and reported.
+func (article jsonArticle) date(name source.Name, itemName string, report *Report) (time.Time, bool) {
+	if published, err := normalizeDate(article.Date); err == nil {
+		return published.Time, published.Inferred
+	}
+	reason := "the publication date is missing"
+	if strings.TrimSpace(article.Date) != "" {
+		reason = fmt.Sprintf("the publication date %q cannot be parsed", article.Date)
+	}
+	if updated := normalizePointer(article.UpdatedAt); updated != nil {
+		report.Repair(name, itemName, reason, FallbackUpdatedDate)
+		return *updated, true
+	}
+	report.Repair(name, itemName, reason, FallbackFetchTime)
+	return now().UTC(), true
 }
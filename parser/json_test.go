@@ -1,9 +1,12 @@
 package parser
 
 import (
+	"context"
 	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -25,15 +28,33 @@ func TestJson_ParseSource(t *testing.T) {
 				name: "testjson",
 			},
 			want: []news.News{
-				{Title: "Test News 1", Description: "Description 1", Link: "http://example.com/1", Date: parseDate("2024-06-01"), SourceName: "testjson"},
-				{Title: "Test News 2", Description: "Description 2", Link: "http://example.com/2", Date: parseDate("2024-06-02"), SourceName: "testjson"},
+				{ID: news.NewID("", "http://example.com/1"), Title: "Test News 1", Description: "Description 1", Link: "http://example.com/1", Date: parseDate("2024-06-01"), SourceName: "testjson",
+					Authors: []string{"John Doe", "Jane Roe"}, Images: []news.Link{"http://example.com/1.jpg"}, Content: "Content 1"},
+				{ID: news.NewID("", "http://example.com/2"), Title: "Test News 2", Description: "Description 2", Link: "http://example.com/2", Date: parseDate("2024-06-02"), SourceName: "testjson"},
+			},
+		},
+		{
+			name: "Parse undeclared windows-1251 JSON file",
+			args: args{
+				path: "../mnt/resources/testdata/windows1251_articles.json",
+				name: "testjson",
+			},
+			want: []news.News{
+				{ID: news.NewID("", "http://example.com/uk/json/1"), Title: "Новини з Києва", Description: "Опис новини", Link: "http://example.com/uk/json/1",
+					Date: time.Date(2024, time.June, 3, 9, 15, 0, 0, time.UTC), SourceName: "testjson", Authors: []string{"Олена Петренко"}},
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			jsonFile := Json{}
-			if got, _ := jsonFile.Parse(tt.args.path, tt.args.name); !reflect.DeepEqual(got, tt.want) {
+			file, err := os.Open(string(tt.args.path))
+			if err != nil {
+				t.Fatalf("Failed to open file: %v", err)
+			}
+			defer file.Close()
+
+			if got, _, _ := jsonFile.Parse(context.Background(), file, tt.args.name); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Parse() = %v, want %v", got, tt.want)
 			}
 		})
@@ -47,3 +68,36 @@ func parseDate(dateStr string) time.Time {
 	}
 	return date
 }
+
+func TestJson_ParseInvalidArticles(t *testing.T) {
+	fetchTime := time.Date(2024, time.June, 5, 9, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fetchTime }
+	defer func() { now = time.Now }()
+
+	content := `{"articles": [
+		{"title": "Updated", "url": "http://example.com/1", "publishedAt": "last June", "updatedAt": "2024-06-01T12:00:00Z"},
+		{"title": "Undated", "url": "http://example.com/2"},
+		{"description": "Without title and link"}
+	]}`
+
+	got, report, err := Json{}.Parse(context.Background(), strings.NewReader(content), "testjson")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	updated := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	want := []news.News{
+		{ID: news.NewID("", "http://example.com/1"), Title: "Updated", Link: "http://example.com/1", Date: updated, DateInferred: true, SourceName: "testjson", UpdatedAt: &updated},
+		{ID: news.NewID("", "http://example.com/2"), Title: "Undated", Link: "http://example.com/2", Date: fetchTime, DateInferred: true, SourceName: "testjson"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %v, want %v", got, want)
+	}
+	wantReport := Report{Diagnostics: []Diagnostic{
+		{Source: "testjson", Item: "Updated", Action: Repaired, Reason: `the publication date "last June" cannot be parsed`, Fallback: FallbackUpdatedDate},
+		{Source: "testjson", Item: "Undated", Action: Repaired, Reason: "the publication date is missing", Fallback: FallbackFetchTime},
+		{Source: "testjson", Item: "item 3", Action: Skipped, Reason: "the article has neither title nor link"},
+	}}
+	if !reflect.DeepEqual(report, wantReport) {
+		t.Errorf("Parse() report = %v, want %v", report, wantReport)
+	}
+}
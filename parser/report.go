@@ -0,0 +1,154 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"news-aggregator/entity/source"
+	"news-aggregator/parser/dates"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Action describes what was done with the item which could not be parsed as is.
+type Action string
+
+const (
+	// Skipped means the item is not returned by the parser.
+	Skipped Action = "skipped"
+	// Repaired means the item is returned with the value of the fallback.
+	Repaired Action = "repaired"
+)
+
+// The fallbacks used for the missing or invalid publication dates of the items.
+const (
+	FallbackUpdatedDate = "UpdatedParsed"
+	FallbackFeedDate    = "feed date"
+	FallbackFetchTime   = "fetch time"
+)
+
+// now returns the fetch time used for the items without the publication date.
+var now = time.Now
+
+// normalizeDate normalizes the date of the item to UTC relative to the fetch time.
+func normalizeDate(value string) (dates.Date, error) {
+	return dates.Normalize(value, dates.Options{Reference: now()})
+}
+
+// normalizePointer returns the normalized date or nil if the date is empty or invalid.
+func normalizePointer(value string) *time.Time {
+	date, err := normalizeDate(value)
+	if err != nil {
+		return nil
+	}
+	return &date.Time
+}
+
+// Diagnostic describes the item which was skipped or repaired during the parsing.
+type Diagnostic struct {
+	Source   source.Name `json:"source"`
+	Item     string      `json:"item"`
+	Action   Action      `json:"action"`
+	Reason   string      `json:"reason"`
+	Fallback string      `json:"fallback,omitempty"`
+}
+
+// State is the result of the collection of the source.
+type State string
+
+const (
+	// Collected means the source is parsed.
+	Collected State = "ok"
+	// Failed means the source cannot be read or parsed.
+	Failed State = "failed"
+	// TimedOut means the source is not parsed before its deadline.
+	TimedOut State = "timeout"
+)
+
+// SourceStatus describes the collection of the single source. Duration is the time
+// of the fetching and the parsing of the source.
+type SourceStatus struct {
+	State    State         `json:"state"`
+	Articles int           `json:"articles"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"-"`
+}
+
+// Report is the list of the diagnostics of the parsing. The parsers return the partial
+// results with the report instead of the error when only some items cannot be parsed.
+// The collector adds the status of every source, so the news of the healthy sources
+// are returned with the report when the other sources fail.
+type Report struct {
+	Diagnostics []Diagnostic                 `json:"diagnostics"`
+	Sources     map[source.Name]SourceStatus `json:"sources,omitempty"`
+}
+
+// Skip adds the diagnostic of the skipped item.
+func (report *Report) Skip(name source.Name, item, reason string) {
+	report.Diagnostics = append(report.Diagnostics, Diagnostic{Source: name, Item: item, Action: Skipped, Reason: reason})
+}
+
+// Repair adds the diagnostic of the item repaired by the fallback.
+func (report *Report) Repair(name source.Name, item, reason, fallback string) {
+	report.Diagnostics = append(report.Diagnostics, Diagnostic{
+		Source:   name,
+		Item:     item,
+		Action:   Repaired,
+		Reason:   reason,
+		Fallback: fallback,
+	})
+}
+
+// SetStatus sets the status of the collection of the source.
+func (report *Report) SetStatus(name source.Name, status SourceStatus) {
+	if report.Sources == nil {
+		report.Sources = make(map[source.Name]SourceStatus)
+	}
+	report.Sources[name] = status
+}
+
+// Merge adds the diagnostics and the statuses of the sources of the other report.
+func (report *Report) Merge(other Report) {
+	report.Diagnostics = append(report.Diagnostics, other.Diagnostics...)
+	for name, status := range other.Sources {
+		report.SetStatus(name, status)
+	}
+}
+
+// Warnings returns the descriptions of the sources which are not collected, sorted by the source name.
+func (report Report) Warnings() []string {
+	var warnings []string
+	for name, status := range report.Sources {
+		if status.State != Collected {
+			warnings = append(warnings, fmt.Sprintf("source %s %s: %s", name, status.State, status.Error))
+		}
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
+// Log writes the diagnostics and the sources which are not collected to the log as the warnings.
+func (report Report) Log() {
+	for _, diagnostic := range report.Diagnostics {
+		logrus.WithFields(logrus.Fields{
+			"source":   diagnostic.Source,
+			"item":     diagnostic.Item,
+			"fallback": diagnostic.Fallback,
+		}).Warnf("Parser: Item %s: %s", diagnostic.Action, diagnostic.Reason)
+	}
+	for _, warning := range report.Warnings() {
+		logrus.Warn("Collector: The news are incomplete, ", warning)
+	}
+}
+
+// ItemName returns the name of the item used in the diagnostics:
+// its title, its link or its position in the source.
+func ItemName(index int, title, link string) string {
+	if title = strings.TrimSpace(title); title != "" {
+		return title
+	}
+	if link = strings.TrimSpace(link); link != "" {
+		return link
+	}
+	return fmt.Sprintf("item %d", index+1)
+}
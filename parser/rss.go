@@ -1,46 +1,171 @@
 package parser
 
 import (
+	"context"
 	"fmt"
 	"github.com/mmcdole/gofeed"
+	"io"
 	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
-	"os"
+	"news-aggregator/parser/charset"
+	"strings"
+	"time"
 )
 
 // Rss analyzes RSS sources.
 type Rss struct {
 }
 
-// Parse reads and parses a XML (RSS) file specified by the path and returns a slice of articles.
-func (rss Rss) Parse(path source.PathToFile, name source.Name) ([]news.News, error) {
+// Parse reads and parses a XML (RSS) feed from the reader and returns a slice of articles.
+// The items without both the title and the link are skipped, and the items without the
+// publication date get the updated date, the date of the feed or the fetch time instead.
+// Every skipped or repaired item is listed in the report.
+func (rss Rss) Parse(ctx context.Context, reader io.Reader, name source.Name) ([]news.News, Report, error) {
 
+	var report Report
 	parser := gofeed.NewParser()
-	filename := fmt.Sprintf(string(path))
-	file, err := os.Open(filename)
+	feed, err := parser.Parse(NewContextReader(ctx, charset.NewReader(reader, "")))
 	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		if cerr := file.Close(); cerr != nil {
-			err = fmt.Errorf("cannot close RSS file: %w", cerr)
-		}
-	}()
-
-	feed, err := parser.Parse(file)
-	if err != nil {
-		return nil, err
+		return nil, report, err
 	}
 
 	var newsData []news.News
-	for _, item := range feed.Items {
+	for index, item := range feed.Items {
+		if err := ctx.Err(); err != nil {
+			return nil, report, err
+		}
+		if item == nil {
+			continue
+		}
+		itemName := ItemName(index, item.Title, item.Link)
+		if strings.TrimSpace(item.Title) == "" && strings.TrimSpace(item.Link) == "" {
+			report.Skip(name, itemName, "the item has neither title nor link")
+			continue
+		}
+		date, dateInferred := rssDate(feed, item, name, itemName, &report)
 		newsData = append(newsData, news.News{
-			Title:       news.Title(item.Title),
-			Description: news.Description(item.Description),
-			Link:        news.Link(item.Link),
-			Date:        *item.PublishedParsed,
-			SourceName:  name,
+			ID:           news.NewID(item.GUID, news.Link(item.Link)),
+			Title:        news.Title(item.Title),
+			Description:  news.Description(item.Description),
+			Link:         news.Link(item.Link),
+			Date:         date,
+			DateInferred: dateInferred,
+			SourceName:   name,
+			Authors:      rssAuthors(item),
+			Categories:   item.Categories,
+			Images:       rssImages(item),
+			Content:      news.Content(item.Content),
+			UpdatedAt:    rssUpdated(item),
+			Language:     feed.Language,
 		})
 	}
-	return newsData, nil
+	return newsData, report, nil
+}
+
+// rssDate returns the publication date of the item in UTC and whether it was inferred.
+// The date is normalized by the dates package, the date parsed by gofeed is used if it
+// cannot be normalized. If the item does not have the publication date, the updated date
+// of the item, the date of the feed or the fetch time is used and reported.
+func rssDate(feed *gofeed.Feed, item *gofeed.Item, name source.Name, itemName string, report *Report) (time.Time, bool) {
+	if date, err := normalizeDate(item.Published); err == nil {
+		return date.Time, date.Inferred
+	}
+	if item.PublishedParsed != nil {
+		return item.PublishedParsed.UTC(), false
+	}
+	reason := "the publication date is missing"
+	if strings.TrimSpace(item.Published) != "" {
+		reason = fmt.Sprintf("the publication date %q cannot be parsed", item.Published)
+	}
+
+	if updated := rssUpdated(item); updated != nil {
+		report.Repair(name, itemName, reason, FallbackUpdatedDate)
+		return *updated, true
+	}
+	for _, feedDate := range []string{feed.Published, feed.Updated} {
+		if date, err := normalizeDate(feedDate); err == nil {
+			report.Repair(name, itemName, reason, FallbackFeedDate)
+			return date.Time, true
+		}
+	}
+	for _, feedDate := range []*time.Time{feed.PublishedParsed, feed.UpdatedParsed} {
+		if feedDate != nil {
+			report.Repair(name, itemName, reason, FallbackFeedDate)
+			return feedDate.UTC(), true
+		}
+	}
+	report.Repair(name, itemName, reason, FallbackFetchTime)
+	return now().UTC(), true
+}
+
+// rssAuthors returns the names of the item's authors.
+func rssAuthors(item *gofeed.Item) []string {
+	var authors []string
+	for _, author := range item.Authors {
+		if author != nil && strings.TrimSpace(author.Name) != "" {
+			authors = append(authors, strings.TrimSpace(author.Name))
+		}
+	}
+	return authors
+}
+
+// rssImages returns the links to the item's images from the image element,
+// the image enclosures and the media content and thumbnails.
+func rssImages(item *gofeed.Item) []news.Link {
+	var images []news.Link
+	addImage := func(link string) {
+		link = strings.TrimSpace(link)
+		if link == "" {
+			return
+		}
+		for _, image := range images {
+			if image == news.Link(link) {
+				return
+			}
+		}
+		images = append(images, news.Link(link))
+	}
+
+	if item.Image != nil {
+		addImage(item.Image.URL)
+	}
+	for _, enclosure := range item.Enclosures {
+		if enclosure != nil && strings.HasPrefix(enclosure.Type, "image/") {
+			addImage(enclosure.URL)
+		}
+	}
+	if media, exists := item.Extensions["media"]; exists {
+		for _, element := range []string{"content", "thumbnail"} {
+			for _, extension := range media[element] {
+				medium := extension.Attrs["medium"]
+				if element == "content" && medium != "image" && !strings.HasPrefix(extension.Attrs["type"], "image/") {
+					continue
+				}
+				addImage(extension.Attrs["url"])
+			}
+		}
+	}
+	return images
+}
+
+// rssUpdated returns the date of the item's last update in UTC. The Dublin Core date is used
+// when the feed does not provide the updated date and it differs from the publication date.
+func rssUpdated(item *gofeed.Item) *time.Time {
+	if updated := normalizePointer(item.Updated); updated != nil {
+		return updated
+	}
+	if item.UpdatedParsed != nil {
+		updated := item.UpdatedParsed.UTC()
+		return &updated
+	}
+	if item.DublinCoreExt == nil {
+		return nil
+	}
+	for _, date := range item.DublinCoreExt.Date {
+		updated := normalizePointer(date)
+		if updated != nil && (item.PublishedParsed == nil || !updated.Equal(*item.PublishedParsed)) {
+			return updated
+		}
+	}
+	return nil
 }
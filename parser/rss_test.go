@@ -1,9 +1,12 @@
 package parser
 
 import (
+	"context"
 	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -26,12 +29,14 @@ func TestRss_ParseSource(t *testing.T) {
 			},
 			want: []news.News{
 				{
+					ID:          news.NewID("", "http://example.com/1"),
 					Title:       "Test News 1",
 					Description: "Description 1",
 					Link:        "http://example.com/1",
 					Date:        time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
 					SourceName:  "testrss"},
 				{
+					ID:          news.NewID("", "http://example.com/2"),
 					Title:       "Test News 2",
 					Description: "Description 2",
 					Link:        "http://example.com/2",
@@ -40,13 +45,131 @@ func TestRss_ParseSource(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Parse RSS file with authors, categories, images and content",
+			args: args{
+				path: "../mnt/resources/testdata/rich_rss.xml",
+				name: "testrss",
+			},
+			want: []news.News{
+				{
+					ID:          news.NewID("rich-1", "http://example.com/rich/1"),
+					Title:       "Rich News 1",
+					Description: "Description 1",
+					Link:        "http://example.com/rich/1",
+					Date:        time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+					SourceName:  "testrss",
+					Authors:     []string{"John Doe"},
+					Categories:  []string{"World", "Europe"},
+					Images:      []news.Link{"http://example.com/rich/1.jpg", "http://example.com/rich/1-thumbnail.jpg"},
+					Content:     "<p>Full content 1</p>",
+					UpdatedAt:   timePointer(time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)),
+					Language:    "en-gb",
+				},
+			},
+		},
+		{
+			name: "Parse windows-1251 RSS file",
+			args: args{
+				path: "../mnt/resources/testdata/windows1251_rss.xml",
+				name: "pravda",
+			},
+			want: []news.News{
+				{
+					ID:          news.NewID("", "http://example.com/uk/1"),
+					Title:       "Новини України",
+					Description: "Уряд ухвалив рішення про підтримку ґрунтових доріг",
+					Link:        "http://example.com/uk/1",
+					Date:        time.Date(2024, time.June, 3, 6, 15, 0, 0, time.UTC),
+					SourceName:  "pravda",
+				},
+				{
+					ID:          news.NewID("", "http://example.com/uk/2"),
+					Title:       "Їжак і єнот",
+					Description: "Історія про тварин",
+					Link:        "http://example.com/uk/2",
+					Date:        time.Date(2024, time.June, 4, 7, 0, 0, 0, time.UTC),
+					SourceName:  "pravda",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			rss := Rss{}
-			if got, _ := rss.Parse(tt.args.path, tt.args.name); !reflect.DeepEqual(got, tt.want) {
+			file, err := os.Open(string(tt.args.path))
+			if err != nil {
+				t.Fatalf("Failed to open file: %v", err)
+			}
+			defer file.Close()
+
+			if got, _, _ := rss.Parse(context.Background(), file, tt.args.name); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Parse() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func timePointer(t time.Time) *time.Time {
+	return &t
+}
+
+func TestRss_ParseUndatedItems(t *testing.T) {
+	fetchTime := time.Date(2024, time.June, 5, 9, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fetchTime }
+	defer func() { now = time.Now }()
+
+	file, err := os.Open("../mnt/resources/testdata/undated_rss.xml")
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer file.Close()
+
+	got, report, err := Rss{}.Parse(context.Background(), file, "undated")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	feedDate := time.Date(2024, time.June, 4, 0, 0, 0, 0, time.UTC)
+	if len(got) != 2 || !got[0].Date.Equal(feedDate) || !got[1].Date.Equal(feedDate) || !got[0].DateInferred || !got[1].DateInferred {
+		t.Errorf("Parse() = %v, want two items with the inferred feed date %v", got, feedDate)
+	}
+	wantReport := Report{Diagnostics: []Diagnostic{
+		{Source: "undated", Item: "Undated item", Action: Repaired,
+			Reason: "the publication date is missing", Fallback: FallbackFeedDate},
+		{Source: "undated", Item: "Broken date item", Action: Repaired,
+			Reason: `the publication date "sometime last week" cannot be parsed`, Fallback: FallbackFeedDate},
+		{Source: "undated", Item: "item 3", Action: Skipped, Reason: "the item has neither title nor link"},
+	}}
+	if !reflect.DeepEqual(report, wantReport) {
+		t.Errorf("Parse() report = %v, want %v", report, wantReport)
+	}
+
+	tests := []struct {
+		name         string
+		feed         string
+		wantDate     time.Time
+		wantFallback string
+	}{
+		{
+			name: "Fetch time",
+			feed: `<rss version="2.0"><channel><title>Undated</title>` +
+				`<item><title>Undated item</title><link>http://example.com/undated</link></item></channel></rss>`,
+			wantDate:     fetchTime,
+			wantFallback: FallbackFetchTime,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, report, err := Rss{}.Parse(context.Background(), strings.NewReader(tt.feed), "undated")
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(got) != 1 || !got[0].Date.Equal(tt.wantDate) {
+				t.Errorf("Parse() = %v, want the item with the date %v", got, tt.wantDate)
+			}
+			if len(report.Diagnostics) != 1 || report.Diagnostics[0].Fallback != tt.wantFallback {
+				t.Errorf("Parse() report = %v, want the fallback %v", report, tt.wantFallback)
+			}
+		})
+	}
+}
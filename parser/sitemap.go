@@ -0,0 +1,166 @@
+package parser
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io"
+	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
+	"news-aggregator/fetcher"
+	"news-aggregator/parser/charset"
+	"strings"
+)
+
+// maxSitemapDepth limits the nesting of the sitemap index files.
+const maxSitemapDepth = 3
+
+// Sitemap analyzes the Google News sitemaps. The entries with the news:news element
+// are converted to the news, and the child sitemaps of the sitemap index files
+// are downloaded by the Fetcher, the default one is used if it is not set.
+type Sitemap struct {
+	Fetcher *fetcher.Fetcher
+}
+
+// sitemapDocument is either the urlset with the entries or the sitemap index with the child sitemaps.
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []sitemapURL `xml:"url"`
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+	News    *struct {
+		Publication struct {
+			Name     string `xml:"name"`
+			Language string `xml:"language"`
+		} `xml:"publication"`
+		PublicationDate string `xml:"publication_date"`
+		Title           string `xml:"title"`
+		Keywords        string `xml:"keywords"`
+	} `xml:"news"`
+	Images []struct {
+		Loc string `xml:"loc"`
+	} `xml:"image"`
+}
+
+// Parse reads the sitemap from the reader and returns the news of its entries.
+// The child sitemaps of the sitemap index are parsed too, the ones which
+// cannot be downloaded or parsed are skipped and listed in the report.
+func (sitemap Sitemap) Parse(ctx context.Context, reader io.Reader, name source.Name) ([]news.News, Report, error) {
+	var report Report
+	articles, err := sitemap.parse(ctx, reader, name, 0, &report)
+	return articles, report, err
+}
+
+func (sitemap Sitemap) parse(ctx context.Context, reader io.Reader, name source.Name, depth int, report *Report) ([]news.News, error) {
+	var document sitemapDocument
+	if err := xml.NewDecoder(NewContextReader(ctx, charset.NewReader(reader, ""))).Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to decode sitemap: %w", err)
+	}
+
+	switch document.XMLName.Local {
+	case "urlset":
+		var articles []news.News
+		for index, entry := range document.URLs {
+			if article, ok := entry.toNews(name, index, report); ok {
+				articles = append(articles, article)
+			}
+		}
+		return articles, nil
+	case "sitemapindex":
+		if depth >= maxSitemapDepth {
+			return nil, fmt.Errorf("sitemap index of source %s is nested too deep", name)
+		}
+		var articles []news.News
+		for _, child := range document.Sitemaps {
+			childArticles, err := sitemap.parseChild(ctx, strings.TrimSpace(child.Loc), name, depth+1, report)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				logrus.Warnf("Sitemap: Failed to parse child sitemap %s: %v", child.Loc, err)
+				report.Skip(name, strings.TrimSpace(child.Loc), "the child sitemap cannot be parsed: "+err.Error())
+				continue
+			}
+			articles = append(articles, childArticles...)
+		}
+		return articles, nil
+	default:
+		return nil, errors.New("unknown sitemap root element: " + document.XMLName.Local)
+	}
+}
+
+// parseChild downloads and parses the child sitemap of the sitemap index.
+func (sitemap Sitemap) parseChild(ctx context.Context, url string, name source.Name, depth int, report *Report) ([]news.News, error) {
+	sitemapFetcher := sitemap.Fetcher
+	if sitemapFetcher == nil {
+		sitemapFetcher = fetcher.New(fetcher.DefaultConfig())
+	}
+	response, err := sitemapFetcher.Fetch(ctx, url, fetcher.Validators{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := response.Body.Close(); err != nil {
+			logrus.Error("Sitemap: Failed to close response body: ", err)
+		}
+	}()
+	return sitemap.parse(ctx, response.Body, name, depth, report)
+}
+
+// toNews converts the entry of the sitemap to the news. The entries without
+// the news:news element are not the news and are skipped. The entries without the publication
+// date get the date of the last modification or the fetch time, which is listed in the report.
+func (entry sitemapURL) toNews(name source.Name, index int, report *Report) (news.News, bool) {
+	if entry.News == nil {
+		return news.News{}, false
+	}
+	link := news.Link(strings.TrimSpace(entry.Loc))
+	article := news.News{
+		ID:         news.NewID("", link),
+		Title:      news.Title(strings.TrimSpace(entry.News.Title)),
+		Link:       link,
+		SourceName: name,
+		Language:   strings.TrimSpace(entry.News.Publication.Language),
+	}
+
+	if date, err := normalizeDate(entry.News.PublicationDate); err == nil {
+		article.Date, article.DateInferred = date.Time, date.Inferred
+	} else {
+		itemName := ItemName(index, string(article.Title), string(article.Link))
+		reason := "the publication date is missing"
+		if strings.TrimSpace(entry.News.PublicationDate) != "" {
+			reason = fmt.Sprintf("the publication date %q cannot be parsed", entry.News.PublicationDate)
+		}
+		article.DateInferred = true
+		if updated := normalizePointer(entry.LastMod); updated != nil {
+			article.Date = *updated
+			report.Repair(name, itemName, reason, FallbackUpdatedDate)
+		} else {
+			article.Date = now().UTC()
+			report.Repair(name, itemName, reason, FallbackFetchTime)
+		}
+	}
+	if updated := normalizePointer(entry.LastMod); updated != nil && !updated.Equal(article.Date) {
+		article.UpdatedAt = updated
+	}
+
+	for _, keyword := range strings.Split(entry.News.Keywords, ",") {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			article.Categories = append(article.Categories, keyword)
+		}
+	}
+	for _, image := range entry.Images {
+		if image.Loc = strings.TrimSpace(image.Loc); image.Loc != "" {
+			article.Images = append(article.Images, news.Link(image.Loc))
+		}
+	}
+	return article, true
+}
@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSitemap_Parse(t *testing.T) {
+	file, err := os.Open("../mnt/resources/testdata/news_sitemap.xml")
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer file.Close()
+
+	want := []news.News{
+		{
+			ID:         news.NewID("", "https://example.com/world/story-1"),
+			Title:      "Sitemap News 1",
+			Link:       "https://example.com/world/story-1",
+			Date:       time.Date(2024, time.June, 1, 10, 0, 0, 0, time.UTC),
+			SourceName: "sitemap",
+			Categories: []string{"World", "Ukraine", "Politics"},
+			Images:     []news.Link{"https://example.com/images/1.jpg"},
+			UpdatedAt:  timePointer(time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)),
+			Language:   "en",
+		},
+		{
+			ID:         news.NewID("", "https://example.com/world/story-2"),
+			Title:      "Sitemap News 2",
+			Link:       "https://example.com/world/story-2",
+			Date:       time.Date(2024, time.June, 2, 0, 0, 0, 0, time.UTC),
+			SourceName: "sitemap",
+			Language:   "en",
+		},
+	}
+
+	got, _, err := Sitemap{}.Parse(context.Background(), file, "sitemap")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %v, want %v", got, want)
+	}
+}
+
+func TestSitemap_ParseIndex(t *testing.T) {
+	sitemap, err := os.ReadFile("../mnt/resources/testdata/news_sitemap.xml")
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/news.xml":
+			_, _ = w.Write(sitemap)
+		case "/nested.xml":
+			_, _ = w.Write([]byte(`<sitemapindex><sitemap><loc>` + server.URL + `/news.xml</loc></sitemap></sitemapindex>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	index := `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>` + server.URL + `/news.xml</loc></sitemap>
+  <sitemap><loc>` + server.URL + `/missing.xml</loc></sitemap>
+  <sitemap><loc>` + server.URL + `/nested.xml</loc></sitemap>
+</sitemapindex>`
+
+	got, _, err := Sitemap{}.Parse(context.Background(), strings.NewReader(index), "sitemap")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(got) != 4 {
+		t.Errorf("Parse() quantity = %v, want %v", len(got), 4)
+	}
+
+	if _, _, err := (Sitemap{}).Parse(context.Background(), strings.NewReader("<rss></rss>"), "sitemap"); err == nil {
+		t.Errorf("Parse() expected error for the document which is not a sitemap")
+	}
+	if _, _, err := (Sitemap{}).Parse(context.Background(), strings.NewReader("<urlset>"), source.Name("sitemap")); err == nil {
+		t.Errorf("Parse() expected error for the broken sitemap")
+	}
+}
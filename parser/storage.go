@@ -1,42 +1,36 @@
 package parser
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
-	"os"
 )
 
+// Storage analyzes the news saved to the storage in the JSON format.
 type Storage struct {
 }
 
-func (storage Storage) Parse(path source.PathToFile, name source.Name) ([]news.News, error) {
+// Parse reads the saved news from the reader and returns a slice of news.
+func (storage Storage) Parse(ctx context.Context, reader io.Reader, name source.Name) ([]news.News, Report, error) {
 
-	if _, err := os.Stat(string(path)); os.IsNotExist(err) {
-		return nil, fmt.Errorf("JSON file not found for source: %s", path)
-	}
-
-	jsonFile, err := os.Open(string(path))
-	if err != nil {
-		return nil, fmt.Errorf("failed to open JSON file: %w", err)
-	}
-	defer jsonFile.Close()
-
-	byteValue, err := ioutil.ReadAll(jsonFile)
+	byteValue, err := io.ReadAll(NewContextReader(ctx, reader))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read JSON file: %w", err)
+		return nil, Report{}, fmt.Errorf("failed to read JSON file: %w", err)
 	}
 
 	var articles []news.News
 	if err := json.Unmarshal(byteValue, &articles); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal JSON data: %w", err)
+		return nil, Report{}, fmt.Errorf("failed to unmarshal JSON data: %w", err)
 	}
 
 	for i := range articles {
+		articles[i] = articles[i].WithID()
+		articles[i].Date = articles[i].Date.UTC()
 		articles[i].SourceName = name
 	}
 
-	return articles, nil
+	return articles, Report{}, nil
 }
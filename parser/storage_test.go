@@ -1,31 +1,20 @@
 package parser
 
 import (
+	"context"
 	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
-	"os"
 	"reflect"
+	"strings"
 	"testing"
 )
 
-// Helper function to create a temporary JSON file for testing
-func createTempJSONFile(content string) (string, func(), error) {
-	tmpFile, err := os.CreateTemp("", "test_*.json")
-	if err != nil {
-		return "", nil, err
-	}
-	if _, err := tmpFile.Write([]byte(content)); err != nil {
-		return "", nil, err
-	}
-	if err := tmpFile.Close(); err != nil {
-		return "", nil, err
-	}
-	return tmpFile.Name(), func() { os.Remove(tmpFile.Name()) }, nil
-}
-
 func TestParse(t *testing.T) {
+	cancelledContext, cancel := context.WithCancel(context.Background())
+	cancel()
+
 	type args struct {
-		path source.PathToFile
+		ctx  context.Context
 		name source.Name
 	}
 
@@ -39,21 +28,21 @@ func TestParse(t *testing.T) {
 		{
 			name: "Parse valid JSON file",
 			args: args{
-				path: "test_valid.json",
+				ctx:  context.Background(),
 				name: "testjson",
 			},
 			content: `[{"title": "Test News 1", "description": "Description 1", "url": "http://example.com/1"},
 					  {"title": "Test News 2", "description": "Description 2", "url": "http://example.com/2"}]`,
 			want: []news.News{
-				{Title: "Test News 1", Description: "Description 1", Link: "http://example.com/1", SourceName: "testjson"},
-				{Title: "Test News 2", Description: "Description 2", Link: "http://example.com/2", SourceName: "testjson"},
+				{ID: news.NewID("", "http://example.com/1"), Title: "Test News 1", Description: "Description 1", Link: "http://example.com/1", SourceName: "testjson"},
+				{ID: news.NewID("", "http://example.com/2"), Title: "Test News 2", Description: "Description 2", Link: "http://example.com/2", SourceName: "testjson"},
 			},
 			wantErr: false,
 		},
 		{
-			name: "File not found",
+			name: "Empty content",
 			args: args{
-				path: "non_existent_file.json",
+				ctx:  context.Background(),
 				name: "testjson",
 			},
 			content: "",
@@ -63,27 +52,29 @@ func TestParse(t *testing.T) {
 		{
 			name: "Invalid JSON format",
 			args: args{
-				path: "test_invalid.json",
+				ctx:  context.Background(),
 				name: "testjson",
 			},
 			content: `[{ "title": "Invalid JSON" }`,
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "Cancelled context",
+			args: args{
+				ctx:  cancelledContext,
+				name: "testjson",
+			},
+			content: `[{"title": "Test News 1", "description": "Description 1", "url": "http://example.com/1"}]`,
+			want:    nil,
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tmpFilePath, cleanup, err := createTempJSONFile(tt.content)
-			if err != nil {
-				t.Fatalf("Failed to create temp file: %v", err)
-			}
-			defer cleanup()
-
-			tt.args.path = source.PathToFile(tmpFilePath)
-
 			storage := Storage{}
-			got, err := storage.Parse(tt.args.path, tt.args.name)
+			got, _, err := storage.Parse(tt.args.ctx, strings.NewReader(tt.content), tt.args.name)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
 				return
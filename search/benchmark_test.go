@@ -0,0 +1,87 @@
+package search
+
+import (
+	"fmt"
+	"news-aggregator/entity/news"
+	"news-aggregator/filter"
+	"os"
+	"testing"
+)
+
+var benchmarkWords = []string{
+	"ukraine", "kyiv", "alert", "missile", "election", "football", "market", "economy",
+	"weather", "energy", "drone", "president", "parliament", "vote", "match", "storm",
+}
+
+// benchmarkArticles returns the news with the titles and the descriptions made of the benchmark words.
+// The news have the IDs like the news of the parsed sources.
+func benchmarkArticles(count int) []news.News {
+	articles := make([]news.News, count)
+	for i := range articles {
+		title := ""
+		description := ""
+		for j := 0; j < 4; j++ {
+			title += benchmarkWords[(i*7+j*3)%len(benchmarkWords)] + " "
+		}
+		for j := 0; j < 20; j++ {
+			description += benchmarkWords[(i*5+j*11)%len(benchmarkWords)] + "s "
+		}
+		articles[i] = news.News{
+			Title:       news.Title(title),
+			Description: news.Description(description),
+			Link:        news.Link(fmt.Sprintf("https://example.com/%d", i)),
+		}.WithID()
+	}
+	return articles
+}
+
+// silenceOutput drops the messages printed by the filters when nothing is found.
+func silenceOutput(b *testing.B) {
+	stdout := os.Stdout
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	os.Stdout = devNull
+	b.Cleanup(func() {
+		os.Stdout = stdout
+		devNull.Close()
+	})
+}
+
+func BenchmarkSearch(b *testing.B) {
+	for _, count := range []int{1000, 10000} {
+		articles := benchmarkArticles(count)
+		index := New(nil)
+		index.Update("benchmark", articles)
+		keywords := []string{"missile", "storm"}
+
+		b.Run(fmt.Sprintf("ByKeyword/%d", count), func(b *testing.B) {
+			silenceOutput(b)
+			keywordFilter := filter.ByKeyword{Keywords: keywords}
+			for i := 0; i < b.N; i++ {
+				keywordFilter.Filter(articles)
+			}
+		})
+		b.Run(fmt.Sprintf("ByRelevance/%d", count), func(b *testing.B) {
+			silenceOutput(b)
+			relevanceFilter := ByRelevance{Index: index, Keywords: keywords}
+			for i := 0; i < b.N; i++ {
+				relevanceFilter.Filter(articles)
+			}
+		})
+		b.Run(fmt.Sprintf("Index/%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				index.Search("missile storm")
+			}
+		})
+	}
+}
+
+func BenchmarkIndex_Update(b *testing.B) {
+	articles := benchmarkArticles(1000)
+	index := New(nil)
+	for i := 0; i < b.N; i++ {
+		index.Update("benchmark", articles)
+	}
+}
@@ -0,0 +1,111 @@
+package search
+
+import (
+	"math"
+	"news-aggregator/analysis"
+	"news-aggregator/entity/news"
+)
+
+// The parameters of BM25: k1 limits the growth of the score with the frequency of the term
+// and b sets how much the score is normalized by the length of the news.
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// queryTerm is the term of the query with its inverse document frequency.
+type queryTerm struct {
+	text string
+	idf  float64
+}
+
+// scorer computes the BM25 scores with the statistics of the index.
+type scorer struct {
+	terms         []queryTerm
+	averageLength float64
+}
+
+// newScorer returns the scorer of the unique terms, the read lock must be held.
+func (index *Index) newScorer(terms []string) scorer {
+	count := float64(len(index.documents))
+	newsScorer := scorer{averageLength: 1}
+	if len(index.documents) > 0 && index.totalLength > 0 {
+		newsScorer.averageLength = float64(index.totalLength) / count
+	}
+	seen := make(map[string]struct{})
+	for _, term := range terms {
+		if _, exists := seen[term]; exists {
+			continue
+		}
+		seen[term] = struct{}{}
+		frequency := float64(len(index.postings[term]))
+		newsScorer.terms = append(newsScorer.terms, queryTerm{
+			text: term,
+			idf:  math.Log(1 + (count-frequency+0.5)/(frequency+0.5)),
+		})
+	}
+	return newsScorer
+}
+
+func (newsScorer scorer) termScore(term queryTerm, frequency, length int) float64 {
+	tf := float64(frequency)
+	return term.idf * tf * (k1 + 1) / (tf + k1*(1-b+b*float64(length)/newsScorer.averageLength))
+}
+
+// score returns the BM25 score of the news with the passed frequencies of the terms.
+func (newsScorer scorer) score(frequencies map[string]int, length int) float64 {
+	var score float64
+	for _, term := range newsScorer.terms {
+		if frequency := frequencies[term.text]; frequency > 0 {
+			score += newsScorer.termScore(term, frequency, length)
+		}
+	}
+	return score
+}
+
+// ByRelevance filters the slice of news by the keywords and their synonyms and sets the
+// BM25 scores of the matching news. The terms of the indexed news are taken from the Index, the
+// rest of the news are analyzed on the fly. The keywords are expanded by the synonyms of the Index.
+// If the Index is nil, the statistics of the terms are computed from the filtered news and the
+// keywords are not expanded.
+type ByRelevance struct {
+	Index    *Index
+	Keywords []string
+}
+
+// Filter returns the news which contain at least one keyword with their scores in the original order.
+func (relevanceFilter ByRelevance) Filter(articles []news.News) []news.News {
+	index := relevanceFilter.Index
+	if index == nil {
+		index = New(nil)
+		index.Update("", articles)
+	} else {
+		index.mutex.Lock()
+		index.refreshLocked()
+		index.mutex.Unlock()
+	}
+
+	index.mutex.RLock()
+	defer index.mutex.RUnlock()
+	newsScorer := index.newScorer(analysis.QueryTerms(index.synonyms, relevanceFilter.Keywords...))
+
+	var matchingNews []news.News
+	for _, article := range articles {
+		var score float64
+		if doc, indexed := index.documents[article.WithID().ID]; indexed {
+			score = newsScorer.score(doc.Terms, doc.length)
+		} else {
+			frequencies := termFrequencies(article)
+			length := 0
+			for _, frequency := range frequencies {
+				length += frequency
+			}
+			score = newsScorer.score(frequencies, length)
+		}
+		if score > 0 {
+			article.Score = score
+			matchingNews = append(matchingNews, article)
+		}
+	}
+	return matchingNews
+}
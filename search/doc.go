@@ -0,0 +1,9 @@
+// Package search provides the full-text search of the stored news ranked by BM25.
+//
+// Index is the inverted index of the stemmed terms of the titles and the descriptions of
+// the news. Open loads the index from its file next to the JSON storage of the news or builds
+// it from the stored news, and NewIndexedStorage updates and saves the index whenever the news
+// of the source are saved, so the index is updated incrementally by the news updater and the
+// web server. ByRelevance is the filter which keeps the news with the keywords and sets their
+// BM25 scores, it is used by the clients when the news are sorted by relevance.
+package search
@@ -0,0 +1,339 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"news-aggregator/analysis"
+	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
+	"news-aggregator/storage"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// formatVersion is the version of the format of the index file. The index of the older
+// version has the terms of the other analysis, so it is built again.
+const formatVersion = 2
+
+// errOutdatedVersion is returned when the file has the index of the older version.
+var errOutdatedVersion = errors.New("outdated version of search index")
+
+// Result is the news found by the index with its relevance score.
+type Result struct {
+	ID     news.ID
+	Source source.Name
+	Score  float64
+}
+
+// document is the indexed news: the name of its source and the frequencies of its terms.
+type document struct {
+	Source source.Name    `json:"source"`
+	Terms  map[string]int `json:"terms"`
+	length int
+}
+
+// indexFile is the content of the file of the index.
+type indexFile struct {
+	Version   int                  `json:"version"`
+	Documents map[news.ID]document `json:"documents"`
+}
+
+// Index is the inverted index of the titles and the descriptions of the stored news.
+// The index is saved to its file as the terms of every news, and the postings are built
+// when it is loaded. The index is reloaded when its file is changed by the other process,
+// like the news updater. It is safe for the concurrent use.
+type Index struct {
+	mutex       sync.RWMutex
+	path        string
+	modTime     time.Time
+	documents   map[news.ID]document
+	postings    map[string]map[news.ID]int
+	sources     map[source.Name]map[news.ID]struct{}
+	totalLength int
+	synonyms    *analysis.Synonyms
+}
+
+// New returns the empty index which is kept only in memory. The words of the search are
+// expanded by the dictionary of synonyms, which may be nil.
+func New(synonyms *analysis.Synonyms) *Index {
+	return &Index{
+		synonyms:  synonyms,
+		documents: make(map[news.ID]document),
+		postings:  make(map[string]map[news.ID]int),
+		sources:   make(map[source.Name]map[news.ID]struct{}),
+	}
+}
+
+// Open loads the index from the file. If the file does not exist, the index is built
+// from the news of the sources of the storage type and saved to the file. Building stops with
+// the error of the context when the context is done. The words of the search are expanded by
+// the dictionary of synonyms, which may be nil.
+func Open(ctx context.Context, path string, resourcesStorage storage.Storage, synonyms *analysis.Synonyms) (*Index, error) {
+	index := New(synonyms)
+	index.path = path
+	loaded, err := index.reload()
+	if errors.Is(err, errOutdatedVersion) {
+		logrus.Info("Search index: The index in ", path, " is outdated and is built again")
+	} else if err != nil {
+		return nil, err
+	}
+	if loaded {
+		logrus.Info("Search index: Loaded from ", path, ", documents: ", index.Len())
+		return index, nil
+	}
+
+	sources, err := resourcesStorage.GetSources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sources for search index: %w", err)
+	}
+	for _, currentSource := range sources {
+		if currentSource.SourceType != source.STORAGE {
+			continue
+		}
+		articles, err := resourcesStorage.GetNews(ctx, string(currentSource.PathToFile))
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			logrus.Error("Search index: Failed to get news of the source ", currentSource.Name, ": ", err)
+			continue
+		}
+		index.Update(currentSource.Name, articles)
+	}
+	if err := index.Save(); err != nil {
+		return nil, err
+	}
+	logrus.Info("Search index: Built and saved to ", path, ", documents: ", index.Len())
+	return index, nil
+}
+
+// Len returns the number of the indexed news.
+func (index *Index) Len() int {
+	index.mutex.RLock()
+	defer index.mutex.RUnlock()
+	return len(index.documents)
+}
+
+// Update replaces the indexed news of the source with the passed news.
+func (index *Index) Update(sourceName source.Name, articles []news.News) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+	index.refreshLocked()
+	index.removeLocked(sourceName)
+	for _, article := range articles {
+		article = article.WithID()
+		if article.ID == "" {
+			continue
+		}
+		index.addLocked(article.ID, document{Source: sourceName, Terms: termFrequencies(article)})
+	}
+}
+
+// Remove drops the indexed news of the source.
+func (index *Index) Remove(sourceName source.Name) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+	index.refreshLocked()
+	index.removeLocked(sourceName)
+}
+
+// Rename moves the indexed news of the source to its new name.
+func (index *Index) Rename(currentName, newName source.Name) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+	index.refreshLocked()
+	ids := index.sources[currentName]
+	if len(ids) == 0 || currentName == newName {
+		return
+	}
+	for id := range ids {
+		doc := index.documents[id]
+		doc.Source = newName
+		index.documents[id] = doc
+	}
+	delete(index.sources, currentName)
+	index.sources[newName] = ids
+}
+
+// Search returns the news which contain at least one term of the query ordered by their
+// BM25 score from the highest. The words of the query are expanded by their synonyms.
+func (index *Index) Search(query string) []Result {
+	index.mutex.Lock()
+	index.refreshLocked()
+	index.mutex.Unlock()
+
+	index.mutex.RLock()
+	defer index.mutex.RUnlock()
+	scorer := index.newScorer(analysis.QueryTerms(index.synonyms, analysis.Words(query)...))
+	scores := make(map[news.ID]float64)
+	for _, term := range scorer.terms {
+		for id, frequency := range index.postings[term.text] {
+			scores[id] += scorer.termScore(term, frequency, index.documents[id].length)
+		}
+	}
+
+	results := make([]Result, 0, len(scores))
+	for id, score := range scores {
+		results = append(results, Result{ID: id, Source: index.documents[id].Source, Score: score})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+	return results
+}
+
+// Save writes the index to its file. The index which is kept only in memory is not saved.
+// The file is replaced atomically, so the other processes never read the partial index.
+func (index *Index) Save() error {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+	if index.path == "" {
+		return nil
+	}
+
+	content, err := json.Marshal(indexFile{Version: formatVersion, Documents: index.documents})
+	if err != nil {
+		return fmt.Errorf("failed to encode search index: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(index.path), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create directory of search index: %w", err)
+	}
+	temporaryFile, err := os.CreateTemp(filepath.Dir(index.path), filepath.Base(index.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create search index file: %w", err)
+	}
+	defer os.Remove(temporaryFile.Name())
+	if _, err := temporaryFile.Write(content); err != nil {
+		temporaryFile.Close()
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	if err := temporaryFile.Close(); err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	if err := os.Rename(temporaryFile.Name(), index.path); err != nil {
+		return fmt.Errorf("failed to replace search index: %w", err)
+	}
+	if info, err := os.Stat(index.path); err == nil {
+		index.modTime = info.ModTime()
+	}
+	return nil
+}
+
+// refreshLocked reloads the index if its file was changed by the other process.
+// The errors are logged and the current index is kept. The write lock must be held.
+func (index *Index) refreshLocked() {
+	if index.path == "" {
+		return
+	}
+	info, err := os.Stat(index.path)
+	if err != nil || !info.ModTime().After(index.modTime) {
+		return
+	}
+	if _, err := index.reloadLocked(); err != nil {
+		logrus.Error("Search index: Failed to reload ", index.path, ": ", err)
+	}
+}
+
+// reload loads the index from its file and reports whether the file exists.
+func (index *Index) reload() (bool, error) {
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+	return index.reloadLocked()
+}
+
+func (index *Index) reloadLocked() (bool, error) {
+	file, err := os.Open(index.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to open search index: %w", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return false, fmt.Errorf("failed to open search index: %w", err)
+	}
+
+	var content indexFile
+	if err := json.NewDecoder(file).Decode(&content); err != nil {
+		return false, fmt.Errorf("failed to decode search index: %w", err)
+	}
+	if content.Version < formatVersion {
+		return false, errOutdatedVersion
+	}
+	if content.Version != formatVersion {
+		return false, fmt.Errorf("unsupported version of search index: %d", content.Version)
+	}
+
+	index.documents = make(map[news.ID]document, len(content.Documents))
+	index.postings = make(map[string]map[news.ID]int)
+	index.sources = make(map[source.Name]map[news.ID]struct{})
+	index.totalLength = 0
+	for id, doc := range content.Documents {
+		index.addLocked(id, doc)
+	}
+	index.modTime = info.ModTime()
+	return true, nil
+}
+
+// addLocked adds the document to the index, the document with the same ID is replaced.
+func (index *Index) addLocked(id news.ID, doc document) {
+	if _, exists := index.documents[id]; exists {
+		index.removeDocumentLocked(id)
+	}
+	doc.length = 0
+	for term, frequency := range doc.Terms {
+		doc.length += frequency
+		if index.postings[term] == nil {
+			index.postings[term] = make(map[news.ID]int)
+		}
+		index.postings[term][id] = frequency
+	}
+	index.documents[id] = doc
+	index.totalLength += doc.length
+	if index.sources[doc.Source] == nil {
+		index.sources[doc.Source] = make(map[news.ID]struct{})
+	}
+	index.sources[doc.Source][id] = struct{}{}
+}
+
+func (index *Index) removeLocked(sourceName source.Name) {
+	for id := range index.sources[sourceName] {
+		index.removeDocumentLocked(id)
+	}
+	delete(index.sources, sourceName)
+}
+
+func (index *Index) removeDocumentLocked(id news.ID) {
+	doc := index.documents[id]
+	for term := range doc.Terms {
+		delete(index.postings[term], id)
+		if len(index.postings[term]) == 0 {
+			delete(index.postings, term)
+		}
+	}
+	delete(index.documents, id)
+	delete(index.sources[doc.Source], id)
+	index.totalLength -= doc.length
+}
+
+// termFrequencies returns the frequencies of the terms of the title and the description of the news
+// in the language of the news.
+func termFrequencies(article news.News) map[string]int {
+	frequencies := make(map[string]int)
+	text := string(article.Title) + " " + string(article.Description)
+	for _, term := range analysis.Terms(text, analysis.LanguageOf(article)) {
+		frequencies[term]++
+	}
+	return frequencies
+}
@@ -0,0 +1,189 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"news-aggregator/analysis"
+	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
+	client "news-aggregator/storage/mock_aggregator"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testArticles = []news.News{
+	{Title: "Air alert in Kyiv", Description: "The missiles were launched at Kyiv", Link: "https://example.com/1"},
+	{Title: "Elections", Description: "Ukraine is discussed by the candidates of the elections", Link: "https://example.com/2"},
+	{Title: "Drones", Description: "The air alert was announced in the regions", Link: "https://example.com/3"},
+	{Title: "Football", Description: "Results of the match", Link: "https://example.com/4"},
+}
+
+func ids(results []Result) []news.ID {
+	var resultIDs []news.ID
+	for _, result := range results {
+		resultIDs = append(resultIDs, result.ID)
+	}
+	return resultIDs
+}
+
+func id(article news.News) news.ID {
+	return article.WithID().ID
+}
+
+func TestIndex_Search(t *testing.T) {
+	index := New(nil)
+	index.Update("pravda", testArticles[:2])
+	index.Update("bbc", testArticles[2:])
+
+	results := index.Search("Kyiv alerts")
+	assert.Equal(t, []news.ID{id(testArticles[0]), id(testArticles[2])}, ids(results),
+		"the news with more matching terms must be ranked higher")
+	assert.Equal(t, source.Name("pravda"), results[0].Source)
+	assert.Greater(t, results[0].Score, results[1].Score)
+
+	assert.Empty(t, index.Search("basketball"))
+	assert.Equal(t, []news.ID{id(testArticles[1])}, ids(index.Search("election")))
+
+	index.Update("pravda", testArticles[1:2])
+	assert.Equal(t, []news.ID{id(testArticles[2])}, ids(index.Search("kyiv alert")),
+		"the news of the source must be replaced by the update")
+
+	index.Rename("bbc", "bbc-world")
+	assert.Equal(t, source.Name("bbc-world"), index.Search("drones")[0].Source)
+
+	index.Remove("bbc-world")
+	assert.Empty(t, index.Search("drones"))
+	assert.Equal(t, 1, index.Len())
+}
+
+func TestIndex_SearchSynonyms(t *testing.T) {
+	index := New(analysis.NewSynonyms([][]string{{"kyiv", "kiev"}}))
+	index.Update("pravda", testArticles)
+
+	assert.Equal(t, []news.ID{id(testArticles[0])}, ids(index.Search("Kiev")))
+	filtered := ByRelevance{Index: index, Keywords: []string{"kiev"}}.Filter(testArticles)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, id(testArticles[0]), id(filtered[0]))
+
+	withoutSynonyms := New(nil)
+	withoutSynonyms.Update("pravda", testArticles)
+	assert.Empty(t, withoutSynonyms.Search("Kiev"))
+}
+
+func TestIndex_SaveAndOpen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	directory := t.TempDir()
+	path := filepath.Join(directory, "search_index.json")
+
+	mockStorage := client.NewMockStorage(ctrl)
+	mockStorage.EXPECT().GetSources(gomock.Any()).Return([]source.Source{
+		{Name: "pravda", SourceType: source.STORAGE, PathToFile: "pravda.json"},
+		{Name: "bbc", SourceType: source.RSS, PathToFile: "bbc.xml"},
+		{Name: "broken", SourceType: source.STORAGE, PathToFile: "broken.json"},
+	}, nil)
+	mockStorage.EXPECT().GetNews(gomock.Any(), "pravda.json").Return(testArticles, nil)
+	mockStorage.EXPECT().GetNews(gomock.Any(), "broken.json").Return(nil, errors.New("decode error"))
+
+	built, err := Open(context.Background(), path, mockStorage, nil)
+	require.NoError(t, err)
+	assert.Equal(t, len(testArticles), built.Len())
+	_, err = os.Stat(path)
+	require.NoError(t, err, "the built index must be saved")
+
+	loaded, err := Open(context.Background(), path, mockStorage, nil)
+	require.NoError(t, err)
+	assert.Equal(t, built.Search("air alert"), loaded.Search("air alert"))
+
+	built.Update("pravda", testArticles[3:])
+	require.NoError(t, built.Save())
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, future, future))
+	assert.Equal(t, []news.ID{id(testArticles[3])}, ids(loaded.Search("football match")),
+		"the index must be reloaded when its file is changed")
+	assert.Equal(t, 1, loaded.Len())
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 99}`), 0o644))
+	_, err = Open(context.Background(), path, mockStorage, nil)
+	assert.Error(t, err)
+}
+
+func TestByRelevance_Filter(t *testing.T) {
+	index := New(nil)
+	index.Update("pravda", testArticles)
+	notIndexed := news.News{Title: "Kyiv", Description: "The alert in Kyiv and the alert in the region", Link: "https://example.com/5"}
+	articles := append([]news.News{notIndexed}, testArticles...)
+
+	tests := []struct {
+		name     string
+		index    *Index
+		keywords []string
+		want     []news.ID
+	}{
+		{
+			name:     "Indexed and not indexed news",
+			index:    index,
+			keywords: []string{"kyiv", "alert"},
+			want:     []news.ID{id(notIndexed), id(testArticles[0]), id(testArticles[2])},
+		},
+		{
+			name:     "Without index",
+			keywords: []string{"elections"},
+			want:     []news.ID{id(testArticles[1])},
+		},
+		{
+			name:     "No matches",
+			index:    index,
+			keywords: []string{"basketball"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ByRelevance{Index: tt.index, Keywords: tt.keywords}.Filter(articles)
+			var gotIDs []news.ID
+			for _, article := range got {
+				assert.Greater(t, article.Score, 0.0)
+				gotIDs = append(gotIDs, id(article))
+			}
+			assert.Equal(t, tt.want, gotIDs)
+		})
+	}
+}
+
+func TestIndexedStorage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	path := filepath.Join(t.TempDir(), "search_index.json")
+	index := New(nil)
+	index.path = path
+	mockStorage := client.NewMockStorage(ctrl)
+	indexed := NewIndexedStorage(mockStorage, index)
+
+	pravda := source.Source{Name: "pravda", SourceType: source.STORAGE}
+	mockStorage.EXPECT().SaveNews(gomock.Any(), pravda, testArticles).Return(pravda, nil)
+	_, err := indexed.SaveNews(context.Background(), pravda, testArticles)
+	require.NoError(t, err)
+	assert.Len(t, index.Search("alert"), 2)
+	_, err = os.Stat(path)
+	assert.NoError(t, err, "the index must be saved after the news are saved")
+
+	mockStorage.EXPECT().SaveNews(gomock.Any(), pravda, testArticles[3:]).Return(source.Source{}, errors.New("write error"))
+	_, err = indexed.SaveNews(context.Background(), pravda, testArticles[3:])
+	assert.Error(t, err)
+	assert.Len(t, index.Search("alert"), 2, "the index must not be changed when the news are not saved")
+
+	renamed := source.Source{Name: "ukrainska-pravda", SourceType: source.STORAGE}
+	mockStorage.EXPECT().UpdateSource(gomock.Any(), renamed, "pravda").Return(nil)
+	require.NoError(t, indexed.UpdateSource(context.Background(), renamed, "pravda"))
+	assert.Equal(t, source.Name("ukrainska-pravda"), index.Search("alert")[0].Source)
+
+	mockStorage.EXPECT().DeleteSourceByName(gomock.Any(), source.Name("ukrainska-pravda")).Return(nil)
+	require.NoError(t, indexed.DeleteSourceByName(context.Background(), "ukrainska-pravda"))
+	assert.Zero(t, index.Len())
+}
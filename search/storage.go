@@ -0,0 +1,63 @@
+package search
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
+	"news-aggregator/storage"
+)
+
+// indexedStorage updates the index when the news of the sources are saved or the sources
+// are renamed or deleted.
+type indexedStorage struct {
+	storage.Storage
+	index *Index
+}
+
+// NewIndexedStorage returns the storage which keeps the index up to date with the saved news.
+// The index is saved to its file after every change.
+func NewIndexedStorage(resourcesStorage storage.Storage, index *Index) storage.Storage {
+	return &indexedStorage{Storage: resourcesStorage, index: index}
+}
+
+// SaveNews saves the news and replaces the indexed news of the source with them.
+func (indexed *indexedStorage) SaveNews(ctx context.Context, providedSource source.Source, articles []news.News) (source.Source, error) {
+	savedSource, err := indexed.Storage.SaveNews(ctx, providedSource, articles)
+	if err != nil {
+		return savedSource, err
+	}
+	indexed.index.Update(savedSource.Name, news.RemoveDuplicates(articles))
+	indexed.save()
+	return savedSource, nil
+}
+
+// DeleteSourceByName deletes the source and its indexed news.
+func (indexed *indexedStorage) DeleteSourceByName(ctx context.Context, name source.Name) error {
+	if err := indexed.Storage.DeleteSourceByName(ctx, name); err != nil {
+		return err
+	}
+	indexed.index.Remove(name)
+	indexed.save()
+	return nil
+}
+
+// UpdateSource updates the source and moves its indexed news to the new name.
+func (indexed *indexedStorage) UpdateSource(ctx context.Context, updatedSource source.Source, currentName string) error {
+	if err := indexed.Storage.UpdateSource(ctx, updatedSource, currentName); err != nil {
+		return err
+	}
+	if updatedSource.Name != "" && updatedSource.Name != source.Name(currentName) {
+		indexed.index.Rename(source.Name(currentName), updatedSource.Name)
+		indexed.save()
+	}
+	return nil
+}
+
+// save writes the index to its file. The news are already saved, so the error is only logged
+// and the index is built again from the news when its file cannot be loaded.
+func (indexed *indexedStorage) save() {
+	if err := indexed.index.Save(); err != nil {
+		logrus.Error("Search index: Failed to save: ", err)
+	}
+}
@@ -4,14 +4,16 @@ import (
 	"errors"
 	"github.com/sirupsen/logrus"
 	"news-aggregator/entity/news"
-	"sort"
 	"strings"
 )
 
+// DateSorter sorts the news by the asc, desc or relevance parameter. It is the shortcut of the
+// MultiKeySorter with the date and the relevance keys, the other keys are not supported.
 type DateSorter struct {
 }
 
-// SortNews sorts news by ASC or DESC
+// SortNews sorts news by ASC, DESC or relevance. The news with the same score
+// are sorted by date from the newest.
 func (DateSorter) SortNews(news []news.News, sortBy string) ([]news.News, error) {
 
 	logrus.Info("DateSorter: sorting articles by " + sortBy)
@@ -20,17 +22,8 @@ func (DateSorter) SortNews(news []news.News, sortBy string) ([]news.News, error)
 		return news, nil
 	}
 
-	var sortingFunctions = map[string]func(i, j int) bool{
-		"asc": func(i, j int) bool {
-			return news[i].Date.Before(news[j].Date)
-		},
-		"desc": func(i, j int) bool {
-			return news[i].Date.After(news[j].Date)
-		},
-	}
-	if sortingFunctions[lowerCaseSortParameter] != nil {
-		sort.Slice(news, sortingFunctions[lowerCaseSortParameter])
-		return news, nil
+	if _, exists := aliases[lowerCaseSortParameter]; exists {
+		return MultiKeySorter{}.SortNews(news, lowerCaseSortParameter)
 	}
 
 	return nil, errors.New("wrong sorting parameter: " + sortBy)
@@ -10,7 +10,9 @@ func TestDateSorter_SortArticle(t *testing.T) {
 	articles := []news.News{
 		{Title: "News 1", Date: time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)},
 		{Title: "News 2", Date: time.Date(2023, 7, 2, 0, 0, 0, 0, time.UTC)},
-		{Title: "News 3", Date: time.Date(2023, 7, 3, 0, 0, 0, 0, time.UTC)},
+		{Title: "News 3", Date: time.Date(2023, 7, 3, 0, 0, 0, 0, time.UTC), Score: 1.5},
+		{Title: "News 4", Date: time.Date(2023, 7, 4, 0, 0, 0, 0, time.UTC), Score: 2.5},
+		{Title: "News 5", Date: time.Date(2023, 7, 5, 0, 0, 0, 0, time.UTC), Score: 1.5},
 	}
 
 	dateSorter := DateSorter{}
@@ -24,12 +26,17 @@ func TestDateSorter_SortArticle(t *testing.T) {
 		{
 			name:   "ascending",
 			sortBy: "asc",
-			want:   []string{"News 1", "News 2", "News 3"},
+			want:   []string{"News 1", "News 2", "News 3", "News 4", "News 5"},
 		},
 		{
 			name:   "descending",
 			sortBy: "desc",
-			want:   []string{"News 3", "News 2", "News 1"},
+			want:   []string{"News 5", "News 4", "News 3", "News 2", "News 1"},
+		},
+		{
+			name:   "relevance",
+			sortBy: "Relevance",
+			want:   []string{"News 4", "News 5", "News 3", "News 2", "News 1"},
 		},
 		{
 			name:      "invalid parameter",
@@ -1,5 +1,6 @@
-// Package sorter provides functionality for sorting articles by date.
-// It supports sorting in ascending and descending order.
-// The sorter package defines a DateSorter struct that implements the SortNews method,
-// which sorts a slice of news.News based on the provided sort order
+// Package sorter provides functionality for sorting articles.
+// The MultiKeySorter sorts the news by the several keys like "source:asc,date:desc,title:asc",
+// the keys are date, title, source, relevance and length, and it is used by the clients.
+// The DateSorter is the shortcut of the MultiKeySorter which supports only the asc and desc
+// order by date and the relevance order by the scores of the search.
 package sorter
@@ -0,0 +1,170 @@
+package sorter
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"news-aggregator/entity/news"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// The keys of the news which the MultiKeySorter sorts by. Relevance sorts the news by their
+// search score from the highest.
+const (
+	Date      = "date"
+	Title     = "title"
+	Source    = "source"
+	Relevance = "relevance"
+	Length    = "length"
+)
+
+// The directions of the sorting by the key.
+const (
+	Ascending  = "asc"
+	Descending = "desc"
+)
+
+// keys are the supported keys in the order they are listed in the errors.
+var keys = []string{Date, Title, Source, Relevance, Length}
+
+// aliases are the sorting parameters of the DateSorter, they are also supported by the
+// MultiKeySorter for the compatibility.
+// The news sorted by relevance are sorted by date from the newest when their scores are equal.
+var aliases = map[string]string{
+	Ascending:  Date + ":" + Ascending,
+	Descending: Date + ":" + Descending,
+	Relevance:  Relevance + ":" + Descending + "," + Date + ":" + Descending,
+}
+
+// Key is the key of the news with the direction of the sorting by it.
+type Key struct {
+	Name       string
+	Descending bool
+}
+
+// MultiKeySorter sorts the news by the comma-separated keys with the optional directions like
+// "source:asc,date:desc,title". The news with the equal first key are sorted by the next key and
+// the news with all keys equal are kept in their order. The keys are date, title, source,
+// relevance and length, the relevance is sorted from the highest score by default and the
+// other keys from the lowest. The asc, desc and relevance parameters of the DateSorter
+// are also supported.
+type MultiKeySorter struct {
+}
+
+// ParseKeys returns the keys of the sorting parameter. The error lists the supported keys if the
+// key is unknown.
+func ParseKeys(sortBy string) ([]Key, error) {
+	spec := strings.ToLower(strings.TrimSpace(sortBy))
+	if alias, exists := aliases[spec]; exists {
+		spec = alias
+	}
+	var parsed []Key
+	for _, part := range strings.Split(spec, ",") {
+		name, direction, hasDirection := strings.Cut(strings.TrimSpace(part), ":")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, errors.New("wrong sorting parameter, the key is missing: " + sortBy)
+		}
+		if !slices.Contains(keys, name) {
+			return nil, fmt.Errorf("unknown sort key %q, supported keys: %s", name, strings.Join(keys, ", "))
+		}
+		key := Key{Name: name, Descending: name == Relevance}
+		if hasDirection {
+			switch strings.TrimSpace(direction) {
+			case Ascending:
+				key.Descending = false
+			case Descending:
+				key.Descending = true
+			default:
+				return nil, fmt.Errorf("wrong sort direction %q of the key %s, must be asc or desc", direction, name)
+			}
+		}
+		if slices.ContainsFunc(parsed, func(other Key) bool { return other.Name == name }) {
+			return nil, fmt.Errorf("sort key %q is repeated", name)
+		}
+		parsed = append(parsed, key)
+	}
+	return parsed, nil
+}
+
+// SortsBy reports whether the valid sorting parameter has the key.
+func SortsBy(sortBy, key string) bool {
+	parsed, err := ParseKeys(sortBy)
+	if err != nil {
+		return false
+	}
+	return slices.ContainsFunc(parsed, func(current Key) bool { return current.Name == key })
+}
+
+// SortNews sorts the news by the keys of the sortBy parameter with the stable sort.
+// The news are returned unchanged if the parameter is empty.
+func (MultiKeySorter) SortNews(articles []news.News, sortBy string) ([]news.News, error) {
+	logrus.Info("MultiKeySorter: sorting articles by " + sortBy)
+	if strings.TrimSpace(sortBy) == "" {
+		return articles, nil
+	}
+	parsed, err := ParseKeys(sortBy)
+	if err != nil {
+		return nil, err
+	}
+
+	lengths := make([]int, len(articles))
+	if slices.ContainsFunc(parsed, func(key Key) bool { return key.Name == Length }) {
+		for i := range articles {
+			lengths[i] = newsLength(articles[i])
+		}
+	}
+	indexes := make([]int, len(articles))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		first, second := indexes[i], indexes[j]
+		for _, key := range parsed {
+			comparison := compare(key.Name, articles[first], articles[second], lengths[first], lengths[second])
+			if comparison == 0 {
+				continue
+			}
+			if key.Descending {
+				return comparison > 0
+			}
+			return comparison < 0
+		}
+		return false
+	})
+
+	sorted := make([]news.News, len(articles))
+	for i, index := range indexes {
+		sorted[i] = articles[index]
+	}
+	copy(articles, sorted)
+	return articles, nil
+}
+
+// compare returns -1, 0 or 1 if the first news is less, equal or greater than the second by the key.
+// The titles and the sources are compared without the case.
+func compare(key string, first, second news.News, firstLength, secondLength int) int {
+	switch key {
+	case Date:
+		return first.Date.Compare(second.Date)
+	case Title:
+		return strings.Compare(strings.ToLower(string(first.Title)), strings.ToLower(string(second.Title)))
+	case Source:
+		return strings.Compare(strings.ToLower(string(first.SourceName)), strings.ToLower(string(second.SourceName)))
+	case Relevance:
+		return cmp.Compare(first.Score, second.Score)
+	case Length:
+		return cmp.Compare(firstLength, secondLength)
+	}
+	return 0
+}
+
+// newsLength returns the number of the words of the title, the description and the content of the news.
+func newsLength(article news.News) int {
+	text := string(article.Title) + " " + string(article.Description) + " " + string(article.Content)
+	return len(strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }))
+}
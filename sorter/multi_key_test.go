@@ -0,0 +1,84 @@
+package sorter
+
+import (
+	"news-aggregator/entity/news"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMultiKeySorter_SortNews(t *testing.T) {
+	articles := []news.News{
+		{Title: "Kyiv", SourceName: "pravda", Date: time.Date(2023, 7, 2, 0, 0, 0, 0, time.UTC), Score: 1.5, Description: "One two three"},
+		{Title: "abc", SourceName: "BBC", Date: time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), Score: 2.5, Description: "One"},
+		{Title: "Lviv", SourceName: "pravda", Date: time.Date(2023, 7, 3, 0, 0, 0, 0, time.UTC), Score: 1.5, Description: "One two"},
+		{Title: "Bbc", SourceName: "bbc", Date: time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), Description: "One two three four"},
+		{Title: "Odesa", SourceName: "nbc", Date: time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), Score: 1.5},
+	}
+	multiKeySorter := MultiKeySorter{}
+
+	tests := []struct {
+		name          string
+		sortBy        string
+		want          []string
+		expectedError string
+	}{
+		{name: "empty parameter keeps the order", sortBy: "", want: []string{"Kyiv", "abc", "Lviv", "Bbc", "Odesa"}},
+		{name: "legacy ascending", sortBy: "ASC", want: []string{"abc", "Bbc", "Odesa", "Kyiv", "Lviv"}},
+		{name: "legacy descending", sortBy: "desc", want: []string{"Lviv", "Kyiv", "abc", "Bbc", "Odesa"}},
+		{name: "legacy relevance", sortBy: "relevance", want: []string{"abc", "Lviv", "Kyiv", "Odesa", "Bbc"}},
+		{name: "source and date", sortBy: "source:asc,date:desc", want: []string{"abc", "Bbc", "Odesa", "Lviv", "Kyiv"}},
+		{name: "source, date and title", sortBy: "source:asc, date:desc, title:asc", want: []string{"abc", "Bbc", "Odesa", "Lviv", "Kyiv"}},
+		{name: "title without case", sortBy: "title", want: []string{"abc", "Bbc", "Kyiv", "Lviv", "Odesa"}},
+		{name: "relevance is descending by default", sortBy: "relevance,title:desc", want: []string{"abc", "Odesa", "Lviv", "Kyiv", "Bbc"}},
+		{name: "length", sortBy: "length:desc", want: []string{"Bbc", "Kyiv", "Lviv", "abc", "Odesa"}},
+		{name: "stable for the equal keys", sortBy: "date:asc", want: []string{"abc", "Bbc", "Odesa", "Kyiv", "Lviv"}},
+		{name: "unknown key", sortBy: "source,author:asc", expectedError: `unknown sort key "author", supported keys: date, title, source, relevance, length`},
+		{name: "wrong direction", sortBy: "date:up", expectedError: `wrong sort direction "up" of the key date, must be asc or desc`},
+		{name: "repeated key", sortBy: "date,date:desc", expectedError: `sort key "date" is repeated`},
+		{name: "missing key", sortBy: "date,", expectedError: "wrong sorting parameter, the key is missing: date,"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := append([]news.News(nil), articles...)
+			sortedArticles, err := multiKeySorter.SortNews(input, tt.sortBy)
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var titles []string
+			for _, article := range sortedArticles {
+				titles = append(titles, string(article.Title))
+			}
+			if strings.Join(titles, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("expected news titles %v, got %v", tt.want, titles)
+			}
+		})
+	}
+}
+
+func TestSortsBy(t *testing.T) {
+	tests := []struct {
+		sortBy string
+		key    string
+		want   bool
+	}{
+		{sortBy: "Relevance", key: Relevance, want: true},
+		{sortBy: "source:asc,relevance:desc", key: Relevance, want: true},
+		{sortBy: "desc", key: Relevance, want: false},
+		{sortBy: "desc", key: Date, want: true},
+		{sortBy: "relevance,unknown", key: Relevance, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := SortsBy(tt.sortBy, tt.key); got != tt.want {
+			t.Errorf("SortsBy(%q, %q) = %v, want %v", tt.sortBy, tt.key, got, tt.want)
+		}
+	}
+}
@@ -5,6 +5,7 @@
 package client
 
 import (
+	context "context"
 	news "news-aggregator/entity/news"
 	source "news-aggregator/entity/source"
 	storage "news-aggregator/storage"
@@ -37,134 +38,134 @@ func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
 }
 
 // DeleteSourceByName mocks base method.
-func (m *MockStorage) DeleteSourceByName(arg0 source.Name) error {
+func (m *MockStorage) DeleteSourceByName(ctx context.Context, name source.Name) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSourceByName", arg0)
+	ret := m.ctrl.Call(m, "DeleteSourceByName", ctx, name)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteSourceByName indicates an expected call of DeleteSourceByName.
-func (mr *MockStorageMockRecorder) DeleteSourceByName(arg0 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) DeleteSourceByName(ctx, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSourceByName", reflect.TypeOf((*MockStorage)(nil).DeleteSourceByName), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSourceByName", reflect.TypeOf((*MockStorage)(nil).DeleteSourceByName), ctx, name)
 }
 
 // GetNews mocks base method.
-func (m *MockStorage) GetNews(path string) ([]news.News, error) {
+func (m *MockStorage) GetNews(ctx context.Context, path string) ([]news.News, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNews", path)
+	ret := m.ctrl.Call(m, "GetNews", ctx, path)
 	ret0, _ := ret[0].([]news.News)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetNews indicates an expected call of GetNews.
-func (mr *MockStorageMockRecorder) GetNews(path interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) GetNews(ctx, path interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNews", reflect.TypeOf((*MockStorage)(nil).GetNews), path)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNews", reflect.TypeOf((*MockStorage)(nil).GetNews), ctx, path)
 }
 
 // GetNewsBySourceName mocks base method.
-func (m *MockStorage) GetNewsBySourceName(sourceName source.Name, sourceStorage storage.Source) ([]news.News, error) {
+func (m *MockStorage) GetNewsBySourceName(ctx context.Context, sourceName source.Name, sourceStorage storage.Source) ([]news.News, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNewsBySourceName", sourceName, sourceStorage)
+	ret := m.ctrl.Call(m, "GetNewsBySourceName", ctx, sourceName, sourceStorage)
 	ret0, _ := ret[0].([]news.News)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetNewsBySourceName indicates an expected call of GetNewsBySourceName.
-func (mr *MockStorageMockRecorder) GetNewsBySourceName(sourceName, sourceStorage interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) GetNewsBySourceName(ctx, sourceName, sourceStorage interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNewsBySourceName", reflect.TypeOf((*MockStorage)(nil).GetNewsBySourceName), sourceName, sourceStorage)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNewsBySourceName", reflect.TypeOf((*MockStorage)(nil).GetNewsBySourceName), ctx, sourceName, sourceStorage)
 }
 
 // GetSourceByName mocks base method.
-func (m *MockStorage) GetSourceByName(arg0 source.Name) (source.Source, error) {
+func (m *MockStorage) GetSourceByName(ctx context.Context, name source.Name) (source.Source, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSourceByName", arg0)
+	ret := m.ctrl.Call(m, "GetSourceByName", ctx, name)
 	ret0, _ := ret[0].(source.Source)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSourceByName indicates an expected call of GetSourceByName.
-func (mr *MockStorageMockRecorder) GetSourceByName(arg0 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) GetSourceByName(ctx, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSourceByName", reflect.TypeOf((*MockStorage)(nil).GetSourceByName), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSourceByName", reflect.TypeOf((*MockStorage)(nil).GetSourceByName), ctx, name)
 }
 
 // GetSources mocks base method.
-func (m *MockStorage) GetSources() ([]source.Source, error) {
+func (m *MockStorage) GetSources(ctx context.Context) ([]source.Source, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSources")
+	ret := m.ctrl.Call(m, "GetSources", ctx)
 	ret0, _ := ret[0].([]source.Source)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSources indicates an expected call of GetSources.
-func (mr *MockStorageMockRecorder) GetSources() *gomock.Call {
+func (mr *MockStorageMockRecorder) GetSources(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSources", reflect.TypeOf((*MockStorage)(nil).GetSources))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSources", reflect.TypeOf((*MockStorage)(nil).GetSources), ctx)
 }
 
 // IsSourceExists mocks base method.
-func (m *MockStorage) IsSourceExists(arg0 source.Name) bool {
+func (m *MockStorage) IsSourceExists(ctx context.Context, name source.Name) bool {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsSourceExists", arg0)
+	ret := m.ctrl.Call(m, "IsSourceExists", ctx, name)
 	ret0, _ := ret[0].(bool)
 	return ret0
 }
 
 // IsSourceExists indicates an expected call of IsSourceExists.
-func (mr *MockStorageMockRecorder) IsSourceExists(arg0 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) IsSourceExists(ctx, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSourceExists", reflect.TypeOf((*MockStorage)(nil).IsSourceExists), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSourceExists", reflect.TypeOf((*MockStorage)(nil).IsSourceExists), ctx, name)
 }
 
 // SaveNews mocks base method.
-func (m *MockStorage) SaveNews(providedSource source.Source, news []news.News) (source.Source, error) {
+func (m *MockStorage) SaveNews(ctx context.Context, providedSource source.Source, news []news.News) (source.Source, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveNews", providedSource, news)
+	ret := m.ctrl.Call(m, "SaveNews", ctx, providedSource, news)
 	ret0, _ := ret[0].(source.Source)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SaveNews indicates an expected call of SaveNews.
-func (mr *MockStorageMockRecorder) SaveNews(providedSource, news interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) SaveNews(ctx, providedSource, news interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveNews", reflect.TypeOf((*MockStorage)(nil).SaveNews), providedSource, news)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveNews", reflect.TypeOf((*MockStorage)(nil).SaveNews), ctx, providedSource, news)
 }
 
 // SaveSource mocks base method.
-func (m *MockStorage) SaveSource(source source.Source) error {
+func (m *MockStorage) SaveSource(ctx context.Context, source source.Source) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveSource", source)
+	ret := m.ctrl.Call(m, "SaveSource", ctx, source)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveSource indicates an expected call of SaveSource.
-func (mr *MockStorageMockRecorder) SaveSource(source interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) SaveSource(ctx, source interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSource", reflect.TypeOf((*MockStorage)(nil).SaveSource), source)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSource", reflect.TypeOf((*MockStorage)(nil).SaveSource), ctx, source)
 }
 
 // UpdateSource mocks base method.
-func (m *MockStorage) UpdateSource(updatedSource source.Source, currentName string) error {
+func (m *MockStorage) UpdateSource(ctx context.Context, updatedSource source.Source, currentName string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSource", updatedSource, currentName)
+	ret := m.ctrl.Call(m, "UpdateSource", ctx, updatedSource, currentName)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateSource indicates an expected call of UpdateSource.
-func (mr *MockStorageMockRecorder) UpdateSource(updatedSource, currentName interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) UpdateSource(ctx, updatedSource, currentName interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSource", reflect.TypeOf((*MockStorage)(nil).UpdateSource), updatedSource, currentName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSource", reflect.TypeOf((*MockStorage)(nil).UpdateSource), ctx, updatedSource, currentName)
 }
 
 // MockNews is a mock of News interface.
@@ -191,48 +192,48 @@ func (m *MockNews) EXPECT() *MockNewsMockRecorder {
 }
 
 // GetNews mocks base method.
-func (m *MockNews) GetNews(path string) ([]news.News, error) {
+func (m *MockNews) GetNews(ctx context.Context, path string) ([]news.News, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNews", path)
+	ret := m.ctrl.Call(m, "GetNews", ctx, path)
 	ret0, _ := ret[0].([]news.News)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetNews indicates an expected call of GetNews.
-func (mr *MockNewsMockRecorder) GetNews(path interface{}) *gomock.Call {
+func (mr *MockNewsMockRecorder) GetNews(ctx, path interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNews", reflect.TypeOf((*MockNews)(nil).GetNews), path)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNews", reflect.TypeOf((*MockNews)(nil).GetNews), ctx, path)
 }
 
 // GetNewsBySourceName mocks base method.
-func (m *MockNews) GetNewsBySourceName(sourceName source.Name, sourceStorage storage.Source) ([]news.News, error) {
+func (m *MockNews) GetNewsBySourceName(ctx context.Context, sourceName source.Name, sourceStorage storage.Source) ([]news.News, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNewsBySourceName", sourceName, sourceStorage)
+	ret := m.ctrl.Call(m, "GetNewsBySourceName", ctx, sourceName, sourceStorage)
 	ret0, _ := ret[0].([]news.News)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetNewsBySourceName indicates an expected call of GetNewsBySourceName.
-func (mr *MockNewsMockRecorder) GetNewsBySourceName(sourceName, sourceStorage interface{}) *gomock.Call {
+func (mr *MockNewsMockRecorder) GetNewsBySourceName(ctx, sourceName, sourceStorage interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNewsBySourceName", reflect.TypeOf((*MockNews)(nil).GetNewsBySourceName), sourceName, sourceStorage)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNewsBySourceName", reflect.TypeOf((*MockNews)(nil).GetNewsBySourceName), ctx, sourceName, sourceStorage)
 }
 
 // SaveNews mocks base method.
-func (m *MockNews) SaveNews(providedSource source.Source, news []news.News) (source.Source, error) {
+func (m *MockNews) SaveNews(ctx context.Context, providedSource source.Source, news []news.News) (source.Source, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveNews", providedSource, news)
+	ret := m.ctrl.Call(m, "SaveNews", ctx, providedSource, news)
 	ret0, _ := ret[0].(source.Source)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SaveNews indicates an expected call of SaveNews.
-func (mr *MockNewsMockRecorder) SaveNews(providedSource, news interface{}) *gomock.Call {
+func (mr *MockNewsMockRecorder) SaveNews(ctx, providedSource, news interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveNews", reflect.TypeOf((*MockNews)(nil).SaveNews), providedSource, news)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveNews", reflect.TypeOf((*MockNews)(nil).SaveNews), ctx, providedSource, news)
 }
 
 // MockSource is a mock of Source interface.
@@ -259,87 +260,87 @@ func (m *MockSource) EXPECT() *MockSourceMockRecorder {
 }
 
 // DeleteSourceByName mocks base method.
-func (m *MockSource) DeleteSourceByName(arg0 source.Name) error {
+func (m *MockSource) DeleteSourceByName(ctx context.Context, name source.Name) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSourceByName", arg0)
+	ret := m.ctrl.Call(m, "DeleteSourceByName", ctx, name)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteSourceByName indicates an expected call of DeleteSourceByName.
-func (mr *MockSourceMockRecorder) DeleteSourceByName(arg0 interface{}) *gomock.Call {
+func (mr *MockSourceMockRecorder) DeleteSourceByName(ctx, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSourceByName", reflect.TypeOf((*MockSource)(nil).DeleteSourceByName), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSourceByName", reflect.TypeOf((*MockSource)(nil).DeleteSourceByName), ctx, name)
 }
 
 // GetSourceByName mocks base method.
-func (m *MockSource) GetSourceByName(arg0 source.Name) (source.Source, error) {
+func (m *MockSource) GetSourceByName(ctx context.Context, name source.Name) (source.Source, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSourceByName", arg0)
+	ret := m.ctrl.Call(m, "GetSourceByName", ctx, name)
 	ret0, _ := ret[0].(source.Source)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSourceByName indicates an expected call of GetSourceByName.
-func (mr *MockSourceMockRecorder) GetSourceByName(arg0 interface{}) *gomock.Call {
+func (mr *MockSourceMockRecorder) GetSourceByName(ctx, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSourceByName", reflect.TypeOf((*MockSource)(nil).GetSourceByName), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSourceByName", reflect.TypeOf((*MockSource)(nil).GetSourceByName), ctx, name)
 }
 
 // GetSources mocks base method.
-func (m *MockSource) GetSources() ([]source.Source, error) {
+func (m *MockSource) GetSources(ctx context.Context) ([]source.Source, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSources")
+	ret := m.ctrl.Call(m, "GetSources", ctx)
 	ret0, _ := ret[0].([]source.Source)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSources indicates an expected call of GetSources.
-func (mr *MockSourceMockRecorder) GetSources() *gomock.Call {
+func (mr *MockSourceMockRecorder) GetSources(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSources", reflect.TypeOf((*MockSource)(nil).GetSources))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSources", reflect.TypeOf((*MockSource)(nil).GetSources), ctx)
 }
 
 // IsSourceExists mocks base method.
-func (m *MockSource) IsSourceExists(arg0 source.Name) bool {
+func (m *MockSource) IsSourceExists(ctx context.Context, name source.Name) bool {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsSourceExists", arg0)
+	ret := m.ctrl.Call(m, "IsSourceExists", ctx, name)
 	ret0, _ := ret[0].(bool)
 	return ret0
 }
 
 // IsSourceExists indicates an expected call of IsSourceExists.
-func (mr *MockSourceMockRecorder) IsSourceExists(arg0 interface{}) *gomock.Call {
+func (mr *MockSourceMockRecorder) IsSourceExists(ctx, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSourceExists", reflect.TypeOf((*MockSource)(nil).IsSourceExists), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSourceExists", reflect.TypeOf((*MockSource)(nil).IsSourceExists), ctx, name)
 }
 
 // SaveSource mocks base method.
-func (m *MockSource) SaveSource(source source.Source) error {
+func (m *MockSource) SaveSource(ctx context.Context, source source.Source) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveSource", source)
+	ret := m.ctrl.Call(m, "SaveSource", ctx, source)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveSource indicates an expected call of SaveSource.
-func (mr *MockSourceMockRecorder) SaveSource(source interface{}) *gomock.Call {
+func (mr *MockSourceMockRecorder) SaveSource(ctx, source interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSource", reflect.TypeOf((*MockSource)(nil).SaveSource), source)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSource", reflect.TypeOf((*MockSource)(nil).SaveSource), ctx, source)
 }
 
 // UpdateSource mocks base method.
-func (m *MockSource) UpdateSource(updatedSource source.Source, currentName string) error {
+func (m *MockSource) UpdateSource(ctx context.Context, updatedSource source.Source, currentName string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSource", updatedSource, currentName)
+	ret := m.ctrl.Call(m, "UpdateSource", ctx, updatedSource, currentName)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateSource indicates an expected call of UpdateSource.
-func (mr *MockSourceMockRecorder) UpdateSource(updatedSource, currentName interface{}) *gomock.Call {
+func (mr *MockSourceMockRecorder) UpdateSource(ctx, updatedSource, currentName interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSource", reflect.TypeOf((*MockSource)(nil).UpdateSource), updatedSource, currentName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSource", reflect.TypeOf((*MockSource)(nil).UpdateSource), ctx, updatedSource, currentName)
 }
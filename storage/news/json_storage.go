@@ -1,12 +1,14 @@
 package news
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/sirupsen/logrus"
 	"news-aggregator/constant"
 	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
+	"news-aggregator/parser"
 	"news-aggregator/storage"
 	"os"
 	"path/filepath"
@@ -25,11 +27,16 @@ func NewJsonStorage(pathToStorage source.PathToFile) (storage.News, error) {
 }
 
 // SaveNews saves the provided news articles to the specified JSON file.
-func (jsonStorage *jsonStorage) SaveNews(currentSource source.Source, news []news.News) (source.Source, error) {
+// The articles with the same ID are saved only once.
+func (jsonStorage *jsonStorage) SaveNews(ctx context.Context, currentSource source.Source, articles []news.News) (source.Source, error) {
 	var jsonFilePath string
 	var jsonFile *os.File
 	var err error
 
+	if err := ctx.Err(); err != nil {
+		return source.Source{}, err
+	}
+
 	if currentSource.PathToFile != "" {
 		jsonFilePath = string(currentSource.PathToFile)
 
@@ -62,7 +69,7 @@ func (jsonStorage *jsonStorage) SaveNews(currentSource source.Source, news []new
 		}
 	}(jsonFile)
 
-	if err := json.NewEncoder(jsonFile).Encode(news); err != nil {
+	if err := json.NewEncoder(jsonFile).Encode(storedArticles(news.RemoveDuplicates(articles))); err != nil {
 		logrus.Error("Failed to encode articles to JSON file: ", err)
 		return source.Source{}, fmt.Errorf("failed to encode articles to JSON file")
 	}
@@ -72,10 +79,26 @@ func (jsonStorage *jsonStorage) SaveNews(currentSource source.Source, news []new
 	return currentSource, nil
 }
 
+// storedArticles returns the copies of the articles without the fields which are set only
+// for the response, like the relevance score and the story, so they are not saved to the JSON file.
+func storedArticles(articles []news.News) []news.News {
+	stored := make([]news.News, len(articles))
+	for i, article := range articles {
+		article.Score, article.Sources, article.Related = 0, nil, nil
+		stored[i] = article
+	}
+	return stored
+}
+
 // GetNews retrieves news articles from the specified JSON file.
-func (jsonStorage *jsonStorage) GetNews(jsonFilePath string) ([]news.News, error) {
+// Reading stops with the error of the context when the context is done.
+func (jsonStorage *jsonStorage) GetNews(ctx context.Context, jsonFilePath string) ([]news.News, error) {
 	var existingArticles []news.News
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	if _, err := os.Stat(jsonFilePath); err == nil {
 		jsonFile, err := os.Open(jsonFilePath)
 		if err != nil {
@@ -89,22 +112,29 @@ func (jsonStorage *jsonStorage) GetNews(jsonFilePath string) ([]news.News, error
 			}
 		}(jsonFile)
 
-		if err := json.NewDecoder(jsonFile).Decode(&existingArticles); err != nil {
+		if err := json.NewDecoder(parser.NewContextReader(ctx, jsonFile)).Decode(&existingArticles); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
 			logrus.Error("Failed to decode existing articles from JSON file: ", err)
 			return nil, fmt.Errorf("failed to decode existing articles from JSON file")
 		}
 	}
 
+	for i := range existingArticles {
+		existingArticles[i] = existingArticles[i].WithID()
+	}
+
 	return existingArticles, nil
 }
 
-func (jsonStorage *jsonStorage) GetNewsBySourceName(sourceName source.Name, sourceStorage storage.Source) ([]news.News, error) {
-	currentSource, err := sourceStorage.GetSourceByName(sourceName)
+func (jsonStorage *jsonStorage) GetNewsBySourceName(ctx context.Context, sourceName source.Name, sourceStorage storage.Source) ([]news.News, error) {
+	currentSource, err := sourceStorage.GetSourceByName(ctx, sourceName)
 	if err != nil {
 		logrus.Error("Failed to get currentSource by name: ", err)
 		return nil, err
 	}
-	receivedNews, err := jsonStorage.GetNews(string(currentSource.PathToFile))
+	receivedNews, err := jsonStorage.GetNews(ctx, string(currentSource.PathToFile))
 	if err != nil {
 		logrus.Error("Failed to get currentSource by path: ", err)
 		return nil, err
@@ -1,6 +1,7 @@
 package news
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/sirupsen/logrus"
 	"news-aggregator/entity/source"
@@ -51,7 +52,7 @@ func TestSaveNews(t *testing.T) {
 
 			logrus.Infof("Current source path: %s", currentSource.PathToFile)
 
-			_, err = jsonStorage.SaveNews(currentSource, tt.newsArticles)
+			_, err = jsonStorage.SaveNews(context.Background(), currentSource, tt.newsArticles)
 			if tt.expectError {
 				require.Error(t, err)
 				logrus.Infof("Expected error occurred: %s", err)
@@ -65,6 +66,24 @@ func TestSaveNews(t *testing.T) {
 	}
 }
 
+func TestSaveNews_WithoutViewFields(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "test_source.json")
+	jsonStorage, _ := NewJsonStorage(source.PathToFile(filePath))
+
+	articles := []news.News{{Title: "Test Article", Link: "http://example.com", Score: 1.5,
+		Sources: []source.Name{"bbc", "abc"}, Related: []news.ID{"related"}}}
+	_, err := jsonStorage.SaveNews(context.Background(), source.Source{Name: "test_source", PathToFile: source.PathToFile(filePath)}, articles)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "score")
+	assert.NotContains(t, string(content), "sources")
+	assert.NotContains(t, string(content), "related")
+	assert.Equal(t, 1.5, articles[0].Score)
+}
+
 func TestGetNews(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -126,7 +145,7 @@ func TestGetNews(t *testing.T) {
 			_, err = tt.setupFile(t, filePath)
 			require.NoError(t, err)
 
-			news, err := jsonStorage.GetNews(filePath)
+			news, err := jsonStorage.GetNews(context.Background(), filePath)
 			if tt.expectError {
 				require.Error(t, err)
 			} else {
@@ -136,3 +155,22 @@ func TestGetNews(t *testing.T) {
 		})
 	}
 }
+
+func TestGetNews_CancelledContext(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "test_source.json")
+	require.NoError(t, os.WriteFile(filePath, []byte(`[{"title":"Test News"}]`), 0644))
+	jsonStorage, err := NewJsonStorage(source.PathToFile(filePath))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = jsonStorage.GetNews(ctx, filePath)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = jsonStorage.SaveNews(ctx, source.Source{Name: "test", PathToFile: source.PathToFile(filePath)}, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	articles, err := jsonStorage.GetNews(context.Background(), filePath)
+	require.NoError(t, err)
+	assert.Len(t, articles, 1, "the news must not be overwritten after the context is cancelled")
+}
@@ -2,12 +2,14 @@ package source
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/sirupsen/logrus"
 	"io"
 	"news-aggregator/constant"
 	"news-aggregator/entity/source"
+	"news-aggregator/parser"
 	"news-aggregator/storage"
 	"os"
 	"path/filepath"
@@ -27,10 +29,10 @@ func NewJsonStorage(pathToStorage source.PathToFile) (storage.Source, error) {
 }
 
 // SaveSource load the input source to the storage
-func (storage *jsonStorage) SaveSource(source source.Source) error {
+func (storage *jsonStorage) SaveSource(ctx context.Context, source source.Source) error {
 	logrus.Info("jsonStorage: Starting to save the source to storage")
 
-	existingSources, err := storage.GetSources()
+	existingSources, err := storage.GetSources(ctx)
 	if err != nil && !os.IsNotExist(err) {
 		logrus.Error("jsonStorage: Failed to read existing sources: ", err)
 		return err
@@ -45,6 +47,9 @@ func (storage *jsonStorage) SaveSource(source source.Source) error {
 
 	existingSources = append(existingSources, source)
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	file, err := os.Create(string(storage.pathToStorage)) // Use os.Create to create or truncate the file
 	if err != nil {
 		logrus.Error("jsonStorage: Failed to create storage file: ", err)
@@ -67,8 +72,13 @@ func (storage *jsonStorage) SaveSource(source source.Source) error {
 	return nil
 }
 
-func (storage *jsonStorage) GetSources() ([]source.Source, error) {
+// GetSources returns the all sources from the JSON storage, reading stops with the error
+// of the context when the context is done.
+func (storage *jsonStorage) GetSources(ctx context.Context) ([]source.Source, error) {
 	logrus.Info("jsonStorage: Starting loading the existing sources from storage")
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	file, err := os.Open(string(storage.pathToStorage))
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -84,7 +94,7 @@ func (storage *jsonStorage) GetSources() ([]source.Source, error) {
 		}
 	}(file)
 
-	reader := bufio.NewReader(file)
+	reader := bufio.NewReader(parser.NewContextReader(ctx, file))
 	content, err := io.ReadAll(reader)
 	if err != nil {
 		logrus.Error("jsonStorage: Failed to read storage file: ", err)
@@ -102,10 +112,10 @@ func (storage *jsonStorage) GetSources() ([]source.Source, error) {
 }
 
 // DeleteSourceByName remove the source from JSON storage by the name of this source
-func (storage *jsonStorage) DeleteSourceByName(name source.Name) error {
+func (storage *jsonStorage) DeleteSourceByName(ctx context.Context, name source.Name) error {
 	var updatedSources []source.Source
 	found := false
-	definedSources, err := storage.GetSources()
+	definedSources, err := storage.GetSources(ctx)
 	if err != nil {
 		return err
 	}
@@ -113,6 +123,9 @@ func (storage *jsonStorage) DeleteSourceByName(name source.Name) error {
 		if strings.ToLower(string(currentSource.Name)) != strings.ToLower(string(name)) {
 			updatedSources = append(updatedSources, currentSource)
 		} else {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			found = true
 			directoryPath := filepath.Join(constant.PathToResources, strings.ToLower(string(name)))
 			err := os.RemoveAll(directoryPath)
@@ -148,8 +161,8 @@ func (storage *jsonStorage) DeleteSourceByName(name source.Name) error {
 	logrus.Info("jsonStorage: Source successfully deleted from storage")
 	return nil
 }
-func (storage *jsonStorage) IsSourceExists(name source.Name) bool {
-	sources, err := storage.GetSources()
+func (storage *jsonStorage) IsSourceExists(ctx context.Context, name source.Name) bool {
+	sources, err := storage.GetSources(ctx)
 	if err != nil {
 		logrus.Error("IsSourceExists: ", err)
 		return false
@@ -164,10 +177,10 @@ func (storage *jsonStorage) IsSourceExists(name source.Name) bool {
 	return false
 }
 
-func (storage *jsonStorage) GetSourceByName(name source.Name) (source.Source, error) {
+func (storage *jsonStorage) GetSourceByName(ctx context.Context, name source.Name) (source.Source, error) {
 	logrus.Info("jsonStorage: Starting to get source by name from storage")
 
-	sources, err := storage.GetSources()
+	sources, err := storage.GetSources(ctx)
 	if err != nil {
 		logrus.Error("jsonStorage: Failed to get sources: ", err)
 		return source.Source{}, err
@@ -184,10 +197,10 @@ func (storage *jsonStorage) GetSourceByName(name source.Name) (source.Source, er
 }
 
 // UpdateSource updates existing source in the JSON storage
-func (storage *jsonStorage) UpdateSource(updatedSource source.Source, currentName string) error {
+func (storage *jsonStorage) UpdateSource(ctx context.Context, updatedSource source.Source, currentName string) error {
 	logrus.Info("jsonStorage: Starting to update the source in storage")
 
-	existingSources, err := storage.GetSources()
+	existingSources, err := storage.GetSources(ctx)
 	if err != nil {
 		logrus.Error("jsonStorage: Failed to get sources: ", err)
 		return err
@@ -209,6 +222,9 @@ func (storage *jsonStorage) UpdateSource(updatedSource source.Source, currentNam
 		return fmt.Errorf("source with name '%s' not found", currentName)
 	}
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	file, err := os.Create(string(storage.pathToStorage))
 	if err != nil {
 		logrus.Error("jsonStorage: Failed to create storage file: ", err)
@@ -1,6 +1,7 @@
 package source
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -50,7 +51,7 @@ func TestIsSourceExists(t *testing.T) {
 			}
 
 			storage := &jsonStorage{pathToStorage: source.PathToFile(filePath)}
-			exists := storage.IsSourceExists(tt.input)
+			exists := storage.IsSourceExists(context.Background(), tt.input)
 			assert.Equal(t, tt.expected, exists)
 		})
 	}
@@ -107,7 +108,7 @@ func TestSaveSource(t *testing.T) {
 			}
 
 			storage := &jsonStorage{pathToStorage: source.PathToFile(filePath)}
-			err = storage.SaveSource(tt.newSource)
+			err = storage.SaveSource(context.Background(), tt.newSource)
 			if (err != nil) != tt.expectErr {
 				t.Fatalf("SaveSource() error = %v, wantErr %v", err, tt.expectErr)
 			}
@@ -116,7 +117,7 @@ func TestSaveSource(t *testing.T) {
 				return
 			}
 
-			sources, err := storage.GetSources()
+			sources, err := storage.GetSources(context.Background())
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -168,7 +169,7 @@ func TestDeleteSourceByName(t *testing.T) {
 			}
 
 			storage := &jsonStorage{pathToStorage: source.PathToFile(filePath)}
-			err = storage.DeleteSourceByName(source.Name(tt.inputName))
+			err = storage.DeleteSourceByName(context.Background(), source.Name(tt.inputName))
 			if (err != nil) != tt.expectErr {
 				t.Fatalf("DeleteSourceByName() error = %v, wantErr %v", err, tt.expectErr)
 			}
@@ -177,7 +178,7 @@ func TestDeleteSourceByName(t *testing.T) {
 				return
 			}
 
-			sources, err := storage.GetSources()
+			sources, err := storage.GetSources(context.Background())
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -229,7 +230,7 @@ func TestGetSourceByName(t *testing.T) {
 			}
 
 			storage := &jsonStorage{pathToStorage: source.PathToFile(filePath)}
-			source, err := storage.GetSourceByName(tt.inputName)
+			source, err := storage.GetSourceByName(context.Background(), tt.inputName)
 			if (err != nil) != tt.expectErr {
 				t.Fatalf("GetSourceByName() error = %v, wantErr %v", err, tt.expectErr)
 			}
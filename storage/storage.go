@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"context"
 	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
 )
@@ -26,26 +27,28 @@ func NewStorage(newsStorage News, sourceStorage Source) Storage {
 	}
 }
 
-// News is an implementation of the Storage for managing the news
+// News is an implementation of the Storage for managing the news.
+// The methods return the error of the context without reading or writing when the context is done.
 type News interface {
 	// SaveNews saves the news of provided source and return the entity of this source
-	SaveNews(providedSource source.Source, news []news.News) (source.Source, error)
+	SaveNews(ctx context.Context, providedSource source.Source, news []news.News) (source.Source, error)
 	// GetNews returns the slice of news from the provided path
-	GetNews(path string) ([]news.News, error)
+	GetNews(ctx context.Context, path string) ([]news.News, error)
 	// GetNewsBySourceName returns the slice of news by source name from the provided source storage
-	GetNewsBySourceName(sourceName source.Name, sourceStorage Source) ([]news.News, error)
+	GetNewsBySourceName(ctx context.Context, sourceName source.Name, sourceStorage Source) ([]news.News, error)
 }
 
-// Source is an implementation of the Storage for managing the sources
+// Source is an implementation of the Storage for managing the sources.
+// The methods return the error of the context without reading or writing when the context is done.
 type Source interface {
 	// SaveSource saves the provided source to the storage
-	SaveSource(source source.Source) error
+	SaveSource(ctx context.Context, source source.Source) error
 	// DeleteSourceByName removes the source by provided source's name
-	DeleteSourceByName(source.Name) error
+	DeleteSourceByName(ctx context.Context, name source.Name) error
 	// GetSources returns the all sources from the storage
-	GetSources() ([]source.Source, error)
+	GetSources(ctx context.Context) ([]source.Source, error)
 	// IsSourceExists check
-	IsSourceExists(source.Name) bool
-	GetSourceByName(source.Name) (source.Source, error)
-	UpdateSource(updatedSource source.Source, currentName string) error
+	IsSourceExists(ctx context.Context, name source.Name) bool
+	GetSourceByName(ctx context.Context, name source.Name) (source.Source, error)
+	UpdateSource(ctx context.Context, updatedSource source.Source, currentName string) error
 }
@@ -0,0 +1,14 @@
+// Package trending finds the emerging topics of the stored news.
+//
+// The titles and the descriptions of the news are normalized into the stemmed terms and the
+// phrases of two adjacent terms without the stopwords, in the language of the news. Every term
+// and phrase is counted once per news in the recent window and in the baseline window which
+// precedes it. The topics are ranked by the number of the recent news multiplied by the logarithm
+// of the growth of their share of the news against the baseline, so the frequent topics which
+// were rare before are ranked first. The topic which covers mostly the same news as the higher
+// ranked topic with the common term is dropped. Every topic has the sources which cover it and
+// the newest news about it as the samples.
+//
+// Service ranks the topics of the stored news of the sources selected by their names, tags or
+// patterns, it is used by the GET /trending endpoint and the trending command of the CLI.
+package trending
@@ -0,0 +1,70 @@
+package trending
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
+	"news-aggregator/storage"
+	"news-aggregator/validator"
+	"slices"
+	"time"
+)
+
+// ErrInvalidSources is returned when the selectors of the sources do not select any source.
+var ErrInvalidSources = errors.New("invalid sources")
+
+// Service ranks the topics of the news of the sources saved to the storage.
+type Service struct {
+	storage storage.Storage
+}
+
+// NewService returns the new instance of the Service over the storage.
+func NewService(storage storage.Storage) *Service {
+	return &Service{storage: storage}
+}
+
+// Trending returns the emerging topics of the stored news of the sources selected by their names,
+// tags like "tag:world" or patterns like "n*", or of all sources if the selectors are empty. Only the
+// sources of the storage type have the stored news, the news of the other sources are not ranked.
+// The error of the context is returned when the context is done before the news are read.
+func (service *Service) Trending(ctx context.Context, selectors []string, now time.Time, config Config) ([]Topic, error) {
+	sources, err := service.storage.GetSources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sources: %w", err)
+	}
+	if len(selectors) == 0 {
+		selectors = []string{validator.AllSources}
+	}
+	names, err := validator.SelectSources(selectors, sources)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSources, err)
+	}
+
+	var articles []news.News
+	for _, currentSource := range sources {
+		if !slices.Contains(names, string(currentSource.Name)) {
+			continue
+		}
+		if currentSource.SourceType != source.STORAGE {
+			logrus.Info("Trending: The source ", currentSource.Name, " has no stored news and is skipped")
+			continue
+		}
+		sourceNews, err := service.storage.GetNews(ctx, string(currentSource.PathToFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get news of the source %s: %w", currentSource.Name, err)
+		}
+		for _, article := range sourceNews {
+			if article.SourceName == "" {
+				article.SourceName = currentSource.Name
+			}
+			articles = append(articles, article.WithID())
+		}
+	}
+
+	topics := Rank(articles, now, config)
+	logrus.Info("Trending: Ranked ", len(topics), " topics of ", len(articles), " news of the sources: ", names)
+	return topics, nil
+}
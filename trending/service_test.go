@@ -0,0 +1,107 @@
+package trending
+
+import (
+	"context"
+	"errors"
+	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
+	client "news-aggregator/storage/mock_aggregator"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSources = []source.Source{
+	{Name: "nytimes", SourceType: source.STORAGE, PathToFile: "nytimes.json", Tags: []string{"usa"}},
+	{Name: "cbsnews", SourceType: source.STORAGE, PathToFile: "cbsnews.json", Tags: []string{"usa"}},
+	{Name: "pravda", SourceType: source.STORAGE, PathToFile: "pravda.json", Tags: []string{"ukraine"}},
+	{Name: "bbc", SourceType: source.RSS, PathToFile: "bbc.xml", Tags: []string{"usa"}},
+}
+
+func newsOf(sourceName source.Name) []news.News {
+	var result []news.News
+	for _, testArticle := range testArticles {
+		if testArticle.SourceName == sourceName {
+			testArticle.SourceName = ""
+			result = append(result, testArticle)
+		}
+	}
+	return result
+}
+
+func TestService_Trending(t *testing.T) {
+	tests := []struct {
+		name           string
+		selectors      []string
+		prepare        func(mockStorage *client.MockStorage)
+		expected       []string
+		expectedError  error
+		expectAnyError bool
+	}{
+		{
+			name:      "Sources of the tag",
+			selectors: []string{"tag:usa"},
+			prepare: func(mockStorage *client.MockStorage) {
+				mockStorage.EXPECT().GetNews(gomock.Any(), "nytimes.json").Return(newsOf("nytimes"), nil)
+				mockStorage.EXPECT().GetNews(gomock.Any(), "cbsnews.json").Return(newsOf("cbsnews"), nil)
+			},
+			expected: []string{"kamala harris"},
+		},
+		{
+			name:      "Source without the topic",
+			selectors: []string{"pravda"},
+			prepare: func(mockStorage *client.MockStorage) {
+				mockStorage.EXPECT().GetNews(gomock.Any(), "pravda.json").Return(newsOf("pravda"), nil)
+			},
+		},
+		{
+			name: "All sources by default",
+			prepare: func(mockStorage *client.MockStorage) {
+				mockStorage.EXPECT().GetNews(gomock.Any(), "nytimes.json").Return(newsOf("nytimes"), nil)
+				mockStorage.EXPECT().GetNews(gomock.Any(), "cbsnews.json").Return(newsOf("cbsnews"), nil)
+				mockStorage.EXPECT().GetNews(gomock.Any(), "pravda.json").Return(newsOf("pravda"), nil)
+			},
+			expected: []string{"kamala harris"},
+		},
+		{
+			name:          "Unknown source",
+			selectors:     []string{"unknown"},
+			prepare:       func(mockStorage *client.MockStorage) {},
+			expectedError: ErrInvalidSources,
+		},
+		{
+			name:      "Storage error",
+			selectors: []string{"nytimes"},
+			prepare: func(mockStorage *client.MockStorage) {
+				mockStorage.EXPECT().GetNews(gomock.Any(), "nytimes.json").Return(nil, errors.New("decode error"))
+			},
+			expectAnyError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStorage := client.NewMockStorage(ctrl)
+			mockStorage.EXPECT().GetSources(gomock.Any()).Return(testSources, nil)
+			tt.prepare(mockStorage)
+
+			topics, err := NewService(mockStorage).Trending(context.Background(), tt.selectors, testNow, DefaultConfig())
+			if tt.expectedError != nil || tt.expectAnyError {
+				assert.Error(t, err)
+				if tt.expectedError != nil {
+					assert.ErrorIs(t, err, tt.expectedError)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, phrases(topics))
+			for _, topic := range topics {
+				assert.NotContains(t, topic.Sources, source.Name(""), "the news must have the names of their sources")
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package trending
+
+import (
+	"errors"
+	"math"
+	"news-aggregator/analysis"
+	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
+	"news-aggregator/filter"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minimumWordLength is the number of the letters of the shortest word of the topics.
+const minimumWordLength = 3
+
+// phraseShare is the share of the news of the topic which must be the news of the other topic
+// with the common term to replace it, like the phrase which replaces its word.
+const phraseShare = 0.8
+
+// Config is the configuration of the ranking of the topics. Window is the duration of the
+// recent window before now and Baseline is the duration of the window before the recent one.
+// Limit is the maximum number of the topics, Samples is the maximum number of the news of
+// every topic and MinimumCount is the number of the recent news the topic must appear in.
+type Config struct {
+	Window       time.Duration
+	Baseline     time.Duration
+	Limit        int
+	Samples      int
+	MinimumCount int
+}
+
+// DefaultConfig returns the configuration which compares the last day with the week before it.
+func DefaultConfig() Config {
+	return Config{
+		Window:       24 * time.Hour,
+		Baseline:     7 * 24 * time.Hour,
+		Limit:        10,
+		Samples:      3,
+		MinimumCount: 3,
+	}
+}
+
+// ParseConfig returns the default configuration with the passed parameters, the empty
+// parameters are left default. The windows are the durations like 24h or 7d.
+func ParseConfig(window, baseline, limit, samples string) (Config, error) {
+	config := DefaultConfig()
+	var err error
+	if window != "" {
+		if config.Window, err = filter.ParseDuration(window); err != nil {
+			return config, errors.New("wrong window parameter, must be duration like 24h or 7d: " + window)
+		}
+	}
+	if baseline != "" {
+		if config.Baseline, err = filter.ParseDuration(baseline); err != nil {
+			return config, errors.New("wrong baseline parameter, must be duration like 24h or 7d: " + baseline)
+		}
+	}
+	if limit != "" {
+		if config.Limit, err = strconv.Atoi(limit); err != nil || config.Limit <= 0 {
+			return config, errors.New("wrong limit parameter, must be positive number: " + limit)
+		}
+	}
+	if samples != "" {
+		if config.Samples, err = strconv.Atoi(samples); err != nil || config.Samples < 0 {
+			return config, errors.New("wrong samples parameter, must be number from 0: " + samples)
+		}
+	}
+	return config, nil
+}
+
+// Topic is the emerging term or phrase of the news. Score is the rank of the topic, RecentCount
+// and BaselineCount are the numbers of the news with the topic in the recent and the baseline
+// windows. Sources are the sources of the recent news and Articles are the newest of them.
+type Topic struct {
+	Phrase        string        `json:"topic"`
+	Score         float64       `json:"score"`
+	RecentCount   int           `json:"recentCount"`
+	BaselineCount int           `json:"baselineCount"`
+	Sources       []source.Name `json:"sources"`
+	Articles      []news.News   `json:"articles"`
+}
+
+// feature is the term or the phrase of the news: the stems joined by the space and the words
+// as they are written in the news.
+type feature struct {
+	key     string
+	surface string
+}
+
+// statistics are the counts of the feature in the windows.
+type statistics struct {
+	terms    []string
+	recent   []int
+	baseline int
+	surfaces map[string]int
+}
+
+// candidate is the feature which is more frequent in the recent window with its score.
+type candidate struct {
+	key   string
+	stats *statistics
+	score float64
+}
+
+// Rank returns the emerging topics of the news published in the recent window before now
+// compared with the news of the baseline window, from the highest score. The news out of
+// both windows are ignored.
+func Rank(articles []news.News, now time.Time, config Config) []Topic {
+	recentStart := now.Add(-config.Window)
+	baselineStart := recentStart.Add(-config.Baseline)
+
+	var recentArticles []news.News
+	var baselineCount int
+	features := make(map[string]*statistics)
+	for _, article := range articles {
+		var recent bool
+		switch {
+		case article.Date.After(now) || article.Date.Before(baselineStart):
+			continue
+		case !article.Date.Before(recentStart):
+			recent = true
+		}
+		if recent {
+			recentArticles = append(recentArticles, article)
+		} else {
+			baselineCount++
+		}
+		for _, currentFeature := range articleFeatures(article) {
+			stats, exists := features[currentFeature.key]
+			if !exists {
+				stats = &statistics{terms: strings.Fields(currentFeature.key), surfaces: make(map[string]int)}
+				features[currentFeature.key] = stats
+			}
+			if recent {
+				stats.recent = append(stats.recent, len(recentArticles)-1)
+				stats.surfaces[currentFeature.surface]++
+			} else {
+				stats.baseline++
+			}
+		}
+	}
+	if len(recentArticles) == 0 {
+		return nil
+	}
+
+	candidates := scoreFeatures(features, len(recentArticles), baselineCount, config.MinimumCount)
+	var topics []Topic
+	var selected []candidate
+	for _, current := range candidates {
+		if len(topics) >= config.Limit {
+			break
+		}
+		current = expandToPhrase(current, candidates)
+		if coveredBySelected(current, selected) {
+			continue
+		}
+		selected = append(selected, current)
+		topics = append(topics, newTopic(current, recentArticles, config.Samples))
+	}
+	return topics
+}
+
+// scoreFeatures returns the features which appear in at least the minimum number of the recent
+// news and are more frequent in them than in the baseline news, from the highest score.
+func scoreFeatures(features map[string]*statistics, recentCount, baselineCount, minimumCount int) []candidate {
+	smoothing := 1 / float64(recentCount+baselineCount)
+	var candidates []candidate
+	for key, stats := range features {
+		if len(stats.recent) < max(minimumCount, 1) {
+			continue
+		}
+		recentShare := float64(len(stats.recent)) / float64(recentCount)
+		baselineShare := float64(stats.baseline) / float64(max(baselineCount, 1))
+		lift := (recentShare + smoothing) / (baselineShare + smoothing)
+		if lift <= 1 {
+			continue
+		}
+		candidates = append(candidates, candidate{key: key, stats: stats, score: float64(len(stats.recent)) * math.Log(lift)})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		if len(candidates[i].stats.terms) != len(candidates[j].stats.terms) {
+			return len(candidates[i].stats.terms) > len(candidates[j].stats.terms)
+		}
+		return candidates[i].key < candidates[j].key
+	})
+	return candidates
+}
+
+// expandToPhrase returns the most frequent phrase with the term of the candidate which appears
+// in almost all of its news, like "kamala harris" for "kamala", or the candidate itself.
+func expandToPhrase(current candidate, candidates []candidate) candidate {
+	if len(current.stats.terms) != 1 {
+		return current
+	}
+	expanded := current
+	for _, phrase := range candidates {
+		if len(phrase.stats.terms) < 2 || !sharesTerm(current.stats.terms, phrase.stats.terms) ||
+			float64(len(phrase.stats.recent)) < phraseShare*float64(len(current.stats.recent)) {
+			continue
+		}
+		if expanded.key == current.key || len(phrase.stats.recent) > len(expanded.stats.recent) {
+			expanded = phrase
+		}
+	}
+	return expanded
+}
+
+// coveredBySelected reports whether the candidate has the common term with the selected topic
+// and most of its news are the news of that topic.
+func coveredBySelected(current candidate, selected []candidate) bool {
+	for _, topic := range selected {
+		if !sharesTerm(current.stats.terms, topic.stats.terms) {
+			continue
+		}
+		topicArticles := make(map[int]struct{}, len(topic.stats.recent))
+		for _, index := range topic.stats.recent {
+			topicArticles[index] = struct{}{}
+		}
+		common := 0
+		for _, index := range current.stats.recent {
+			if _, exists := topicArticles[index]; exists {
+				common++
+			}
+		}
+		if float64(common) >= phraseShare*float64(len(current.stats.recent)) {
+			return true
+		}
+	}
+	return false
+}
+
+func sharesTerm(first, second []string) bool {
+	for _, term := range first {
+		for _, other := range second {
+			if term == other {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// newTopic returns the topic of the candidate with the most frequent spelling of its words,
+// the sorted names of the sources of its recent news and the newest of them as the samples.
+func newTopic(current candidate, recentArticles []news.News, samples int) Topic {
+	topic := Topic{
+		Phrase:        mostFrequent(current.stats.surfaces),
+		Score:         math.Round(current.score*1000) / 1000,
+		RecentCount:   len(current.stats.recent),
+		BaselineCount: current.stats.baseline,
+		Sources:       []source.Name{},
+		Articles:      []news.News{},
+	}
+
+	articles := make([]news.News, 0, len(current.stats.recent))
+	seenSources := make(map[source.Name]struct{})
+	for _, index := range current.stats.recent {
+		article := recentArticles[index]
+		articles = append(articles, article)
+		if _, exists := seenSources[article.SourceName]; !exists && article.SourceName != "" {
+			seenSources[article.SourceName] = struct{}{}
+			topic.Sources = append(topic.Sources, article.SourceName)
+		}
+	}
+	sort.Slice(topic.Sources, func(i, j int) bool { return topic.Sources[i] < topic.Sources[j] })
+	sort.SliceStable(articles, func(i, j int) bool { return articles[i].Date.After(articles[j].Date) })
+	if len(articles) > samples {
+		articles = articles[:samples]
+	}
+	topic.Articles = append(topic.Articles, articles...)
+	return topic
+}
+
+// mostFrequent returns the most frequent spelling, the first in the alphabetical order of the equally frequent ones.
+func mostFrequent(surfaces map[string]int) string {
+	var result string
+	for surface, count := range surfaces {
+		if count > surfaces[result] || count == surfaces[result] && surface < result {
+			result = surface
+		}
+	}
+	return result
+}
+
+// articleFeatures returns the unique terms and the phrases of two adjacent words of the title
+// and the description of the news in the language of the news. The stopwords, the numbers and
+// the short words are skipped and break the phrases.
+func articleFeatures(article news.News) []feature {
+	language := analysis.LanguageOf(article)
+	seen := make(map[string]struct{})
+	var features []feature
+	add := func(key, surface string) {
+		if _, exists := seen[key]; !exists {
+			seen[key] = struct{}{}
+			features = append(features, feature{key: key, surface: surface})
+		}
+	}
+	for _, text := range []string{string(article.Title), string(article.Description)} {
+		var previous *feature
+		for _, word := range analysis.Words(text) {
+			if !isTopicWord(language, word) {
+				previous = nil
+				continue
+			}
+			current := feature{key: analysis.Stem(language, word), surface: word}
+			add(current.key, current.surface)
+			if previous != nil && previous.key != current.key {
+				add(previous.key+" "+current.key, previous.surface+" "+current.surface)
+			}
+			previous = &current
+		}
+	}
+	return features
+}
+
+// isTopicWord reports whether the word is long enough, has the letters and is not the stopword.
+func isTopicWord(language analysis.Language, word string) bool {
+	if utf8.RuneCountInString(word) < minimumWordLength || analysis.IsStopword(language, word) {
+		return false
+	}
+	return strings.IndexFunc(word, unicode.IsLetter) >= 0
+}
@@ -0,0 +1,122 @@
+package trending
+
+import (
+	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2024, 7, 22, 12, 0, 0, 0, time.UTC)
+
+func article(title string, sourceName source.Name, hoursAgo int) news.News {
+	return news.News{
+		Title:      news.Title(title),
+		Link:       news.Link("https://example.com/" + title),
+		Date:       testNow.Add(-time.Duration(hoursAgo) * time.Hour),
+		SourceName: sourceName,
+		Language:   "en",
+	}
+}
+
+var testArticles = []news.News{
+	article("Kamala Harris leads the Democrats", "nytimes", 1),
+	article("Kamala Harris raises money", "cbsnews", 2),
+	article("Donors back Kamala Harris", "nytimes", 3),
+	article("Kamala Harris speaks in Delaware", "cbsnews", 30),
+	article("Weather in Kyiv", "pravda", 4),
+	article("Weather in Lviv", "pravda", 48),
+	article("Weather in Odesa", "pravda", 72),
+	article("Weather in Kharkiv", "pravda", 96),
+	article("Football results", "cbsnews", 5),
+	article("Olympic torch arrives in Paris", "nytimes", 50),
+	article("Stocks fall after the report", "cbsnews", 60),
+	article("Kamala Harris in the future", "nytimes", -1),
+	article("Kamala Harris in the past", "nytimes", 24*30),
+}
+
+func phrases(topics []Topic) []string {
+	var result []string
+	for _, topic := range topics {
+		result = append(result, topic.Phrase)
+	}
+	return result
+}
+
+func TestRank(t *testing.T) {
+	config := DefaultConfig()
+
+	topics := Rank(testArticles, testNow, config)
+	require.Equal(t, []string{"kamala harris"}, phrases(topics),
+		"the phrase must replace its words and the weather which is as frequent as before must not trend")
+	assert.Equal(t, 3, topics[0].RecentCount)
+	assert.Equal(t, 1, topics[0].BaselineCount)
+	assert.Equal(t, []source.Name{"cbsnews", "nytimes"}, topics[0].Sources)
+	require.Len(t, topics[0].Articles, 3)
+	assert.Equal(t, news.Title("Kamala Harris leads the Democrats"), topics[0].Articles[0].Title,
+		"the newest news must be the first sample")
+	assert.Greater(t, topics[0].Score, 0.0)
+
+	config.Samples = 1
+	config.MinimumCount = 1
+	config.Limit = 2
+	topics = Rank(testArticles, testNow, config)
+	require.Len(t, topics, 2)
+	assert.Equal(t, "kamala harris", topics[0].Phrase)
+	assert.Len(t, topics[0].Articles, 1)
+
+	assert.Empty(t, Rank(testArticles, testNow.Add(-24*365*time.Hour), DefaultConfig()),
+		"no topics must be ranked without the recent news")
+}
+
+func TestRank_Ukrainian(t *testing.T) {
+	articles := []news.News{
+		article("Повітряна тривога у Києві", "pravda", 1),
+		article("У Києві оголошено повітряну тривогу", "kashtan", 2),
+		article("Повітряна тривога триває", "pravda", 3),
+		article("Новини спорту", "pravda", 50),
+	}
+	for i := range articles {
+		articles[i].Language = "uk"
+	}
+
+	topics := Rank(articles, testNow, DefaultConfig())
+	require.NotEmpty(t, topics)
+	assert.Equal(t, "повітряна тривога", topics[0].Phrase, "the inflected forms must be counted as one topic")
+	assert.Equal(t, 3, topics[0].RecentCount)
+}
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name                             string
+		window, baseline, limit, samples string
+		expected                         Config
+		expectError                      bool
+	}{
+		{name: "Defaults", expected: DefaultConfig()},
+		{
+			name:   "All parameters",
+			window: "6h", baseline: "2w", limit: "5", samples: "0",
+			expected: Config{Window: 6 * time.Hour, Baseline: 14 * 24 * time.Hour, Limit: 5, Samples: 0, MinimumCount: 3},
+		},
+		{name: "Wrong window", window: "yesterday", expectError: true},
+		{name: "Negative baseline", baseline: "-1d", expectError: true},
+		{name: "Zero limit", limit: "0", expectError: true},
+		{name: "Wrong samples", samples: "many", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseConfig(tt.window, tt.baseline, tt.limit, tt.samples)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, config)
+		})
+	}
+}
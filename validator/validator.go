@@ -5,76 +5,218 @@ import (
 	"fmt"
 	"news-aggregator/constant"
 	"news-aggregator/entity/source"
+	"news-aggregator/filter"
+	"path"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"slices"
 )
 
+// AllSources is the selector of all sources of the storage.
+const AllSources = "all"
+
+// TagPrefix is the prefix of the selector of the sources with the tag, like "tag:world".
+const TagPrefix = "tag:"
+
+// globCharacters are the characters which make the selector the glob pattern of the names.
+const globCharacters = "*?["
+
+// tagForbiddenCharacters are the characters which cannot be used in the tags because
+// they separate the sources in the queries or make the glob patterns.
+const tagForbiddenCharacters = ",:*?[] \t\n"
+
 // ValidateSource checks if the provided list of news articles contains at least one news.
 // If the input slice is empty, the function will return false, indicating that there are no valid news sources.
+// The sources may be selected by "all", "tag:<tag>" or the glob patterns, see ResolveSources.
 func ValidateSource(sources []string) (bool, error) {
-	logrus.Info("Validator: Starting source validation for sources:", sources)
+	if _, err := ResolveSources(sources); err != nil {
+		return false, err
+	}
+	return true, nil
+}
 
+// ResolveSources returns the names of the sources of the storage selected by the selectors.
+// The selector is the name of the source, "all" for all sources, "tag:<tag>" for the sources
+// with the tag or the glob pattern of the names like "n*" or "[ab]bc". The names are returned
+// in the order of the selectors without the duplicates. The error lists the names and the tags
+// of the sources if no selector is passed or some selector does not match any source.
+func ResolveSources(selectors []string) ([]string, error) {
+	logrus.Info("Validator: Starting source validation for sources:", selectors)
 	storage, err := source.LoadExistingSourcesFromStorage(constant.PathToStorage)
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"path":  constant.PathToStorage,
 			"error": err,
 		}).Error("Validator: Failed to load existing sources from storage")
-		return false, err
+		return nil, err
 	}
-
-	var storageNames []string
-	for _, s := range storage {
-		storageNames = append(storageNames, string(s.Name))
+	names, err := resolveSources(selectors, storage)
+	if err != nil {
+		return nil, err
 	}
+	logrus.Info("Validator: Source validation successful:", names)
+	return names, nil
+}
+
+// SelectSources returns the names of the passed sources which are selected like by ResolveSources:
+// by their names, tags like "tag:world", patterns like "n*" or "all".
+func SelectSources(selectors []string, sources []source.Source) ([]string, error) {
+	return resolveSources(selectors, sources)
+}
 
-	for _, currentSource := range sources {
-		if !slices.Contains(storageNames, strings.ToLower(currentSource)) {
-			errMessage := fmt.Sprintf("Source %s is not valid. The program supports such news news:\n%s",
-				currentSource, strings.Join(storageNames, ", "))
+func resolveSources(selectors []string, storage []source.Source) ([]string, error) {
+	var names []string
+	var resolved []string
+	for _, currentSelector := range selectors {
+		currentSelector = strings.ToLower(strings.TrimSpace(currentSelector))
+		if currentSelector == "" {
+			continue
+		}
+		matched, err := matchSources(currentSelector, storage)
+		if err != nil {
+			return nil, err
+		}
+		if len(matched) == 0 {
+			errMessage := fmt.Sprintf("Source %s is not valid. The program supports such news news:\n%s%s",
+				currentSelector, strings.Join(sourceNames(storage), ", "), describeTags(storage))
 			logrus.WithFields(logrus.Fields{
-				"current_source": currentSource,
-				"valid_sources":  storageNames,
+				"current_source": currentSelector,
+				"valid_sources":  sourceNames(storage),
 			}).Error("Validator: Invalid source")
-			return false, errors.New(errMessage)
+			return nil, errors.New(errMessage)
+		}
+		for _, name := range matched {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
 		}
+		resolved = append(resolved, currentSelector)
 	}
-	if len(sources) == 0 {
-		errMessage := fmt.Sprintf("Please, specify at least one news source. The program supports such news news:\n%s.",
-			strings.Join(storageNames, ", "))
+
+	if len(resolved) == 0 {
+		errMessage := fmt.Sprintf("Please, specify at least one news source. The program supports such news news:\n%s.%s",
+			strings.Join(sourceNames(storage), ", "), describeTags(storage))
 		logrus.WithFields(logrus.Fields{
-			"valid_sources": storageNames,
+			"valid_sources": sourceNames(storage),
 		}).Error("Validator: No sources specified")
-		return false, errors.New(errMessage)
+		return nil, errors.New(errMessage)
 	}
+	return names, nil
+}
 
-	logrus.Info("Validator: Source validation successful:", sources)
-	return true, nil
+// matchSources returns the names of the sources matched by the lowercase selector.
+func matchSources(currentSelector string, storage []source.Source) ([]string, error) {
+	var matched []string
+	for _, storedSource := range storage {
+		name := strings.ToLower(string(storedSource.Name))
+		var matches bool
+		switch {
+		case currentSelector == AllSources:
+			matches = true
+		case strings.HasPrefix(currentSelector, TagPrefix):
+			matches = slices.Contains(source.NormalizeTags(storedSource.Tags), strings.TrimPrefix(currentSelector, TagPrefix))
+		case strings.ContainsAny(currentSelector, globCharacters):
+			var err error
+			if matches, err = path.Match(currentSelector, name); err != nil {
+				return nil, fmt.Errorf("source pattern %s is not valid: %w", currentSelector, err)
+			}
+		default:
+			matches = name == currentSelector
+		}
+		if matches {
+			matched = append(matched, string(storedSource.Name))
+		}
+	}
+	return matched, nil
+}
+
+// ErrInvalidTag is returned for the tag which cannot be used in the queries.
+var ErrInvalidTag = errors.New("invalid tag")
+
+// ValidateTags checks that the tags are not empty and do not contain the separators of the queries.
+func ValidateTags(tags []string) error {
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" {
+			return fmt.Errorf("%w: tag is empty", ErrInvalidTag)
+		}
+		if strings.ContainsAny(strings.TrimSpace(tag), tagForbiddenCharacters) {
+			return fmt.Errorf("%w %q, the tags cannot contain spaces and the characters %q", ErrInvalidTag, tag, ",:*?[]")
+		}
+	}
+	return nil
+}
+
+func sourceNames(storage []source.Source) []string {
+	var names []string
+	for _, storedSource := range storage {
+		names = append(names, string(storedSource.Name))
+	}
+	return names
+}
+
+// describeTags returns the list of the tags of the sources for the error messages.
+func describeTags(storage []source.Source) string {
+	var tags []string
+	for _, storedSource := range storage {
+		for _, tag := range source.NormalizeTags(storedSource.Tags) {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	if len(tags) == 0 {
+		return ""
+	}
+	slices.Sort(tags)
+	return "\nThe sources are also selected by \"all\" or by such tags with the \"tag:\" prefix:\n" + strings.Join(tags, ", ")
+}
+
+// ErrUnknownSourceType is returned when no parser is registered for the type of the source.
+var ErrUnknownSourceType = errors.New("unknown source type")
+
+// ValidateSourceType checks if the source type is one of the registered types.
+// The returned error lists the registered types.
+func ValidateSourceType(sourceType source.Type, registeredTypes []source.Type) error {
+	if slices.Contains(registeredTypes, sourceType) {
+		return nil
+	}
+	typeNames := make([]string, 0, len(registeredTypes))
+	for _, registeredType := range registeredTypes {
+		typeNames = append(typeNames, string(registeredType))
+	}
+	logrus.WithFields(logrus.Fields{
+		"source_type":      sourceType,
+		"registered_types": typeNames,
+	}).Error("Validator: Unknown source type")
+	return fmt.Errorf("%w %q, the registered types are: %s", ErrUnknownSourceType, sourceType, strings.Join(typeNames, ", "))
 }
 
-// ValidateDate validates the provided start and end dates.
-// It returns an error if the start date is after the end date, otherwise, it returns nil.
-func ValidateDate(startDate, endDate string) (error, bool) {
+// ValidateDate validates the provided since and until bounds and the last window.
+// Either bound may be omitted to leave the range open. It returns an error if the bound
+// is not the date, the RFC3339 timestamp or the duration, if last is combined with since,
+// or if since is after until, otherwise, it reports whether any date was provided.
+func ValidateDate(since, until, last string) (error, bool) {
 	logrus.WithFields(logrus.Fields{
-		"start_date": startDate,
-		"end_date":   endDate,
+		"since": since,
+		"until": until,
+		"last":  last,
 	}).Info("Validator: Starting date validation")
 
-	if startDate == "" && endDate == "" {
+	if since == "" && until == "" && last == "" {
 		logrus.Info("Validator: No dates provided")
 		return nil, false
 	}
-	if startDate == "" || endDate == "" {
-		errMessage := "either both start date and end date must be provided, or neither"
+	if _, err := filter.ParseDateRange(since, until, last, time.Now()); err != nil {
 		logrus.WithFields(logrus.Fields{
-			"start_date": startDate,
-			"end_date":   endDate,
-		}).Error("Validator:", errMessage)
-		return errors.New(errMessage), false
+			"since": since,
+			"until": until,
+			"last":  last,
+		}).Error("Validator: ", err)
+		return err, false
 	}
 
-	logrus.Info("Validator: Date validation successful:", startDate, endDate)
+	logrus.Info("Validator: Date validation successful:", since, until, last)
 	return nil, true
 }
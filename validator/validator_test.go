@@ -1,7 +1,11 @@
 package validator
 
 import (
+	"errors"
 	"news-aggregator/constant"
+	"news-aggregator/entity/source"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -45,10 +49,99 @@ func TestCheckSource(t *testing.T) {
 	}
 }
 
+func TestResolveSources(t *testing.T) {
+	storage := []source.Source{
+		{Name: "bbc", Tags: []string{"World", "uk"}},
+		{Name: "nbc", Tags: []string{"world", "us"}},
+		{Name: "abc", Tags: []string{"us"}},
+		{Name: "pravda", Tags: []string{"ukraine"}},
+	}
+	tests := []struct {
+		name      string
+		selectors []string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "Exact names in any case",
+			selectors: []string{"BBC", " nbc "},
+			want:      []string{"bbc", "nbc"},
+		},
+		{
+			name:      "All sources",
+			selectors: []string{"all"},
+			want:      []string{"bbc", "nbc", "abc", "pravda"},
+		},
+		{
+			name:      "Sources with the tag",
+			selectors: []string{"tag:world"},
+			want:      []string{"bbc", "nbc"},
+		},
+		{
+			name:      "Glob patterns without the duplicates",
+			selectors: []string{"?bc", "[bp]*", "tag:us"},
+			want:      []string{"bbc", "nbc", "abc", "pravda"},
+		},
+		{
+			name:      "Empty selectors are ignored",
+			selectors: []string{"", "pravda"},
+			want:      []string{"pravda"},
+		},
+		{
+			name:      "Unknown name",
+			selectors: []string{"bbc", "cnn"},
+			wantErr:   true,
+		},
+		{
+			name:      "Unknown tag",
+			selectors: []string{"tag:sport"},
+			wantErr:   true,
+		},
+		{
+			name:      "Invalid pattern",
+			selectors: []string{"[b"},
+			wantErr:   true,
+		},
+		{
+			name:      "No selectors",
+			selectors: []string{""},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveSources(tt.selectors, storage)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveSources() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveSources() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	_, err := resolveSources([]string{"cnn"}, storage)
+	if err == nil || !strings.Contains(err.Error(), "uk, ukraine, us, world") {
+		t.Errorf("Actual error = %v, expected the list of the tags", err)
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	if err := ValidateTags([]string{"world", "україна", "tech-news"}); err != nil {
+		t.Errorf("Unexpected error for the valid tags: %v", err)
+	}
+	for _, tags := range [][]string{{""}, {"world,us"}, {"tag:world"}, {"wor*"}, {"two words"}} {
+		if err := ValidateTags(tags); err == nil {
+			t.Errorf("Expected error for the tags %q", tags)
+		}
+	}
+}
+
 func TestValidateDate(t *testing.T) {
 	type args struct {
-		startDate string
-		endDate   string
+		since string
+		until string
+		last  string
 	}
 	tests := []struct {
 		name        string
@@ -57,38 +150,133 @@ func TestValidateDate(t *testing.T) {
 		wantIsValid bool
 	}{
 		{
-			name: "Check data with only start date passed",
+			name:        "Check data without dates",
+			args:        args{},
+			wantError:   false,
+			wantIsValid: false,
+		},
+		{
+			name: "Check data with only since date passed",
+			args: args{
+				since: "2003-05-05",
+			},
+			wantError:   false,
+			wantIsValid: true,
+		},
+		{
+			name: "Check data with only until date passed",
 			args: args{
-				startDate: "2003-05-05",
+				until: "2003-05-05",
+			},
+			wantError:   false,
+			wantIsValid: true,
+		},
+		{
+			name: "Check data with two correct date passed",
+			args: args{
+				since: "2003-05-01",
+				until: "2003-05-05",
+			},
+			wantError:   false,
+			wantIsValid: true,
+		},
+		{
+			name: "Check data with the same since and until date",
+			args: args{
+				since: "2003-05-05",
+				until: "2003-05-05",
+			},
+			wantError:   false,
+			wantIsValid: true,
+		},
+		{
+			name: "Check data with RFC3339 timestamp and relative date",
+			args: args{
+				since: "2003-05-01T10:00:00+03:00",
+				until: "1d",
+			},
+			wantError:   false,
+			wantIsValid: true,
+		},
+		{
+			name: "Check data with last window",
+			args: args{
+				last: "24h",
+			},
+			wantError:   false,
+			wantIsValid: true,
+		},
+		{
+			name: "Check data with since after until",
+			args: args{
+				since: "2003-05-05",
+				until: "2003-05-01",
 			},
 			wantError:   true,
 			wantIsValid: false,
 		},
 		{
-			name: "Check data with only end date passed",
+			name: "Check data with invalid date",
 			args: args{
-				endDate: "2003-05-05",
+				since: "05/05/2003",
 			},
 			wantError:   true,
 			wantIsValid: false,
 		},
-
 		{
-			name: "Check data with two correct date passed",
+			name: "Check data with last and since",
 			args: args{
-				startDate: "2003-05-01",
-				endDate:   "2003-05-05",
+				since: "7d",
+				last:  "24h",
 			},
-			wantError:   false,
-			wantIsValid: true,
+			wantError:   true,
+			wantIsValid: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotError, gotBool := ValidateDate(tt.args.startDate, tt.args.endDate)
+			gotError, gotBool := ValidateDate(tt.args.since, tt.args.until, tt.args.last)
+
+			if gotError != nil != tt.wantError || gotBool != tt.wantIsValid {
+				t.Errorf("Actual error: %v, valid: %v, expected error: %v, valid: %v", gotError, gotBool, tt.wantError, tt.wantIsValid)
+			}
+		})
+	}
+}
+
+func TestValidateSourceType(t *testing.T) {
+	registeredTypes := []source.Type{source.JSON, source.RSS}
 
-			if gotError != nil != tt.wantError && gotBool != tt.wantIsValid {
-				t.Errorf("Actual error: %v, expected %v", gotError != nil, tt.wantIsValid)
+	tests := []struct {
+		name       string
+		sourceType source.Type
+		wantErr    bool
+	}{
+		{
+			name:       "Check registered type",
+			sourceType: source.RSS,
+			wantErr:    false,
+		},
+		{
+			name:       "Check unknown type",
+			sourceType: "ATOM",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSourceType(tt.sourceType, registeredTypes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateSourceType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			if !errors.Is(err, ErrUnknownSourceType) {
+				t.Errorf("ValidateSourceType() error = %v, want ErrUnknownSourceType", err)
+			}
+			if !strings.Contains(err.Error(), "JSON, RSS") {
+				t.Errorf("ValidateSourceType() error = %v, want the list of registered types", err)
 			}
 		})
 	}
@@ -1,21 +1,24 @@
 package feed
 
 import (
+	"bufio"
+	"context"
 	"fmt"
 	"github.com/sirupsen/logrus"
 	"io"
 	"net/http"
+	"net/url"
 	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
 	"news-aggregator/parser"
-	"os"
+	"news-aggregator/parser/charset"
 	"regexp"
 	"strings"
 )
 
 // GetRssFeedLink takes link of rss feed from the input site
-func GetRssFeedLink(url string) (string, error) {
-	resp, err := http.Get(url)
+func GetRssFeedLink(ctx context.Context, url string) (string, error) {
+	resp, err := get(ctx, url)
 	if err != nil || resp.StatusCode != http.StatusOK {
 		logrus.Error("GetRssFeedLink: RSS URL not found ", err)
 		return "", fmt.Errorf("rss url not found: %s", url)
@@ -27,7 +30,7 @@ func GetRssFeedLink(url string) (string, error) {
 		}
 	}(resp.Body)
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := io.ReadAll(parser.NewContextReader(ctx, resp.Body))
 	if err != nil {
 		logrus.Error("GetRssFeedLink: Failed to read page content ", err)
 		return "", err
@@ -60,12 +63,13 @@ func ExtractDomainName(url string) string {
 	return domain
 }
 
-// ParseRssFeed downloads the RSS feed and returns the parsed news
-func ParseRssFeed(rssURL, name string) ([]news.News, error) {
-	rssResponse, err := http.Get(rssURL)
+// ParseRssFeed downloads the RSS feed and returns the parsed news with the report of the parser.
+// The body of the response is passed to the parser while it is being downloaded.
+func ParseRssFeed(ctx context.Context, rssURL, name string) ([]news.News, parser.Report, error) {
+	rssResponse, err := get(ctx, rssURL)
 	if err != nil || rssResponse.StatusCode != http.StatusOK {
 		logrus.Error("Failed to download RSS feed: ", err)
-		return nil, fmt.Errorf("failed to download RSS feed")
+		return nil, parser.Report{}, fmt.Errorf("failed to download RSS feed")
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -75,29 +79,122 @@ func ParseRssFeed(rssURL, name string) ([]news.News, error) {
 		}
 	}(rssResponse.Body)
 
-	tempFile, err := os.CreateTemp("", "*.xml")
+	parsedNews, report, err := parser.Rss{}.Parse(ctx, charset.NewReader(rssResponse.Body, rssResponse.Header.Get("Content-Type")), source.Name(name))
 	if err != nil {
-		logrus.Error("Failed to create temporary file: ", err)
-		return nil, fmt.Errorf("failed to create temporary file")
+		logrus.Error("Failed to parse RSS feed: ", err)
+		return nil, report, fmt.Errorf("failed to parse RSS feed")
+	}
+
+	return parsedNews, report, nil
+}
+
+// get sends the GET request which is cancelled together with the passed context.
+func get(ctx context.Context, url string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return http.DefaultClient.Do(request)
+}
+
+// GetSitemapLinks returns the links of the sitemaps listed in the robots.txt of the site.
+func GetSitemapLinks(ctx context.Context, siteURL string) ([]string, error) {
+	parsedURL, err := url.Parse(siteURL)
+	if err != nil || parsedURL.Host == "" {
+		return nil, fmt.Errorf("invalid site url: %s", siteURL)
+	}
+	robotsURL := parsedURL.Scheme + "://" + parsedURL.Host + "/robots.txt"
+
+	resp, err := get(ctx, robotsURL)
+	if err != nil {
+		logrus.Error("GetSitemapLinks: robots.txt not found ", err)
+		return nil, fmt.Errorf("robots.txt not found: %s", robotsURL)
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logrus.Error("GetSitemapLinks: Error closing response body ", err)
+		}
+	}(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("robots.txt not found: %s", robotsURL)
+	}
+
+	var sitemapLinks []string
+	scanner := bufio.NewScanner(parser.NewContextReader(ctx, resp.Body))
+	for scanner.Scan() {
+		directive, value, found := strings.Cut(scanner.Text(), ":")
+		if found && strings.EqualFold(strings.TrimSpace(directive), "sitemap") {
+			sitemapLinks = append(sitemapLinks, strings.TrimSpace(value))
+		}
 	}
-	defer func(name string) {
-		err := os.Remove(name)
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	logrus.Info("GetSitemapLinks: Sitemaps found: ", sitemapLinks)
+	return sitemapLinks, nil
+}
+
+// ParseSitemap downloads the sitemap and returns the parsed news with the report of the parser.
+// The child sitemaps of the sitemap index are downloaded too.
+func ParseSitemap(ctx context.Context, sitemapURL, name string) ([]news.News, parser.Report, error) {
+	sitemapResponse, err := get(ctx, sitemapURL)
+	if err != nil {
+		logrus.Error("Failed to download sitemap: ", err)
+		return nil, parser.Report{}, fmt.Errorf("failed to download sitemap")
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
 		if err != nil {
 			logrus.Error(err)
 			return
 		}
-	}(tempFile.Name())
-
-	if _, err := io.Copy(tempFile, rssResponse.Body); err != nil {
-		logrus.Error("Failed to save RSS feed to temporary file: ", err)
-		return nil, fmt.Errorf("failed to save RSS feed")
+	}(sitemapResponse.Body)
+	if sitemapResponse.StatusCode != http.StatusOK {
+		logrus.Error("Failed to download sitemap: ", sitemapResponse.Status)
+		return nil, parser.Report{}, fmt.Errorf("failed to download sitemap")
 	}
 
-	parsedNews, err := parser.Rss{}.Parse(source.PathToFile(tempFile.Name()), source.Name(name))
+	parsedNews, report, err := parser.Sitemap{}.Parse(ctx, charset.NewReader(sitemapResponse.Body, sitemapResponse.Header.Get("Content-Type")), source.Name(name))
 	if err != nil {
-		logrus.Error("Failed to parse RSS feed: ", err)
-		return nil, fmt.Errorf("failed to parse RSS feed")
+		logrus.Error("Failed to parse sitemap: ", err)
+		return nil, report, fmt.Errorf("failed to parse sitemap")
 	}
 
-	return parsedNews, nil
+	return parsedNews, report, nil
+}
+
+// ParseSiteNews returns the news of the site from its RSS feed. The news sitemaps
+// listed in the robots.txt are used if the site does not have the RSS feed.
+// The reports of the parsers are merged into the returned one.
+func ParseSiteNews(ctx context.Context, siteURL, name string) ([]news.News, parser.Report, error) {
+	rssURL, err := GetRssFeedLink(ctx, siteURL)
+	if err == nil && rssURL != "" {
+		return ParseRssFeed(ctx, rssURL, name)
+	}
+
+	sitemapLinks, sitemapErr := GetSitemapLinks(ctx, siteURL)
+	if sitemapErr != nil || len(sitemapLinks) == 0 {
+		if err == nil {
+			err = fmt.Errorf("neither rss feed nor sitemap found: %s", siteURL)
+		}
+		return nil, parser.Report{}, err
+	}
+
+	var parsedNews []news.News
+	var report parser.Report
+	for _, sitemapLink := range sitemapLinks {
+		sitemapNews, sitemapReport, err := ParseSitemap(ctx, sitemapLink, name)
+		report.Merge(sitemapReport)
+		if err != nil {
+			logrus.Warn("ParseSiteNews: Skipping sitemap ", sitemapLink, ": ", err)
+			continue
+		}
+		parsedNews = append(parsedNews, sitemapNews...)
+	}
+	if len(parsedNews) == 0 {
+		return nil, report, fmt.Errorf("no news found in sitemaps of: %s", siteURL)
+	}
+	return parsedNews, report, nil
 }
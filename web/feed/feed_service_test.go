@@ -1,8 +1,15 @@
 package feed
 
 import (
-	"github.com/stretchr/testify/assert"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExtractDomainName(t *testing.T) {
@@ -24,3 +31,59 @@ func TestExtractDomainName(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSiteNews(t *testing.T) {
+	sitemap, err := os.ReadFile("../../mnt/resources/testdata/news_sitemap.xml")
+	require.NoError(t, err)
+	rss, err := os.ReadFile("../../mnt/resources/testdata/rich_rss.xml")
+	require.NoError(t, err)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/with-feed":
+			_, _ = w.Write([]byte(`<html><head><link rel="alternate" type="application/rss+xml" href="` + server.URL + `/rss.xml"></head></html>`))
+		case "/rss.xml":
+			_, _ = w.Write(rss)
+		case "/robots.txt":
+			_, _ = w.Write([]byte("User-agent: *\nDisallow: /admin\nSitemap: " + server.URL + "/news-sitemap.xml\n"))
+		case "/news-sitemap.xml":
+			_, _ = w.Write(sitemap)
+		default:
+			_, _ = w.Write([]byte("<html><head></head></html>"))
+		}
+	}))
+	defer server.Close()
+
+	got, _, err := ParseSiteNews(context.Background(), server.URL+"/with-feed", "site")
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+
+	got, _, err = ParseSiteNews(context.Background(), server.URL+"/without-feed", "site")
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, []string{"World", "Ukraine", "Politics"}, got[0].Categories)
+
+	links, err := GetSitemapLinks(context.Background(), server.URL+"/without-feed")
+	require.NoError(t, err)
+	assert.Equal(t, []string{server.URL + "/news-sitemap.xml"}, links)
+
+	_, _, err = ParseSiteNews(context.Background(), "", "site")
+	assert.Error(t, err)
+}
+
+func TestParseRssFeed_Charset(t *testing.T) {
+	rss, err := os.ReadFile("../../mnt/resources/testdata/windows1251_rss.xml")
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml; charset=windows-1251")
+		_, _ = w.Write(bytes.Replace(rss, []byte(` encoding="windows-1251"`), nil, 1))
+	}))
+	defer server.Close()
+
+	got, _, err := ParseRssFeed(context.Background(), server.URL, "pravda")
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "Новини України", string(got[0].Title))
+	assert.Equal(t, "Їжак і єнот", string(got[1].Title))
+}
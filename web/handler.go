@@ -1,6 +1,7 @@
 package main
 
 import (
+	"news-aggregator/collector"
 	"news-aggregator/storage"
 	"news-aggregator/web/news"
 	"news-aggregator/web/source"
@@ -19,9 +20,9 @@ type handler struct {
 }
 
 // NewHandler returns a new instance of the Handler interface
-func NewHandler(storage storage.Storage) Handler {
+func NewHandler(storage storage.Storage, parsers *collector.Parsers) Handler {
 	return &handler{
-		SourceHandler: source.NewSourceHandler(storage),
+		SourceHandler: source.NewSourceHandler(storage, parsers),
 		NewsHandler:   news.NewNewsHandler(storage),
 	}
 }
@@ -1,25 +1,32 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
+	"encoding/json"
 	"flag"
 	"github.com/sirupsen/logrus"
 	"net/http"
 	"news-aggregator/aggregator"
+	"news-aggregator/analysis"
+	"news-aggregator/cache"
 	"news-aggregator/client"
 	"news-aggregator/collector"
 	"news-aggregator/constant"
 	"news-aggregator/entity/source"
+	"news-aggregator/search"
 	"news-aggregator/storage"
 	newsStorage "news-aggregator/storage/news"
 	sourceStorage "news-aggregator/storage/source"
 	"path/filepath"
+	_ "time/tzdata"
 )
 
 func main() {
 
 	port := flag.String("port", constant.PORT, "port to listen on")
 	secretPath := flag.String("secret-path", "/etc/tls-secret", "Path to TLS Secret")
+	requestTimeout := flag.Duration("request-timeout", constant.RequestTimeout, "Maximum time of the handling of the request, 0 disables the timeout")
 	flag.Parse()
 
 	certPath := filepath.Join(*secretPath, "tls.crt")
@@ -37,6 +44,7 @@ func main() {
 	server := &http.Server{
 		Addr:      ":" + *port,
 		TLSConfig: tlsConfig,
+		Handler:   withRequestTimeout(http.DefaultServeMux, *requestTimeout),
 	}
 
 	newsJsonStorage, err := newsStorage.NewJsonStorage(source.PathToFile(constant.PathToResources))
@@ -48,15 +56,30 @@ func main() {
 		logrus.Fatal(err)
 	}
 
-	resourcesStorage := storage.NewStorage(newsJsonStorage, sourceJsonStorage)
+	synonyms, err := analysis.LoadSynonyms(constant.PathToSynonyms)
+	if err != nil {
+		logrus.Error("Failed to load synonyms: ", err)
+	}
+	searchIndex, err := search.Open(context.Background(), constant.PathToSearchIndex, storage.NewStorage(newsJsonStorage, sourceJsonStorage), synonyms)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	resourcesStorage := search.NewIndexedStorage(storage.NewStorage(newsJsonStorage, sourceJsonStorage), searchIndex)
 
-	newsCollector := collector.New(resourcesStorage)
+	parsers := collector.GetDefaultParsers()
+	sourceCache := cache.New(cache.DefaultConfig())
+	collectorConfig := collector.DefaultConfig()
+	collectorConfig.Cache = sourceCache
+	newsCollector := collector.NewWithConfig(resourcesStorage, parsers, collectorConfig)
 	newsAggregator := aggregator.New(newsCollector)
 
-	handler := NewHandler(resourcesStorage)
+	handler := NewHandler(resourcesStorage, parsers)
 
 	http.HandleFunc("GET /news", func(w http.ResponseWriter, r *http.Request) {
-		handler.GetNewsHandler().FetchNewsHandler(w, client.NewWebClient(*r, w, newsAggregator))
+		handler.GetNewsHandler().FetchNewsHandler(w, r, client.NewWebClient(*r, w, newsAggregator, searchIndex, synonyms))
+	})
+	http.HandleFunc("GET /trending", func(w http.ResponseWriter, r *http.Request) {
+		handler.GetNewsHandler().TrendingHandler(w, r)
 	})
 	http.HandleFunc("POST /sources", func(w http.ResponseWriter, r *http.Request) {
 		handler.GetSourceHandler().AddSourceHandler(w, r)
@@ -67,8 +90,23 @@ func main() {
 	http.HandleFunc("PUT /sources", func(w http.ResponseWriter, r *http.Request) {
 		handler.GetSourceHandler().UpdateSourceByName(w, r)
 	})
+	http.HandleFunc("PUT /sources/tags", func(w http.ResponseWriter, r *http.Request) {
+		handler.GetSourceHandler().UpdateSourceTagsHandler(w, r)
+	})
+	http.HandleFunc("GET /sources/opml", func(w http.ResponseWriter, r *http.Request) {
+		handler.GetSourceHandler().ExportOPMLHandler(w, r)
+	})
+	http.HandleFunc("POST /sources/opml", func(w http.ResponseWriter, r *http.Request) {
+		handler.GetSourceHandler().ImportOPMLHandler(w, r)
+	})
 	http.HandleFunc("GET /allSources", func(w http.ResponseWriter, r *http.Request) {
-		handler.GetSourceHandler().GetAllSources(w)
+		handler.GetSourceHandler().GetAllSources(w, r)
+	})
+	http.HandleFunc("GET /cache/stats", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(sourceCache.Stats()); err != nil {
+			logrus.Error("Failed to encode cache statistics: ", err)
+		}
 	})
 	logrus.Info("Starting server on: " + *port)
 
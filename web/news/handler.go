@@ -1,27 +1,52 @@
 package news
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"github.com/sirupsen/logrus"
 	"net/http"
 	"news-aggregator/client"
 	"news-aggregator/storage"
+	"news-aggregator/trending"
+	"strings"
+	"time"
 )
 
 type HandlerForNews struct {
-	service *Service
+	service  *Service
+	trending *trending.Service
+}
+
+// trendingResponse is the body of the response with the trending topics and their windows.
+type trendingResponse struct {
+	Window   string           `json:"window"`
+	Baseline string           `json:"baseline"`
+	Topics   []trending.Topic `json:"topics"`
 }
 
 // NewNewsHandler returns the new instance of the news handler
 func NewNewsHandler(storage storage.Storage) *HandlerForNews {
 	return &HandlerForNews{
-		service: NewService(storage),
+		service:  NewService(storage),
+		trending: trending.NewService(storage),
 	}
 }
 
-// FetchNewsHandler handles requests for fetching news.
-func (h *HandlerForNews) FetchNewsHandler(w http.ResponseWriter, client client.Client) {
+// FetchNewsHandler handles requests for fetching news. The fetching stops when the request is
+// cancelled or its deadline is exceeded, the exceeded deadline is answered with 504.
+func (h *HandlerForNews) FetchNewsHandler(w http.ResponseWriter, r *http.Request, client client.Client) {
 
-	news, err := client.FetchNews()
+	news, err := client.FetchNews(r.Context())
+	if errors.Is(err, context.Canceled) {
+		logrus.Info("Fetching of news is cancelled: ", err)
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		logrus.Error("Fetching of news timed out: ", err)
+		http.Error(w, "request timed out", http.StatusGatewayTimeout)
+		return
+	}
 	if err != nil {
 		logrus.Error("Failed to fetch news ", err)
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -29,3 +54,46 @@ func (h *HandlerForNews) FetchNewsHandler(w http.ResponseWriter, client client.C
 	}
 	client.Print(news)
 }
+
+// TrendingHandler handles requests for the trending topics of the stored news. The sources are
+// selected by the sources parameter like the news, the recent and the baseline windows are set by
+// the window and baseline parameters, the numbers of the topics and of their sample news by the
+// limit and samples parameters.
+func (h *HandlerForNews) TrendingHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	config, err := trending.ParseConfig(query.Get("window"), query.Get("baseline"), query.Get("limit"), query.Get("samples"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var selectors []string
+	if sources := query.Get("sources"); sources != "" {
+		selectors = strings.Split(sources, ",")
+	}
+
+	topics, err := h.trending.Trending(r.Context(), selectors, time.Now(), config)
+	if errors.Is(err, trending.ErrInvalidSources) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		logrus.Error("Ranking of trending topics timed out: ", err)
+		http.Error(w, "request timed out", http.StatusGatewayTimeout)
+		return
+	}
+	if err != nil {
+		logrus.Error("Failed to rank trending topics ", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if topics == nil {
+		topics = []trending.Topic{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	response := trendingResponse{Window: config.Window.String(), Baseline: config.Baseline.String(), Topics: topics}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logrus.Error("Failed to encode trending topics: ", err)
+	}
+}
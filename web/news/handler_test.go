@@ -1,16 +1,21 @@
 package news
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	client "news-aggregator/client/mock_aggregator"
 	"news-aggregator/entity/news"
+	"news-aggregator/entity/source"
 	storage "news-aggregator/storage/mock_aggregator"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFetchNewsHandler(t *testing.T) {
@@ -34,7 +39,7 @@ func TestFetchNewsHandler(t *testing.T) {
 					{Title: "Sample News 1", Description: "Description 1", Link: "http://example.com/1"},
 					{Title: "Sample News 2", Description: "Description 2", Link: "http://example.com/2"},
 				}
-				mockClient.EXPECT().FetchNews().Return(expectedNews, nil).Times(1)
+				mockClient.EXPECT().FetchNews(gomock.Any()).Return(expectedNews, nil).Times(1)
 				mockClient.EXPECT().Print(expectedNews).Times(1)
 			},
 			expectedStatus: http.StatusOK,
@@ -43,11 +48,27 @@ func TestFetchNewsHandler(t *testing.T) {
 		{
 			name: "Fetch News Error",
 			mockFetchNews: func() {
-				mockClient.EXPECT().FetchNews().Return(nil, errors.New("some error")).Times(1)
+				mockClient.EXPECT().FetchNews(gomock.Any()).Return(nil, errors.New("some error")).Times(1)
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   "some error\n",
 		},
+		{
+			name: "Fetch News Timeout",
+			mockFetchNews: func() {
+				mockClient.EXPECT().FetchNews(gomock.Any()).Return(nil, context.DeadlineExceeded).Times(1)
+			},
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedBody:   "request timed out\n",
+		},
+		{
+			name: "Fetch News Cancelled",
+			mockFetchNews: func() {
+				mockClient.EXPECT().FetchNews(gomock.Any()).Return(nil, context.Canceled).Times(1)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "",
+		},
 	}
 
 	for _, tt := range tests {
@@ -57,10 +78,103 @@ func TestFetchNewsHandler(t *testing.T) {
 			httptest.NewRequest(http.MethodGet, "/news", nil)
 			rec := httptest.NewRecorder()
 
-			handler.FetchNewsHandler(rec, mockClient)
+			handler.FetchNewsHandler(rec, httptest.NewRequest(http.MethodGet, "/news", nil), mockClient)
 
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 			assert.Equal(t, tt.expectedBody, rec.Body.String())
 		})
 	}
 }
+
+func TestTrendingHandler(t *testing.T) {
+	now := time.Now()
+	storedNews := []news.News{
+		{Title: "Air alert in Kyiv", Link: "http://example.com/1", Date: now.Add(-time.Hour), Language: "en"},
+		{Title: "Air alert in Lviv", Link: "http://example.com/2", Date: now.Add(-2 * time.Hour), Language: "en"},
+		{Title: "Air alert in Odesa", Link: "http://example.com/3", Date: now.Add(-3 * time.Hour), Language: "en"},
+		{Title: "Football results", Link: "http://example.com/4", Date: now.Add(-50 * time.Hour), Language: "en"},
+	}
+	sources := []source.Source{{Name: "pravda", SourceType: source.STORAGE, PathToFile: "pravda.json"}}
+
+	tests := []struct {
+		name           string
+		target         string
+		prepare        func(mockStorage *storage.MockStorage)
+		expectedStatus int
+		expectedTopics []string
+	}{
+		{
+			name:   "Success",
+			target: "/trending?sources=pravda&window=1d&samples=1",
+			prepare: func(mockStorage *storage.MockStorage) {
+				mockStorage.EXPECT().GetSources(gomock.Any()).Return(sources, nil)
+				mockStorage.EXPECT().GetNews(gomock.Any(), "pravda.json").Return(storedNews, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedTopics: []string{"air alert"},
+		},
+		{
+			name:   "No topics",
+			target: "/trending?window=1h",
+			prepare: func(mockStorage *storage.MockStorage) {
+				mockStorage.EXPECT().GetSources(gomock.Any()).Return(sources, nil)
+				mockStorage.EXPECT().GetNews(gomock.Any(), "pravda.json").Return(storedNews, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedTopics: []string{},
+		},
+		{
+			name:           "Wrong window",
+			target:         "/trending?window=yesterday",
+			prepare:        func(mockStorage *storage.MockStorage) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Unknown source",
+			target: "/trending?sources=unknown",
+			prepare: func(mockStorage *storage.MockStorage) {
+				mockStorage.EXPECT().GetSources(gomock.Any()).Return(sources, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Storage error",
+			target: "/trending",
+			prepare: func(mockStorage *storage.MockStorage) {
+				mockStorage.EXPECT().GetSources(gomock.Any()).Return(nil, errors.New("storage error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockStorage := storage.NewMockStorage(ctrl)
+			tt.prepare(mockStorage)
+			handler := NewNewsHandler(mockStorage)
+
+			rec := httptest.NewRecorder()
+			handler.TrendingHandler(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var response struct {
+				Topics []struct {
+					Topic    string      `json:"topic"`
+					Articles []news.News `json:"articles"`
+				} `json:"topics"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			topics := []string{}
+			for _, topic := range response.Topics {
+				topics = append(topics, topic.Topic)
+				assert.Len(t, topic.Articles, 1)
+			}
+			assert.Equal(t, tt.expectedTopics, topics)
+		})
+	}
+}
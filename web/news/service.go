@@ -1,10 +1,13 @@
 package news
 
 import (
+	"context"
 	"fmt"
 	"github.com/sirupsen/logrus"
+	"news-aggregator/collector"
 	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
+	"news-aggregator/fetcher"
 	"news-aggregator/storage"
 	"news-aggregator/web/feed"
 	"sync"
@@ -13,45 +16,53 @@ import (
 
 type Service struct {
 	storage storage.Storage
+	parsers *collector.Parsers
+	fetcher *fetcher.Fetcher
 }
 
 func NewService(storage storage.Storage) *Service {
 	return &Service{
 		storage: storage,
+		parsers: collector.GetDefaultParsers(),
+		fetcher: fetcher.New(fetcher.DefaultConfig()),
 	}
 }
 
 // SaveNews saves the news to the storage
-func (service Service) SaveNews(sourceEntity source.Source, parsedNews []news.News) (source.Source, error) {
+func (service Service) SaveNews(ctx context.Context, sourceEntity source.Source, parsedNews []news.News) (source.Source, error) {
 
-	existingNews, err := service.storage.GetNewsBySourceName(sourceEntity.Name, service.storage)
+	existingNews, err := service.storage.GetNewsBySourceName(ctx, sourceEntity.Name, service.storage)
 	if err != nil {
 		return source.Source{}, err
 	}
 
-	newArticles := newsUnification(parsedNews, existingNews)
-	if len(newArticles) == 0 {
-		logrus.Info("No new parsed news to add")
+	mergedNews, changed := news.MergeNews(existingNews, parsedNews)
+	if changed == 0 {
+		logrus.Info("No new or edited parsed news to save")
 		return sourceEntity, nil
 	}
 
-	existingNews = append(existingNews, newArticles...)
-
-	sourceEntity, err = service.storage.SaveNews(sourceEntity, existingNews)
+	sourceEntity, err = service.storage.SaveNews(ctx, sourceEntity, mergedNews)
+	if err != nil {
+		return source.Source{}, err
+	}
 
 	return sourceEntity, nil
 }
 
-// PeriodicallyUpdateNews updates news for all sources.
-func (service Service) PeriodicallyUpdateNews(newsUpdatePeriod time.Duration) error {
+// PeriodicallyUpdateNews updates news for all sources until the context is done.
+func (service Service) PeriodicallyUpdateNews(ctx context.Context, newsUpdatePeriod time.Duration) error {
 	ticker := time.NewTicker(newsUpdatePeriod)
 	defer ticker.Stop()
 
 	for {
 		select {
+		case <-ctx.Done():
+			logrus.Info("Periodic update of news stopped")
+			return ctx.Err()
 		case <-ticker.C:
 			logrus.Info("Starting periodic update of news")
-			sources, err := service.storage.GetSources()
+			sources, err := service.storage.GetSources(ctx)
 			if err != nil {
 				logrus.Error("Failed to retrieve sources: ", err)
 				return err
@@ -59,22 +70,37 @@ func (service Service) PeriodicallyUpdateNews(newsUpdatePeriod time.Duration) er
 
 			var wg sync.WaitGroup
 			errChan := make(chan error, len(sources))
+			mirroredSources := make(chan source.Source, len(sources))
 
 			for _, src := range sources {
 				wg.Add(1)
 				go func(src source.Source) {
 					defer wg.Done()
 					if src.SourceType == source.STORAGE {
-						err := updateSourceNews(src, service.storage)
+						err := updateSourceNews(ctx, src, service.storage)
 						if err != nil {
 							errChan <- fmt.Errorf("failed to update news for source: %s, %v", src.Name, err)
 						}
+					} else if src.URL != "" {
+						mirroredSource, updated, err := service.parsers.MirrorSource(ctx, service.fetcher, src)
+						if err != nil {
+							errChan <- fmt.Errorf("failed to fetch remote source: %s, %v", src.Name, err)
+						} else if updated {
+							mirroredSources <- mirroredSource
+						}
 					}
 				}(src)
 			}
 
 			wg.Wait()
 			close(errChan)
+			close(mirroredSources)
+
+			for mirroredSource := range mirroredSources {
+				if err := service.storage.UpdateSource(ctx, mirroredSource, string(mirroredSource.Name)); err != nil {
+					logrus.Error("Failed to save validators of source: ", err)
+				}
+			}
 
 			for err := range errChan {
 				logrus.Error(err)
@@ -86,36 +112,15 @@ func (service Service) PeriodicallyUpdateNews(newsUpdatePeriod time.Duration) er
 	}
 }
 
-// newsUnification checks whether there are articles from the new feed in the existing news, and if so, removes them
-func newsUnification(articles []news.News, existingArticles []news.News) []news.News {
-	existingTitles := make(map[string]struct{})
-	for _, existingArticle := range existingArticles {
-		existingTitles[existingArticle.Title.String()] = struct{}{}
-	}
-
-	var newArticles []news.News
-	for _, newArticle := range articles {
-		if _, exists := existingTitles[newArticle.Title.String()]; !exists {
-			newArticles = append(newArticles, newArticle)
-		}
-	}
-
-	return newArticles
-}
-
 // updateSourceNews updating the news of the input source
-func updateSourceNews(inputSource source.Source, storage storage.Storage) error {
-	rssURL, err := feed.GetRssFeedLink(string(inputSource.Link))
-	if err != nil {
-		return err
-	}
-
-	currentNews, err := feed.ParseRssFeed(rssURL, string(inputSource.Name))
+func updateSourceNews(ctx context.Context, inputSource source.Source, storage storage.Storage) error {
+	currentNews, report, err := feed.ParseSiteNews(ctx, string(inputSource.Link), string(inputSource.Name))
 	if err != nil {
 		return err
 	}
+	report.Log()
 
-	_, err = storage.SaveNews(inputSource, currentNews)
+	_, err = NewService(storage).SaveNews(ctx, inputSource, currentNews)
 	if err != nil {
 		return err
 	}
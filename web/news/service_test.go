@@ -1,10 +1,12 @@
 package news
 
 import (
+	"context"
 	"github.com/golang/mock/gomock"
 	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
 	storage "news-aggregator/storage/mock_aggregator"
+	"reflect"
 	"testing"
 )
 
@@ -23,20 +25,56 @@ func TestSaveNews(t *testing.T) {
 		{Title: news.Title("Old Title")},
 	}
 
-	mockStorage.EXPECT().GetNewsBySourceName(sourceEntity.Name, mockStorage).Return(existingNews, nil)
-	mockStorage.EXPECT().SaveNews(sourceEntity, append(existingNews, parsedNews...)).Return(sourceEntity, nil)
+	mockStorage.EXPECT().GetNewsBySourceName(gomock.Any(), sourceEntity.Name, mockStorage).Return(existingNews, nil)
+	mockStorage.EXPECT().SaveNews(gomock.Any(), sourceEntity, append(existingNews, parsedNews...)).Return(sourceEntity, nil)
 
 	service := Service{
 		storage: mockStorage,
 	}
 
-	updatedSource, err := service.SaveNews(sourceEntity, parsedNews)
+	updatedSource, err := service.SaveNews(context.Background(), sourceEntity, parsedNews)
 	if err != nil {
 		t.Errorf("SaveNews() error = %v", err)
 		return
 	}
 
-	if updatedSource != sourceEntity {
+	if !reflect.DeepEqual(updatedSource, sourceEntity) {
 		t.Errorf("SaveNews() = %v, want %v", updatedSource, sourceEntity)
 	}
 }
+
+func TestSaveNews_EditedArticle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := storage.NewMockStorage(ctrl)
+	sourceEntity := source.Source{Name: "TestSource"}
+	existingNews := []news.News{
+		news.News{Title: "Old headline", Link: "https://example.com/story?utm_source=rss"}.WithID(),
+		news.News{Title: "Other story", Link: "https://example.com/other"}.WithID(),
+	}
+	parsedNews := []news.News{
+		{Title: "Edited headline", Link: "https://example.com/story"},
+		{Title: "Other story", Link: "https://example.com/other"},
+	}
+
+	var saved []news.News
+	mockStorage.EXPECT().GetNewsBySourceName(gomock.Any(), sourceEntity.Name, mockStorage).Return(existingNews, nil).Times(2)
+	mockStorage.EXPECT().SaveNews(gomock.Any(), sourceEntity, gomock.Any()).
+		DoAndReturn(func(_ context.Context, savedSource source.Source, articles []news.News) (source.Source, error) {
+			saved = articles
+			return savedSource, nil
+		})
+
+	service := Service{storage: mockStorage}
+	if _, err := service.SaveNews(context.Background(), sourceEntity, parsedNews); err != nil {
+		t.Fatalf("SaveNews() error = %v", err)
+	}
+	if len(saved) != 2 || saved[0].Title != "Edited headline" || saved[0].ID != existingNews[0].ID {
+		t.Errorf("SaveNews() saved = %v, want the edited headline instead of the stored one", saved)
+	}
+
+	if _, err := service.SaveNews(context.Background(), sourceEntity, existingNews); err != nil {
+		t.Fatalf("SaveNews() error = %v", err)
+	}
+}
@@ -1,12 +1,16 @@
 package source
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"github.com/sirupsen/logrus"
 	"io"
 	"net/http"
+	"news-aggregator/collector"
 	"news-aggregator/entity/source"
 	"news-aggregator/storage"
+	"news-aggregator/validator"
 	"strings"
 )
 
@@ -14,10 +18,19 @@ type deleteSourceRequest struct {
 	Name string `json:"name"`
 }
 
+// AddSourceRequest is the request for adding the source. The source is the site with the RSS feed
+// if the type is empty or STORAGE, otherwise it is the remote file of the passed type.
 type AddSourceRequest struct {
-	Name string `json:"name"`
-	URL  string `json:"url"`
+	Name string   `json:"name"`
+	URL  string   `json:"url"`
+	Type string   `json:"type,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+}
+type updateSourceTagsRequest struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
 }
+
 type updateSourceRequest struct {
 	OldName string `json:"old_name"`
 	NewName string `json:"new_name"`
@@ -28,9 +41,11 @@ type HandlerForSources struct {
 	service *Service
 }
 
-func NewSourceHandler(storage storage.Storage) *HandlerForSources {
+// NewSourceHandler returns the new instance of the sources handler which accepts
+// the types of the sources registered in the parsers.
+func NewSourceHandler(storage storage.Storage, parsers *collector.Parsers) *HandlerForSources {
 	return &HandlerForSources{
-		service: NewService(storage),
+		service: NewService(storage, parsers),
 	}
 }
 
@@ -59,7 +74,7 @@ func (h *HandlerForSources) DeleteSourceByNameHandler(w http.ResponseWriter, r *
 
 	logrus.Infof("Request to delete source received: %s", request.Name)
 
-	err = h.service.DeleteSourceByName(source.Name(request.Name))
+	err = h.service.DeleteSourceByName(r.Context(), source.Name(request.Name))
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			logrus.Warnf("Source not found: %s", request.Name)
@@ -89,7 +104,11 @@ func (h *HandlerForSources) AddSourceHandler(w http.ResponseWriter, r *http.Requ
 	}
 	logrus.Info("AddSourceHandler: The URL from the request to add the source was successfully retrieved: ", requestBody.URL)
 
-	sourceName, err := h.service.SaveSource(requestBody)
+	sourceName, err := h.service.SaveSource(r.Context(), requestBody)
+	if errors.Is(err, validator.ErrUnknownSourceType) || errors.Is(err, validator.ErrInvalidTag) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -101,9 +120,45 @@ func (h *HandlerForSources) AddSourceHandler(w http.ResponseWriter, r *http.Requ
 	}
 }
 
+// UpdateSourceTagsHandler replaces the tags of the source and writes the updated source to the response.
+func (h *HandlerForSources) UpdateSourceTagsHandler(w http.ResponseWriter, r *http.Request) {
+	var request updateSourceTagsRequest
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logrus.Error("Failed to close request body: ", err)
+		}
+	}(r.Body)
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Name == "" {
+		logrus.Error("Invalid request body or name parameter is missing")
+		http.Error(w, "Invalid request body or name parameter is missing", http.StatusBadRequest)
+		return
+	}
+
+	updatedSource, err := h.service.UpdateSourceTags(r.Context(), request.Name, request.Tags)
+	switch {
+	case errors.Is(err, validator.ErrInvalidTag):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, ErrSourceNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(updatedSource); err != nil {
+		logrus.Error("Failed to write response for update of tags: ", err)
+	}
+}
+
 // GetAllSources returns the all sources and write him to the response
-func (h *HandlerForSources) GetAllSources(w http.ResponseWriter) {
-	sources, err := h.service.GetAllSources()
+func (h *HandlerForSources) GetAllSources(w http.ResponseWriter, r *http.Request) {
+	sources, err := h.service.GetAllSources(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -141,7 +196,7 @@ func (h *HandlerForSources) UpdateSourceByName(w http.ResponseWriter, r *http.Re
 
 	logrus.Infof("Request to update source received: %s", request.OldName)
 
-	err = h.service.UpdateSourceByName(request.OldName, request.NewName, request.URL)
+	err = h.service.UpdateSourceByName(r.Context(), request.OldName, request.NewName, request.URL)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -156,6 +211,43 @@ func (h *HandlerForSources) UpdateSourceByName(w http.ResponseWriter, r *http.Re
 	logrus.Info("Response for update source written successfully")
 }
 
+// ExportOPMLHandler writes the sources to the response as the OPML document.
+func (h *HandlerForSources) ExportOPMLHandler(w http.ResponseWriter, r *http.Request) {
+	var document bytes.Buffer
+	if err := h.service.ExportOPML(r.Context(), &document); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/x-opml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(document.Bytes()); err != nil {
+		logrus.Error("Failed to write response for export of sources: ", err)
+	}
+}
+
+// ImportOPMLHandler adds the sources of the OPML document from the request body
+// and writes the result of the import of each outline to the response.
+func (h *HandlerForSources) ImportOPMLHandler(w http.ResponseWriter, r *http.Request) {
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logrus.Error("Failed to close request body: ", err)
+		}
+	}(r.Body)
+
+	results, err := h.service.ImportOPML(r.Context(), r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(results); err != nil {
+		logrus.Error("Failed to write response for import of sources: ", err)
+	}
+}
+
 // get the URL of the source from the request
 func parseRequest(r *http.Request, requestBody *AddSourceRequest) error {
 	body, err := io.ReadAll(r.Body)
@@ -3,6 +3,7 @@ package source
 import (
 	"bou.ke/monkey"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,9 +11,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
+	"news-aggregator/collector"
 	"news-aggregator/entity/source"
 	storage "news-aggregator/storage/mock_aggregator"
+	"news-aggregator/validator"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -21,7 +25,7 @@ func TestDeleteSourceByNameHandler(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockStorage := storage.NewMockStorage(ctrl)
-	handler := NewSourceHandler(mockStorage)
+	handler := NewSourceHandler(mockStorage, collector.GetDefaultParsers())
 
 	tests := []struct {
 		name           string
@@ -36,7 +40,7 @@ func TestDeleteSourceByNameHandler(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectedBody:   "Source deleted successfully",
 			mockFunc: func() {
-				mockStorage.EXPECT().DeleteSourceByName(source.Name("ExistingSource")).Return(nil)
+				mockStorage.EXPECT().DeleteSourceByName(gomock.Any(), source.Name("ExistingSource")).Return(nil)
 			},
 		},
 		{
@@ -45,7 +49,7 @@ func TestDeleteSourceByNameHandler(t *testing.T) {
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   "Source not found",
 			mockFunc: func() {
-				mockStorage.EXPECT().DeleteSourceByName(source.Name("NonExistingSource")).Return(errors.New("source not found"))
+				mockStorage.EXPECT().DeleteSourceByName(gomock.Any(), source.Name("NonExistingSource")).Return(errors.New("source not found"))
 			},
 		},
 		{
@@ -83,10 +87,13 @@ func TestDeleteSourceByNameHandler(t *testing.T) {
 }
 
 // mockSaveSource mocks the SaveSource method
-func mockSaveSource(_ *Service, request AddSourceRequest) (source.Name, error) {
+func mockSaveSource(_ *Service, _ context.Context, request AddSourceRequest) (source.Name, error) {
 	if request.URL == "" {
 		return "", fmt.Errorf("passed url is empty")
 	}
+	if request.Type == "ATOM" {
+		return "", validator.ValidateSourceType(source.Type(request.Type), []source.Type{source.RSS})
+	}
 	if request.URL == "https://www.pravda.com.ua/" {
 		return "pravda", nil
 	}
@@ -98,11 +105,11 @@ func TestAddSourceHandler(t *testing.T) {
 	defer ctrl.Finish()
 	mockStorage := storage.NewMockStorage(ctrl)
 
-	service := NewService(mockStorage)
+	service := NewService(mockStorage, collector.GetDefaultParsers())
 	patch := monkey.PatchInstanceMethod(reflect.TypeOf(service), "SaveSource", mockSaveSource)
 	defer patch.Unpatch()
 
-	handler := NewSourceHandler(mockStorage)
+	handler := NewSourceHandler(mockStorage, collector.GetDefaultParsers())
 
 	tests := []struct {
 		name           string
@@ -128,6 +135,12 @@ func TestAddSourceHandler(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   "unknown error",
 		},
+		{
+			name:           "UnknownType",
+			requestBody:    AddSourceRequest{URL: "https://example.com/feed", Type: "ATOM"},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "unknown source type",
+		},
 	}
 
 	for _, tt := range tests {
@@ -150,12 +163,74 @@ func TestAddSourceHandler(t *testing.T) {
 	}
 }
 
+func TestUpdateSourceTagsHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStorage := storage.NewMockStorage(ctrl)
+	handler := NewSourceHandler(mockStorage, collector.GetDefaultParsers())
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockFunc       func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "ValidRequest",
+			requestBody: `{"name": "bbc", "tags": ["World", "uk"]}`,
+			mockFunc: func() {
+				mockStorage.EXPECT().GetSourceByName(gomock.Any(), source.Name("bbc")).Return(source.Source{Name: "bbc", SourceType: source.RSS}, nil)
+				mockStorage.EXPECT().UpdateSource(gomock.Any(), gomock.Any(), "bbc").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"name":"bbc","type":"RSS","tags":["world","uk"]}`,
+		},
+		{
+			name:           "MissingName",
+			requestBody:    `{"tags": ["world"]}`,
+			mockFunc:       func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "name parameter is missing",
+		},
+		{
+			name:           "InvalidTag",
+			requestBody:    `{"name": "bbc", "tags": ["world news"]}`,
+			mockFunc:       func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid tag",
+		},
+		{
+			name:        "UnknownSource",
+			requestBody: `{"name": "unknown", "tags": ["world"]}`,
+			mockFunc: func() {
+				mockStorage.EXPECT().GetSourceByName(gomock.Any(), source.Name("unknown")).Return(source.Source{}, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "source not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			req := httptest.NewRequest(http.MethodPut, "/sources/tags", strings.NewReader(tt.requestBody))
+			rr := httptest.NewRecorder()
+
+			handler.UpdateSourceTagsHandler(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
 func TestGetAllSources(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockService := storage.NewMockStorage(ctrl)
-	handler := NewSourceHandler(mockService)
+	handler := NewSourceHandler(mockService, collector.GetDefaultParsers())
 
 	tests := []struct {
 		name           string
@@ -166,7 +241,7 @@ func TestGetAllSources(t *testing.T) {
 		{
 			name: "SuccessfulFetch",
 			mockFunc: func() {
-				mockService.EXPECT().GetSources().Return([]source.Source{
+				mockService.EXPECT().GetSources(gomock.Any()).Return([]source.Source{
 					{Name: "Source1", SourceType: source.STORAGE},
 					{Name: "Source2", SourceType: source.STORAGE},
 				}, nil)
@@ -177,7 +252,7 @@ func TestGetAllSources(t *testing.T) {
 		{
 			name: "ServiceError",
 			mockFunc: func() {
-				mockService.EXPECT().GetSources().Return(nil, errors.New("internal server error"))
+				mockService.EXPECT().GetSources(gomock.Any()).Return(nil, errors.New("internal server error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   "internal server error",
@@ -190,7 +265,7 @@ func TestGetAllSources(t *testing.T) {
 
 			rr := httptest.NewRecorder()
 
-			handler.GetAllSources(rr)
+			handler.GetAllSources(rr, httptest.NewRequest(http.MethodGet, "/allSources", nil))
 
 			assert.Equal(t, tt.expectedStatus, rr.Code)
 		})
@@ -202,7 +277,7 @@ func TestGetAllSourcesWithWriteError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockService := storage.NewMockStorage(ctrl)
-	handler := NewSourceHandler(mockService)
+	handler := NewSourceHandler(mockService, collector.GetDefaultParsers())
 
 	tests := []struct {
 		name           string
@@ -213,7 +288,7 @@ func TestGetAllSourcesWithWriteError(t *testing.T) {
 		{
 			name: "WriteError",
 			mockFunc: func() {
-				mockService.EXPECT().GetSources().Return([]source.Source{
+				mockService.EXPECT().GetSources(gomock.Any()).Return([]source.Source{
 					{Name: "Source1", SourceType: source.STORAGE},
 					{Name: "Source2", SourceType: source.STORAGE},
 				}, nil)
@@ -230,7 +305,7 @@ func TestGetAllSourcesWithWriteError(t *testing.T) {
 			rr := httptest.NewRecorder()
 			writer := &CustomResponseWriter{ResponseWriter: rr, err: errors.New("write error")}
 
-			handler.GetAllSources(writer)
+			handler.GetAllSources(writer, httptest.NewRequest(http.MethodGet, "/allSources", nil))
 
 			assert.Equal(t, tt.expectedStatus, rr.Code)
 			assert.Empty(t, rr.Body.String())
@@ -247,3 +322,25 @@ type CustomResponseWriter struct {
 func (c *CustomResponseWriter) Write(b []byte) (int, error) {
 	return 0, c.err
 }
+
+func TestExportOPMLHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := storage.NewMockStorage(ctrl)
+	handler := NewSourceHandler(mockStorage, collector.GetDefaultParsers())
+
+	mockStorage.EXPECT().GetSources(gomock.Any()).Return([]source.Source{
+		{Name: "pravda", SourceType: source.STORAGE, Link: "https://www.pravda.com.ua/"},
+	}, nil)
+	rr := httptest.NewRecorder()
+	handler.ExportOPMLHandler(rr, httptest.NewRequest(http.MethodGet, "/sources/opml", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/x-opml; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Body.String(), `<outline text="pravda" title="pravda" htmlUrl="https://www.pravda.com.ua/"></outline>`)
+
+	mockStorage.EXPECT().GetSources(gomock.Any()).Return(nil, errors.New("storage error"))
+	rr = httptest.NewRecorder()
+	handler.ExportOPMLHandler(rr, httptest.NewRequest(http.MethodGet, "/sources/opml", nil))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
@@ -1,29 +1,46 @@
 package source
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/sirupsen/logrus"
+	"io"
+	"news-aggregator/collector"
 	newsEntity "news-aggregator/entity/news"
 	"news-aggregator/entity/source"
+	"news-aggregator/fetcher"
+	"news-aggregator/opml"
 	"news-aggregator/storage"
+	"news-aggregator/validator"
 	"news-aggregator/web/feed"
 	"news-aggregator/web/news"
+	"strings"
+	"time"
 )
 
+// ErrSourceNotFound is returned when the source with the passed name does not exist.
+var ErrSourceNotFound = errors.New("source not found")
+
 type Service struct {
 	storage storage.Storage
+	parsers *collector.Parsers
+	fetcher *fetcher.Fetcher
 }
 
-// NewService creates new instance of the Service
-func NewService(storage storage.Storage) *Service {
+// NewService creates new instance of the Service.
+// The sources of the types other than STORAGE are accepted if their parsers are registered in the parsers.
+func NewService(storage storage.Storage, parsers *collector.Parsers) *Service {
 	return &Service{
 		storage: storage,
+		parsers: parsers,
+		fetcher: fetcher.New(fetcher.DefaultConfig()),
 	}
 }
 
 // DeleteSourceByName removes the source from storage by name.
-func (service *Service) DeleteSourceByName(name source.Name) error {
-	err := service.storage.DeleteSourceByName(name)
+func (service *Service) DeleteSourceByName(ctx context.Context, name source.Name) error {
+	err := service.storage.DeleteSourceByName(ctx, name)
 	if err != nil {
 		logrus.Error("Error deleting source:", err)
 		return err
@@ -31,10 +48,23 @@ func (service *Service) DeleteSourceByName(name source.Name) error {
 	return nil
 }
 
+// SourceInfo describes the source in the list of the sources.
+type SourceInfo struct {
+	Name source.Name `json:"name"`
+	Type source.Type `json:"type"`
+	Tags []string    `json:"tags,omitempty"`
+}
+
 // SaveSource processes the source URL and returns the source entity
-func (service *Service) SaveSource(request AddSourceRequest) (source.Name, error) {
+func (service *Service) SaveSource(ctx context.Context, request AddSourceRequest) (source.Name, error) {
+	if err := validator.ValidateTags(request.Tags); err != nil {
+		return "", err
+	}
+	if request.Type != "" && source.Type(request.Type) != source.STORAGE {
+		return service.saveRemoteSource(ctx, request)
+	}
 
-	parsedNews, err := service.GetParsedNews(request)
+	parsedNews, err := service.GetParsedNews(ctx, request)
 	if err != nil {
 		return "", err
 	}
@@ -43,15 +73,16 @@ func (service *Service) SaveSource(request AddSourceRequest) (source.Name, error
 		Name:       source.Name(request.Name),
 		SourceType: source.STORAGE,
 		Link:       source.Link(request.URL),
+		Tags:       source.NormalizeTags(request.Tags),
 	}
 	newsService := news.NewService(service.storage)
-	sourceEntity, err = newsService.SaveNews(sourceEntity, parsedNews)
+	sourceEntity, err = newsService.SaveNews(ctx, sourceEntity, parsedNews)
 	if err != nil {
 		return "", err
 	}
 
-	if !service.storage.IsSourceExists(sourceEntity.Name) {
-		err = service.storage.SaveSource(sourceEntity)
+	if !service.storage.IsSourceExists(ctx, sourceEntity.Name) {
+		err = service.storage.SaveSource(ctx, sourceEntity)
 		if err != nil {
 			return "", err
 		}
@@ -62,45 +93,93 @@ func (service *Service) SaveSource(request AddSourceRequest) (source.Name, error
 	return sourceEntity.Name, nil
 }
 
-func (service *Service) GetParsedNews(request AddSourceRequest) ([]newsEntity.News, error) {
+// saveRemoteSource saves the source whose file is fetched by the URL and parsed by the parser of the requested type.
+func (service *Service) saveRemoteSource(ctx context.Context, request AddSourceRequest) (source.Name, error) {
 	if request.URL == "" || request.Name == "" {
-		return nil, fmt.Errorf("passed url or name are empty")
+		return "", fmt.Errorf("passed url or name are empty")
+	}
+	if err := validator.ValidateSourceType(source.Type(request.Type), service.parsers.Types()); err != nil {
+		return "", err
+	}
+	if service.storage.IsSourceExists(ctx, source.Name(request.Name)) {
+		logrus.Info("Source already exists")
+		return source.Name(request.Name), nil
 	}
 
-	rssURL, err := feed.GetRssFeedLink(request.URL)
+	sourceEntity, _, err := service.parsers.MirrorSource(ctx, service.fetcher, source.Source{
+		Name:       source.Name(request.Name),
+		SourceType: source.Type(request.Type),
+		URL:        source.Link(request.URL),
+		Tags:       source.NormalizeTags(request.Tags),
+	})
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-	logrus.Info("Save: The URL of feed was successfully retrieved: ", rssURL)
 
-	parsedNews, err := feed.ParseRssFeed(rssURL, request.Name)
+	if err := service.storage.SaveSource(ctx, sourceEntity); err != nil {
+		return "", err
+	}
+	logrus.Info("Remote source added")
+	return sourceEntity.Name, nil
+}
+
+// GetParsedNews finds the RSS feed or the news sitemaps of the requested site and returns its parsed news.
+func (service *Service) GetParsedNews(ctx context.Context, request AddSourceRequest) ([]newsEntity.News, error) {
+	if request.URL == "" || request.Name == "" {
+		return nil, fmt.Errorf("passed url or name are empty")
+	}
+
+	parsedNews, report, err := feed.ParseSiteNews(ctx, request.URL, request.Name)
 	if err != nil {
 		return nil, err
 	}
+	report.Log()
 
 	return parsedNews, nil
 }
 
-// GetAllSources returns all source with Storage type in the system
-func (service *Service) GetAllSources() ([]source.Name, error) {
-	sources, err := service.storage.GetSources()
+// GetAllSources returns all source with Storage type in the system with their tags
+func (service *Service) GetAllSources(ctx context.Context) ([]SourceInfo, error) {
+	sources, err := service.storage.GetSources(ctx)
 	if err != nil {
 		logrus.Error("Error getting sources:", err)
 		return nil, err
 	}
 
-	var sourcesName []source.Name
+	var sourcesInfo []SourceInfo
 	for _, s := range sources {
 		if s.SourceType == source.STORAGE {
-			sourcesName = append(sourcesName, s.Name)
+			sourcesInfo = append(sourcesInfo, SourceInfo{Name: s.Name, Type: s.SourceType, Tags: source.NormalizeTags(s.Tags)})
 		}
+	}
+	return sourcesInfo, nil
+}
+
+// UpdateSourceTags replaces the tags of the source and returns the updated source.
+func (service *Service) UpdateSourceTags(ctx context.Context, name string, tags []string) (SourceInfo, error) {
+	if err := validator.ValidateTags(tags); err != nil {
+		return SourceInfo{}, err
+	}
+	currentSource, err := service.storage.GetSourceByName(ctx, source.Name(name))
+	if err != nil {
+		logrus.Error("Failed to retrieve sources: ", err)
+		return SourceInfo{}, err
+	}
+	if currentSource.Name == "" {
+		return SourceInfo{}, fmt.Errorf("%w: %s", ErrSourceNotFound, name)
+	}
 
+	currentSource.Tags = source.NormalizeTags(tags)
+	if err := service.storage.UpdateSource(ctx, currentSource, string(currentSource.Name)); err != nil {
+		logrus.Error("Failed to save updated sources: ", err)
+		return SourceInfo{}, err
 	}
-	return sourcesName, nil
+	logrus.Info("Tags of the source updated: ", currentSource.Name)
+	return SourceInfo{Name: currentSource.Name, Type: currentSource.SourceType, Tags: currentSource.Tags}, nil
 }
 
-func (service *Service) UpdateSourceByName(currentName, newName, newURL string) error {
-	currentSource, err := service.storage.GetSourceByName(source.Name(currentName))
+func (service *Service) UpdateSourceByName(ctx context.Context, currentName, newName, newURL string) error {
+	currentSource, err := service.storage.GetSourceByName(ctx, source.Name(currentName))
 	if err != nil {
 		logrus.Error("Failed to retrieve sources: ", err)
 		return err
@@ -120,13 +199,13 @@ func (service *Service) UpdateSourceByName(currentName, newName, newURL string)
 		currentSource.Link = source.Link(newURL)
 	}
 
-	err = service.storage.UpdateSource(currentSource, currentName)
+	err = service.storage.UpdateSource(ctx, currentSource, currentName)
 	if err != nil {
 		logrus.Error("Failed to save updated sources: ", err)
 		return err
 	}
 
-	parsedNews, err := service.GetParsedNews(AddSourceRequest{
+	parsedNews, err := service.GetParsedNews(ctx, AddSourceRequest{
 		Name: newName,
 		URL:  newURL,
 	})
@@ -135,7 +214,7 @@ func (service *Service) UpdateSourceByName(currentName, newName, newURL string)
 	}
 
 	newsService := news.NewService(service.storage)
-	_, err = newsService.SaveNews(currentSource, parsedNews)
+	_, err = newsService.SaveNews(ctx, currentSource, parsedNews)
 	if err != nil {
 		return err
 	}
@@ -143,3 +222,72 @@ func (service *Service) UpdateSourceByName(currentName, newName, newURL string)
 	logrus.Info("Sources updated successfully")
 	return nil
 }
+
+// ExportOPML writes the sources of the storage to the writer as the OPML document.
+func (service *Service) ExportOPML(ctx context.Context, writer io.Writer) error {
+	sources, err := service.storage.GetSources(ctx)
+	if err != nil {
+		logrus.Error("Failed to retrieve sources: ", err)
+		return err
+	}
+	return opml.FromSources(sources, time.Now()).Encode(writer)
+}
+
+// ImportOPML adds the sources of the outlines of the OPML document. The outlines whose name or link
+// are already used by the existing sources are skipped, and the failure of one outline
+// does not stop the import of the rest.
+func (service *Service) ImportOPML(ctx context.Context, reader io.Reader) ([]opml.Result, error) {
+	document, err := opml.Decode(reader)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := service.storage.GetSources(ctx)
+	if err != nil {
+		logrus.Error("Failed to retrieve sources: ", err)
+		return nil, err
+	}
+
+	knownSources := make(map[string]struct{})
+	for _, existingSource := range sources {
+		for _, key := range []string{string(existingSource.Name), string(existingSource.URL), string(existingSource.Link)} {
+			if key != "" {
+				knownSources[strings.ToLower(key)] = struct{}{}
+			}
+		}
+	}
+
+	var results []opml.Result
+	for _, outline := range document.Subscriptions() {
+		name := outline.SourceName()
+		if name == "" {
+			name = source.Name(feed.ExtractDomainName(outline.URL()))
+		}
+		result := opml.Result{Name: name, URL: outline.URL()}
+
+		_, nameExists := knownSources[strings.ToLower(string(name))]
+		_, linkExists := knownSources[strings.ToLower(outline.URL())]
+		if nameExists || linkExists {
+			result.Status = opml.Skipped
+			results = append(results, result)
+			continue
+		}
+
+		_, err := service.SaveSource(ctx, AddSourceRequest{
+			Name: string(name),
+			URL:  outline.URL(),
+			Type: string(outline.SourceType()),
+		})
+		if err != nil {
+			logrus.Errorf("Failed to import source %s: %v", name, err)
+			result.Status = opml.Failed
+			result.Error = err.Error()
+		} else {
+			result.Status = opml.Created
+			knownSources[strings.ToLower(string(name))] = struct{}{}
+			knownSources[strings.ToLower(outline.URL())] = struct{}{}
+		}
+		results = append(results, result)
+	}
+	logrus.Info("OPML import completed, outlines processed: ", len(results))
+	return results, nil
+}
@@ -1,12 +1,21 @@
 package source_test
 
 import (
+	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"news-aggregator/collector"
+	"news-aggregator/constant"
 	"news-aggregator/entity/news"
 	"news-aggregator/entity/source"
+	"news-aggregator/opml"
 	client "news-aggregator/storage/mock_aggregator"
+	"news-aggregator/validator"
 	sourceService "news-aggregator/web/source"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -29,7 +38,7 @@ func TestDeleteSourceByName(t *testing.T) {
 			name:       "Success",
 			sourceName: "example-source",
 			mockFunc: func() {
-				mockStorage.EXPECT().DeleteSourceByName(source.Name("example-source")).Return(nil)
+				mockStorage.EXPECT().DeleteSourceByName(gomock.Any(), source.Name("example-source")).Return(nil)
 			},
 			expectErr: false,
 		},
@@ -37,7 +46,7 @@ func TestDeleteSourceByName(t *testing.T) {
 			name:       "Failure",
 			sourceName: "non-existent-source",
 			mockFunc: func() {
-				mockStorage.EXPECT().DeleteSourceByName(source.Name("non-existent-source")).Return(errors.New("delete error"))
+				mockStorage.EXPECT().DeleteSourceByName(gomock.Any(), source.Name("non-existent-source")).Return(errors.New("delete error"))
 			},
 			expectErr: true,
 		},
@@ -46,8 +55,8 @@ func TestDeleteSourceByName(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc()
-			service := sourceService.NewService(mockStorage)
-			err := service.DeleteSourceByName(source.Name(tt.sourceName))
+			service := sourceService.NewService(mockStorage, collector.GetDefaultParsers())
+			err := service.DeleteSourceByName(context.Background(), source.Name(tt.sourceName))
 			if tt.expectErr {
 				assert.Error(t, err)
 			} else {
@@ -75,6 +84,7 @@ func TestSaveSource(t *testing.T) {
 		name       string
 		url        string
 		sourceName string
+		tags       []string
 		want       source.Name
 		wantErr    bool
 		setup      func()
@@ -95,12 +105,12 @@ func TestSaveSource(t *testing.T) {
 					},
 				}
 
-				mockStorage.EXPECT().IsSourceExists(gomock.Any()).Return(false).Times(1)
-				mockStorage.EXPECT().SaveSource(gomock.Any()).Return(nil).Times(1)
-				mockStorage.EXPECT().SaveNews(gomock.Any(), gomock.Any()).Return(
+				mockStorage.EXPECT().IsSourceExists(gomock.Any(), gomock.Any()).Return(false).Times(1)
+				mockStorage.EXPECT().SaveSource(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockStorage.EXPECT().SaveNews(gomock.Any(), gomock.Any(), gomock.Any()).Return(
 					source.Source{Name: "pravda"},
 					nil).Times(1)
-				mockStorage.EXPECT().GetNewsBySourceName(gomock.Any(), gomock.Any()).Return(returnsNews, nil).Times(1)
+				mockStorage.EXPECT().GetNewsBySourceName(gomock.Any(), gomock.Any(), gomock.Any()).Return(returnsNews, nil).Times(1)
 			},
 		},
 		{
@@ -121,6 +131,14 @@ func TestSaveSource(t *testing.T) {
 			setup: func() {
 			},
 		},
+		{
+			name:       "Add source with invalid tag",
+			url:        "https://www.pravda.com.ua/",
+			sourceName: "pravda",
+			tags:       []string{"world news"},
+			want:       "",
+			wantErr:    true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -129,9 +147,9 @@ func TestSaveSource(t *testing.T) {
 				tt.setup()
 			}
 
-			service := sourceService.NewService(mockStorage)
-			request := sourceService.AddSourceRequest{Name: tt.sourceName, URL: tt.url}
-			got, err := service.SaveSource(request)
+			service := sourceService.NewService(mockStorage, collector.GetDefaultParsers())
+			request := sourceService.AddSourceRequest{Name: tt.sourceName, URL: tt.url, Tags: tt.tags}
+			got, err := service.SaveSource(context.Background(), request)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("SaveSource() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -152,25 +170,28 @@ func TestGetAllSources(t *testing.T) {
 	tests := []struct {
 		name      string
 		mockFunc  func()
-		expected  []source.Name
+		expected  []sourceService.SourceInfo
 		expectErr bool
 	}{
 		{
 			name: "Success - Get all STORAGE sources",
 			mockFunc: func() {
-				mockStorage.EXPECT().GetSources().Return([]source.Source{
-					{Name: "source1", SourceType: source.STORAGE},
+				mockStorage.EXPECT().GetSources(gomock.Any()).Return([]source.Source{
+					{Name: "source1", SourceType: source.STORAGE, Tags: []string{"World", "us"}},
 					{Name: "source2", SourceType: source.STORAGE},
 					{Name: "source3", SourceType: source.JSON},
 				}, nil)
 			},
-			expected:  []source.Name{"source1", "source2"},
+			expected: []sourceService.SourceInfo{
+				{Name: "source1", Type: source.STORAGE, Tags: []string{"world", "us"}},
+				{Name: "source2", Type: source.STORAGE},
+			},
 			expectErr: false,
 		},
 		{
 			name: "Failure - Error retrieving sources",
 			mockFunc: func() {
-				mockStorage.EXPECT().GetSources().Return(nil, errors.New("storage error"))
+				mockStorage.EXPECT().GetSources(gomock.Any()).Return(nil, errors.New("storage error"))
 			},
 			expected:  nil,
 			expectErr: true,
@@ -178,7 +199,7 @@ func TestGetAllSources(t *testing.T) {
 		{
 			name: "Success - No STORAGE sources",
 			mockFunc: func() {
-				mockStorage.EXPECT().GetSources().Return([]source.Source{
+				mockStorage.EXPECT().GetSources(gomock.Any()).Return([]source.Source{
 					{Name: "source3", SourceType: source.JSON},
 				}, nil)
 			},
@@ -190,8 +211,8 @@ func TestGetAllSources(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockFunc()
-			service := sourceService.NewService(mockStorage)
-			sources, err := service.GetAllSources()
+			service := sourceService.NewService(mockStorage, collector.GetDefaultParsers())
+			sources, err := service.GetAllSources(context.Background())
 
 			if tt.expectErr {
 				assert.Error(t, err)
@@ -202,3 +223,190 @@ func TestGetAllSources(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateSourceTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := client.NewMockStorage(ctrl)
+
+	tests := []struct {
+		name       string
+		sourceName string
+		tags       []string
+		mockFunc   func()
+		expected   sourceService.SourceInfo
+		wantErr    error
+	}{
+		{
+			name:       "Tags are normalized and saved",
+			sourceName: "bbc",
+			tags:       []string{" World", "UK", "world"},
+			mockFunc: func() {
+				mockStorage.EXPECT().GetSourceByName(gomock.Any(), source.Name("bbc")).Return(source.Source{Name: "bbc", SourceType: source.RSS, Tags: []string{"news"}}, nil)
+				mockStorage.EXPECT().UpdateSource(gomock.Any(), source.Source{Name: "bbc", SourceType: source.RSS, Tags: []string{"world", "uk"}}, "bbc").Return(nil)
+			},
+			expected: sourceService.SourceInfo{Name: "bbc", Type: source.RSS, Tags: []string{"world", "uk"}},
+		},
+		{
+			name:       "Tags are removed",
+			sourceName: "bbc",
+			mockFunc: func() {
+				mockStorage.EXPECT().GetSourceByName(gomock.Any(), source.Name("bbc")).Return(source.Source{Name: "bbc", SourceType: source.RSS, Tags: []string{"news"}}, nil)
+				mockStorage.EXPECT().UpdateSource(gomock.Any(), source.Source{Name: "bbc", SourceType: source.RSS}, "bbc").Return(nil)
+			},
+			expected: sourceService.SourceInfo{Name: "bbc", Type: source.RSS},
+		},
+		{
+			name:       "Invalid tag",
+			sourceName: "bbc",
+			tags:       []string{"tag:world"},
+			mockFunc:   func() {},
+			wantErr:    validator.ErrInvalidTag,
+		},
+		{
+			name:       "Source not found",
+			sourceName: "unknown",
+			tags:       []string{"world"},
+			mockFunc: func() {
+				mockStorage.EXPECT().GetSourceByName(gomock.Any(), source.Name("unknown")).Return(source.Source{}, nil)
+			},
+			wantErr: sourceService.ErrSourceNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockFunc()
+			service := sourceService.NewService(mockStorage, collector.GetDefaultParsers())
+			got, err := service.UpdateSourceTags(context.Background(), tt.sourceName, tt.tags)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestSaveRemoteSource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := client.NewMockStorage(ctrl)
+
+	pathToResources := constant.PathToResources
+	constant.PathToResources = t.TempDir()
+	defer func() {
+		constant.PathToResources = pathToResources
+	}()
+
+	articles, err := os.ReadFile("../../mnt/resources/testdata/json_articles.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write(articles)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		request sourceService.AddSourceRequest
+		want    source.Name
+		wantErr error
+		setup   func()
+	}{
+		{
+			name:    "Add remote JSON source",
+			request: sourceService.AddSourceRequest{Name: "remote", URL: server.URL, Type: string(source.JSON)},
+			want:    "remote",
+			setup: func() {
+				mockStorage.EXPECT().IsSourceExists(gomock.Any(), source.Name("remote")).Return(false).Times(1)
+				mockStorage.EXPECT().SaveSource(gomock.Any(), source.Source{
+					Name:       "remote",
+					PathToFile: source.PathToFile(filepath.ToSlash(filepath.Join(constant.PathToResources, "remote", "remote.json"))),
+					SourceType: source.JSON,
+					URL:        source.Link(server.URL),
+					ETag:       `"v1"`,
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:    "Add source of unknown type",
+			request: sourceService.AddSourceRequest{Name: "remote", URL: server.URL, Type: "ATOM"},
+			wantErr: validator.ErrUnknownSourceType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setup != nil {
+				tt.setup()
+			}
+
+			service := sourceService.NewService(mockStorage, collector.GetDefaultParsers())
+			got, err := service.SaveSource(context.Background(), tt.request)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestImportOPML(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := client.NewMockStorage(ctrl)
+
+	pathToResources := constant.PathToResources
+	constant.PathToResources = t.TempDir()
+	defer func() {
+		constant.PathToResources = pathToResources
+	}()
+
+	feed, err := os.ReadFile("../../mnt/resources/testdata/rich_rss.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken.xml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(feed)
+	}))
+	defer server.Close()
+
+	document := `<opml version="2.0"><body>
+		<outline text="Pravda" htmlUrl="https://www.pravda.com.ua/"/>
+		<outline text="Rich Feed" type="rss" xmlUrl="` + server.URL + `/rss.xml"/>
+		<outline text="Rich Copy" type="rss" xmlUrl="` + server.URL + `/rss.xml"/>
+		<outline text="Broken" type="rss" xmlUrl="` + server.URL + `/broken.xml"/>
+	</body></opml>`
+
+	mockStorage.EXPECT().GetSources(gomock.Any()).Return([]source.Source{
+		{Name: "pravda", SourceType: source.STORAGE, Link: "https://www.pravda.com.ua/"},
+	}, nil)
+	mockStorage.EXPECT().IsSourceExists(gomock.Any(), gomock.Any()).Return(false).Times(2)
+	mockStorage.EXPECT().SaveSource(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	service := sourceService.NewService(mockStorage, collector.GetDefaultParsers())
+	results, err := service.ImportOPML(context.Background(), strings.NewReader(document))
+	assert.NoError(t, err)
+	assert.Len(t, results, 4)
+
+	var statuses []opml.Status
+	for _, result := range results {
+		statuses = append(statuses, result.Status)
+	}
+	assert.Equal(t, []opml.Status{opml.Skipped, opml.Created, opml.Skipped, opml.Failed}, statuses)
+	assert.Equal(t, source.Name("rich-feed"), results[1].Name)
+	assert.NotEmpty(t, results[3].Error)
+
+	_, err = service.ImportOPML(context.Background(), strings.NewReader("not an OPML document"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// withRequestTimeout returns the handler which passes the request to the next handler with the
+// context whose deadline is the timeout after the request is received, so the handlers stop
+// fetching and parsing the news of the request after the timeout. The timeout is not set if it
+// is not positive.
+func withRequestTimeout(next http.Handler, timeout time.Duration) http.Handler {
+	if timeout <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}